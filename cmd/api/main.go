@@ -93,9 +93,13 @@ func main() {
 		Queries:           queries,
 		DB:                db,
 		GoogleAPIKey:      cfg.GoogleAPIKey,
+		OpenAIAPIKey:      cfg.OpenAIAPIKey,
+		OpenAIBaseURL:     cfg.OpenAIBaseURL,
 		GCSService:        gcsService,
 		FileService:       fileService,
 		DocumentAIService: documentAIService,
+
+		RequireApprovedArtifacts: cfg.RequireApprovedArtifacts,
 	})
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -130,7 +134,7 @@ func logConfig(cfg *config.Config) {
 		return
 	}
 	log.Printf(
-		"Config loaded: port=%s db_url=%s project_id=%s pubsub_topic=%s file_store_name=%s gcs_bucket=%s signed_url_ttl=%s docai_location=%s docai_processor_id=%s google_api_key=%s jwt_secret=%s",
+		"Config loaded: port=%s db_url=%s project_id=%s pubsub_topic=%s file_store_name=%s gcs_bucket=%s signed_url_ttl=%s docai_location=%s docai_processor_id=%s google_api_key=%s openai_base_url=%s openai_api_key=%s jwt_secret=%s",
 		cfg.Port,
 		redact(cfg.DBURL),
 		cfg.GoogleProjectID,
@@ -141,6 +145,8 @@ func logConfig(cfg *config.Config) {
 		cfg.DocumentAILocation,
 		cfg.DocumentAIProcessorID,
 		redact(cfg.GoogleAPIKey),
+		cfg.OpenAIBaseURL,
+		redact(cfg.OpenAIAPIKey),
 		redact(cfg.JWTSecret),
 	)
 
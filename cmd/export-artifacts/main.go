@@ -0,0 +1,124 @@
+// Command export-artifacts writes generation artifacts as a JSONL dataset for
+// fine-tuning and regression evaluation.
+//
+//	go run ./cmd/export-artifacts -status APPROVED -type QUESTIONS -eval-results -out questions.jsonl
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/config"
+	"learning-core-api/internal/domain/artifacts"
+	"learning-core-api/internal/infra"
+)
+
+func main() {
+	var (
+		filter         artifacts.ExportFilter
+		from, to       string
+		documentID     string
+		promptTemplate string
+		promptVersion  int
+		out            string
+	)
+	flag.StringVar(&filter.GenerationType, "type", "", "generation type, e.g. QUESTIONS")
+	flag.StringVar(&filter.Status, "status", "", "artifact status, e.g. APPROVED")
+	flag.StringVar(&from, "from", "", "created at or after (RFC 3339 or YYYY-MM-DD)")
+	flag.StringVar(&to, "to", "", "created before (RFC 3339, or YYYY-MM-DD to include that day)")
+	flag.StringVar(&documentID, "document", "", "source document ID")
+	flag.StringVar(&promptTemplate, "prompt-template", "", "prompt template version ID")
+	flag.IntVar(&promptVersion, "prompt-version", 0, "prompt template version (with -type)")
+	flag.BoolVar(&filter.IncludeEvalResults, "eval-results", false, "join eval_results verdicts")
+	flag.BoolVar(&filter.IncludeReviews, "reviews", false, "join reviewer outcomes")
+	flag.IntVar(&filter.Limit, "limit", 0, "maximum number of records (0 exports all)")
+	flag.StringVar(&out, "out", "-", "output file, - for stdout")
+	flag.Parse()
+
+	if from != "" {
+		parsed, _, err := parseTime(from)
+		if err != nil {
+			log.Fatalf("invalid -from: %v", err)
+		}
+		filter.From = &parsed
+	}
+	if to != "" {
+		parsed, dateOnly, err := parseTime(to)
+		if err != nil {
+			log.Fatalf("invalid -to: %v", err)
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		filter.To = &parsed
+	}
+	if documentID != "" {
+		id, err := uuid.Parse(documentID)
+		if err != nil {
+			log.Fatalf("invalid -document: %v", err)
+		}
+		filter.DocumentID = &id
+	}
+	if promptTemplate != "" {
+		id, err := uuid.Parse(promptTemplate)
+		if err != nil {
+			log.Fatalf("invalid -prompt-template: %v", err)
+		}
+		filter.PromptTemplateID = &id
+	}
+	if promptVersion > 0 {
+		version := int32(promptVersion)
+		filter.PromptTemplateVersion = &version
+	}
+	if err := filter.Validate(); err != nil {
+		log.Fatalf("invalid filter: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.Load()
+	db, err := infra.ConnectDB(cfg.DBURL)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	var dest io.Writer = os.Stdout
+	if out != "-" {
+		file, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", out, err)
+		}
+		defer file.Close()
+		dest = file
+	}
+	writer := bufio.NewWriter(dest)
+
+	written, err := artifacts.NewService(db).Export(ctx, filter, writer)
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		log.Fatalf("export failed after %d records: %v", written, err)
+	}
+	log.Printf("Exported %d artifacts", written)
+}
+
+// parseTime accepts an RFC 3339 timestamp or a YYYY-MM-DD date (UTC).
+func parseTime(raw string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.DateOnly, raw); err == nil {
+		return parsed, true, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	return parsed, false, err
+}
@@ -13,12 +13,17 @@ type Config struct {
 	GoogleProjectID       string
 	PubSubTopicID         string
 	GoogleAPIKey          string
+	OpenAIAPIKey          string
+	OpenAIBaseURL         string
 	JWTSecret             string
 	FileStoreName         string
 	GCSBucketName         string
 	SignedURLTTL          string
 	DocumentAILocation    string
 	DocumentAIProcessorID string
+
+	// RequireApprovedArtifacts only lets artifacts approved in review be ingested
+	RequireApprovedArtifacts bool
 }
 
 func Load() *Config {
@@ -32,11 +37,15 @@ func Load() *Config {
 		GoogleProjectID:       os.Getenv("GOOGLE_PROJECT_ID"),
 		PubSubTopicID:         os.Getenv("PUBSUB_TOPIC_ID"),
 		GoogleAPIKey:          os.Getenv("GOOGLE_API_KEY"),
+		OpenAIAPIKey:          os.Getenv("OPENAI_API_KEY"),
+		OpenAIBaseURL:         os.Getenv("OPENAI_BASE_URL"),
 		JWTSecret:             os.Getenv("JWT_SECRET"),
 		FileStoreName:         os.Getenv("FILE_STORE_NAME"),
 		GCSBucketName:         os.Getenv("GCS_BUCKET_NAME"),
 		SignedURLTTL:          os.Getenv("GCS_SIGNED_URL_TTL"),
 		DocumentAILocation:    os.Getenv("DOCUMENT_AI_LOCATION"),
 		DocumentAIProcessorID: os.Getenv("DOCUMENT_AI_PROCESSOR_ID"),
+
+		RequireApprovedArtifacts: os.Getenv("REQUIRE_APPROVED_ARTIFACTS") == "true",
 	}
 }
@@ -0,0 +1,646 @@
+package artifacts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+// Change kinds reported by an artifact diff.
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+const (
+	// minItemSimilarity is the word overlap (Dice coefficient) two array
+	// items need to be treated as the same item when they have no stable key.
+	minItemSimilarity = 0.5
+	// maxPromptDiffLines bounds the line diff of prompt renders; longer
+	// prompts are reported as a single change.
+	maxPromptDiffLines = 2000
+)
+
+// itemKeyFields are the item fields tried, in order, as a stable key.
+var itemKeyFields = []string{"id", "key", "slug", "code"}
+
+// itemTextFields are the item fields tried, in order, as the text compared
+// when items have no stable key.
+var itemTextFields = []string{"question", "title", "name", "text", "prompt", "topic", "label"}
+
+// ArtifactDiff compares the output, prompt render and model params of two artifacts.
+// @Description Structured diff of two artifacts
+type ArtifactDiff struct {
+	Left             ArtifactRef      `json:"left"`
+	Right            ArtifactRef      `json:"right"`
+	SchemaTemplateID *uuid.UUID       `json:"schema_template_id,omitempty"` // schema the outputs were compared along
+	Identical        bool             `json:"identical"`
+	Collections      []CollectionDiff `json:"collections"`
+	Output           []DiffChange     `json:"output"`
+	PromptRender     *TextDiff        `json:"prompt_render,omitempty"`
+	ModelParams      []DiffChange     `json:"model_params"`
+}
+
+// ArtifactRef identifies one side of a diff.
+type ArtifactRef struct {
+	ID               uuid.UUID  `json:"id"`
+	GenerationType   *string    `json:"generation_type,omitempty" example:"QUESTIONS"`
+	Status           string     `json:"status" example:"READY"`
+	Model            *string    `json:"model,omitempty"`
+	PromptTemplateID *uuid.UUID `json:"prompt_template_id,omitempty"`
+	SchemaTemplateID *uuid.UUID `json:"schema_template_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// CollectionDiff summarises how the items of one array (questions, taxonomy
+// nodes, sections, ...) were matched and how many of them differ. MatchedBy is
+// the stable key field, or "similarity" when items were paired by text.
+type CollectionDiff struct {
+	Path      string `json:"path" example:"$.questions"`
+	MatchedBy string `json:"matched_by" example:"id"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+	Changed   int    `json:"changed"`
+	Unchanged int    `json:"unchanged"`
+}
+
+// DiffChange is one difference. Added and removed items carry the whole item;
+// changed values carry both sides.
+type DiffChange struct {
+	Path       string   `json:"path" example:"$.questions[id=q2].question"`
+	Kind       string   `json:"kind" example:"changed"`
+	Left       any      `json:"left,omitempty" swaggertype:"object"`
+	Right      any      `json:"right,omitempty" swaggertype:"object"`
+	Similarity *float64 `json:"similarity,omitempty" example:"0.8"` // set on items paired by text similarity
+}
+
+// TextDiff is a line diff of two texts. Lines holds only added and removed
+// lines; Line is the 1-based line number on the side the line belongs to.
+type TextDiff struct {
+	Equal bool       `json:"equal"`
+	Lines []LineDiff `json:"lines,omitempty"`
+}
+
+// LineDiff is one added or removed line.
+type LineDiff struct {
+	Kind string `json:"kind" example:"added"`
+	Line int    `json:"line" example:"12"`
+	Text string `json:"text"`
+}
+
+// DiffArtifacts compares the artifacts left and right. When requesterID is set
+// both artifacts must have been requested by that user; others are reported
+// as not found.
+func (s *Service) DiffArtifacts(ctx context.Context, leftID, rightID uuid.UUID, requesterID *uuid.UUID) (*ArtifactDiff, error) {
+	left, err := s.getRequestedArtifact(ctx, leftID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.getRequestedArtifact(ctx, rightID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare along the left schema; the right one is used when left has none.
+	var schema json.RawMessage
+	var schemaID *uuid.UUID
+	for _, id := range []uuid.NullUUID{left.SchemaTemplateID, right.SchemaTemplateID} {
+		if !id.Valid {
+			continue
+		}
+		tmpl, err := s.queries.GetSchemaTemplate(ctx, id.UUID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get schema template: %w", err)
+		}
+		schema, schemaID = tmpl.SchemaJson, &tmpl.ID
+		break
+	}
+
+	diff, err := diffArtifacts(left, right, schema)
+	if err != nil {
+		return nil, err
+	}
+	diff.SchemaTemplateID = schemaID
+	return diff, nil
+}
+
+func diffArtifacts(left, right store.Artifact, schema json.RawMessage) (*ArtifactDiff, error) {
+	d := &differ{collections: []CollectionDiff{}, changes: []DiffChange{}}
+	if len(schema) > 0 {
+		if err := json.Unmarshal(schema, &d.root); err != nil {
+			return nil, fmt.Errorf("failed to parse schema template: %w", err)
+		}
+	}
+
+	leftOutput, err := decodeNullJSON(left.OutputJson.RawMessage, left.OutputJson.Valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output_json of %s: %w", left.ID, err)
+	}
+	rightOutput, err := decodeNullJSON(right.OutputJson.RawMessage, right.OutputJson.Valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output_json of %s: %w", right.ID, err)
+	}
+	d.diff("$", d.root, leftOutput, rightOutput)
+
+	leftParams, err := decodeNullJSON(left.ModelParams.RawMessage, left.ModelParams.Valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model_params of %s: %w", left.ID, err)
+	}
+	rightParams, err := decodeNullJSON(right.ModelParams.RawMessage, right.ModelParams.Valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model_params of %s: %w", right.ID, err)
+	}
+	params := &differ{collections: []CollectionDiff{}, changes: []DiffChange{}}
+	params.diff("$", nil, leftParams, rightParams)
+
+	result := &ArtifactDiff{
+		Left:         toArtifactRef(left),
+		Right:        toArtifactRef(right),
+		Collections:  d.collections,
+		Output:       d.changes,
+		PromptRender: diffLines(left.PromptRender.String, right.PromptRender.String),
+		ModelParams:  params.changes,
+	}
+	result.Identical = len(result.Output) == 0 && len(result.ModelParams) == 0 && result.PromptRender.Equal
+	return result, nil
+}
+
+// differ walks two JSON values along a schema, collecting changes.
+type differ struct {
+	root        map[string]any
+	collections []CollectionDiff
+	changes     []DiffChange
+}
+
+func (d *differ) diff(path string, schema map[string]any, left, right any) bool {
+	schema = d.resolve(schema)
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			return d.diffObject(path, schema, l, r)
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return d.diffArray(path, schema, l, r)
+		}
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return false
+	}
+	switch {
+	case left == nil:
+		d.changes = append(d.changes, DiffChange{Path: path, Kind: DiffAdded, Right: right})
+	case right == nil:
+		d.changes = append(d.changes, DiffChange{Path: path, Kind: DiffRemoved, Left: left})
+	default:
+		d.changes = append(d.changes, DiffChange{Path: path, Kind: DiffChanged, Left: left, Right: right})
+	}
+	return true
+}
+
+// diffObject compares properties in schema order, then any others by name.
+func (d *differ) diffObject(path string, schema map[string]any, left, right map[string]any) bool {
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(left)+len(right))
+	seen := map[string]bool{}
+	for _, key := range schemaPropertyOrder(schema) {
+		_, inLeft := left[key]
+		_, inRight := right[key]
+		if inLeft || inRight {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	var rest []string
+	for _, obj := range []map[string]any{left, right} {
+		for key := range obj {
+			if !seen[key] {
+				rest = append(rest, key)
+				seen[key] = true
+			}
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	changed := false
+	for _, key := range keys {
+		propertySchema, _ := properties[key].(map[string]any)
+		if d.diff(path+"."+key, propertySchema, left[key], right[key]) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// diffArray pairs object items by a stable key or by text similarity and
+// reports unpaired items as added or removed. Scalar items are compared as
+// multisets.
+func (d *differ) diffArray(path string, schema map[string]any, left, right []any) bool {
+	itemSchema, _ := schema["items"].(map[string]any)
+	leftItems, leftObjects := objectItems(left)
+	rightItems, rightObjects := objectItems(right)
+	if !leftObjects || !rightObjects {
+		return d.diffScalarArray(path, left, right)
+	}
+
+	collection := CollectionDiff{Path: path}
+	var pairs []itemPair
+	if key := stableKey(leftItems, rightItems); key != "" {
+		collection.MatchedBy = key
+		pairs = pairByKey(leftItems, rightItems, key)
+	} else {
+		collection.MatchedBy = "similarity"
+		pairs = pairBySimilarity(leftItems, rightItems)
+	}
+	index := len(d.collections)
+	d.collections = append(d.collections, collection)
+
+	for _, pair := range pairs {
+		switch {
+		case pair.left < 0:
+			collection.Added++
+			d.changes = append(d.changes, DiffChange{Path: itemPath(path, collection.MatchedBy, rightItems, pair.right), Kind: DiffAdded, Right: right[pair.right]})
+		case pair.right < 0:
+			collection.Removed++
+			d.changes = append(d.changes, DiffChange{Path: itemPath(path, collection.MatchedBy, leftItems, pair.left), Kind: DiffRemoved, Left: left[pair.left]})
+		default:
+			before := len(d.changes)
+			if d.diff(itemPath(path, collection.MatchedBy, rightItems, pair.right), itemSchema, left[pair.left], right[pair.right]) {
+				collection.Changed++
+				if pair.similarity != nil {
+					for i := before; i < len(d.changes); i++ {
+						d.changes[i].Similarity = pair.similarity
+					}
+				}
+			} else {
+				collection.Unchanged++
+			}
+		}
+	}
+	d.collections[index] = collection
+	return collection.Added+collection.Removed+collection.Changed > 0
+}
+
+func (d *differ) diffScalarArray(path string, left, right []any) bool {
+	remaining := make([]any, len(right))
+	copy(remaining, right)
+	changed := false
+	for _, value := range left {
+		found := false
+		for i, candidate := range remaining {
+			if reflect.DeepEqual(value, candidate) {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.changes = append(d.changes, DiffChange{Path: path + "[]", Kind: DiffRemoved, Left: value})
+			changed = true
+		}
+	}
+	for _, value := range remaining {
+		d.changes = append(d.changes, DiffChange{Path: path + "[]", Kind: DiffAdded, Right: value})
+		changed = true
+	}
+	return changed
+}
+
+// resolve follows a local "#/..." $ref, as used by recursive schemas such as
+// taxonomy children.
+func (d *differ) resolve(schema map[string]any) map[string]any {
+	for depth := 0; schema != nil && depth < 10; depth++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return schema
+		}
+		var node any = d.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+			if part == "" {
+				continue
+			}
+			obj, ok := node.(map[string]any)
+			if !ok {
+				return nil
+			}
+			node = obj[strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")]
+		}
+		schema, _ = node.(map[string]any)
+	}
+	return schema
+}
+
+// schemaPropertyOrder lists required properties first, then the others by name.
+func schemaPropertyOrder(schema map[string]any) []string {
+	properties, _ := schema["properties"].(map[string]any)
+	var order []string
+	seen := map[string]bool{}
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok && properties[key] != nil && !seen[key] {
+				order = append(order, key)
+				seen[key] = true
+			}
+		}
+	}
+	var rest []string
+	for key := range properties {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+type itemPair struct {
+	left, right int
+	similarity  *float64
+}
+
+func objectItems(values []any) ([]map[string]any, bool) {
+	items := make([]map[string]any, 0, len(values))
+	for _, value := range values {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		items = append(items, obj)
+	}
+	return items, true
+}
+
+// stableKey returns the first key field that is a non-empty scalar, unique
+// within each side, on every item of both sides.
+func stableKey(left, right []map[string]any) string {
+	if len(left) == 0 && len(right) == 0 {
+		return ""
+	}
+	for _, field := range itemKeyFields {
+		if uniqueKeys(left, field) && uniqueKeys(right, field) {
+			return field
+		}
+	}
+	return ""
+}
+
+func uniqueKeys(items []map[string]any, field string) bool {
+	seen := map[string]bool{}
+	for _, item := range items {
+		key, ok := keyValue(item[field])
+		if !ok || seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+func keyValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, strings.TrimSpace(v) != ""
+	case float64, bool:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+// pairByKey pairs items with equal keys, in left order, followed by the
+// right items that have no counterpart.
+func pairByKey(left, right []map[string]any, field string) []itemPair {
+	rightIndex := make(map[string]int, len(right))
+	for i, item := range right {
+		key, _ := keyValue(item[field])
+		rightIndex[key] = i
+	}
+	paired := make([]bool, len(right))
+	pairs := make([]itemPair, 0, len(left)+len(right))
+	for i, item := range left {
+		key, _ := keyValue(item[field])
+		if j, ok := rightIndex[key]; ok {
+			pairs = append(pairs, itemPair{left: i, right: j})
+			paired[j] = true
+		} else {
+			pairs = append(pairs, itemPair{left: i, right: -1})
+		}
+	}
+	for j := range right {
+		if !paired[j] {
+			pairs = append(pairs, itemPair{left: -1, right: j})
+		}
+	}
+	return pairs
+}
+
+// pairBySimilarity greedily pairs the most similar items first. Pairs below
+// minItemSimilarity are reported as a removal and an addition.
+func pairBySimilarity(left, right []map[string]any) []itemPair {
+	type candidate struct {
+		left, right int
+		score       float64
+	}
+	leftWords, rightWords := itemWords(left), itemWords(right)
+	var candidates []candidate
+	for i := range left {
+		for j := range right {
+			if score := leftWords[i].similarity(rightWords[j]); score >= minItemSimilarity {
+				candidates = append(candidates, candidate{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].score > candidates[b].score
+	})
+
+	leftMatch := make([]int, len(left))
+	for i := range leftMatch {
+		leftMatch[i] = -1
+	}
+	scores := make([]float64, len(left))
+	paired := make([]bool, len(right))
+	for _, c := range candidates {
+		if leftMatch[c.left] >= 0 || paired[c.right] {
+			continue
+		}
+		leftMatch[c.left], scores[c.left] = c.right, c.score
+		paired[c.right] = true
+	}
+
+	pairs := make([]itemPair, 0, len(left)+len(right))
+	for i, j := range leftMatch {
+		pair := itemPair{left: i, right: j}
+		if j >= 0 {
+			score := scores[i]
+			pair.similarity = &score
+		}
+		pairs = append(pairs, pair)
+	}
+	for j := range right {
+		if !paired[j] {
+			pairs = append(pairs, itemPair{left: -1, right: j})
+		}
+	}
+	return pairs
+}
+
+// wordsOfItem holds the words of an item's main text (its first text field)
+// and of all strings in it, nested ones included.
+type wordsOfItem struct {
+	text, all map[string]bool
+}
+
+func itemWords(items []map[string]any) []wordsOfItem {
+	words := make([]wordsOfItem, len(items))
+	for i, item := range items {
+		words[i].all = map[string]bool{}
+		collectWords(item, words[i].all)
+		for _, field := range itemTextFields {
+			if text, ok := item[field].(string); ok && strings.TrimSpace(text) != "" {
+				words[i].text = map[string]bool{}
+				collectWords(text, words[i].text)
+				break
+			}
+		}
+	}
+	return words
+}
+
+// similarity is the better of the Dice coefficients of the main texts and of
+// all strings, so a reworded title still matches when the rest is the same.
+func (w wordsOfItem) similarity(other wordsOfItem) float64 {
+	score := dice(w.all, other.all)
+	if w.text != nil && other.text != nil {
+		score = max(score, dice(w.text, other.text))
+	}
+	return score
+}
+
+func collectWords(value any, words map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		for _, word := range strings.FieldsFunc(strings.ToLower(v), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			words[word] = true
+		}
+	case map[string]any:
+		for _, nested := range v {
+			collectWords(nested, words)
+		}
+	case []any:
+		for _, nested := range v {
+			collectWords(nested, words)
+		}
+	}
+}
+
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// itemPath addresses an array item by its key when items are keyed, and by
+// index otherwise.
+func itemPath(path, matchedBy string, items []map[string]any, index int) string {
+	if matchedBy == "similarity" {
+		return fmt.Sprintf("%s[%d]", path, index)
+	}
+	if key, ok := keyValue(items[index][matchedBy]); ok {
+		return fmt.Sprintf("%s[%s=%s]", path, matchedBy, key)
+	}
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// diffLines reports the lines removed from left and added in right, using a
+// longest common subsequence of lines.
+func diffLines(left, right string) *TextDiff {
+	if left == right {
+		return &TextDiff{Equal: true}
+	}
+	a, b := strings.Split(left, "\n"), strings.Split(right, "\n")
+	if len(a) > maxPromptDiffLines || len(b) > maxPromptDiffLines {
+		return &TextDiff{Lines: []LineDiff{{Kind: DiffRemoved, Line: 1, Text: left}, {Kind: DiffAdded, Line: 1, Text: right}}}
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := &TextDiff{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			diff.Lines = append(diff.Lines, LineDiff{Kind: DiffAdded, Line: j + 1, Text: b[j]})
+			j++
+		default:
+			diff.Lines = append(diff.Lines, LineDiff{Kind: DiffRemoved, Line: i + 1, Text: a[i]})
+			i++
+		}
+	}
+	return diff
+}
+
+func decodeNullJSON(raw json.RawMessage, valid bool) (any, error) {
+	if !valid || len(raw) == 0 {
+		return nil, nil
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func toArtifactRef(artifact store.Artifact) ArtifactRef {
+	return ArtifactRef{
+		ID:               artifact.ID,
+		GenerationType:   toGenerationTypePtr(artifact.GenerationType),
+		Status:           artifact.Status,
+		Model:            toStringPtr(artifact.Model),
+		PromptTemplateID: toUUIDPtr(artifact.PromptTemplateID),
+		SchemaTemplateID: toUUIDPtr(artifact.SchemaTemplateID),
+		CreatedAt:        artifact.CreatedAt,
+	}
+}
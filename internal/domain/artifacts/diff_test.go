@@ -0,0 +1,131 @@
+package artifacts
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+func diffTestArtifact(output, modelParams, prompt string) store.Artifact {
+	return store.Artifact{
+		ID:           uuid.New(),
+		Status:       StatusReady,
+		OutputJson:   pqtype.NullRawMessage{RawMessage: json.RawMessage(output), Valid: true},
+		ModelParams:  pqtype.NullRawMessage{RawMessage: json.RawMessage(modelParams), Valid: true},
+		PromptRender: sql.NullString{String: prompt, Valid: true},
+	}
+}
+
+func changesByPath(changes []DiffChange) map[string]DiffChange {
+	byPath := make(map[string]DiffChange, len(changes))
+	for _, change := range changes {
+		byPath[change.Path+" "+change.Kind] = change
+	}
+	return byPath
+}
+
+func TestDiffArtifacts_QuestionsByKey(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/questions_schema.json")
+	require.NoError(t, err)
+
+	left := diffTestArtifact(`{"questions": [
+		{"id": "q1", "question": "What is ATP?", "expected_answer": "Energy currency"},
+		{"id": "q2", "question": "Where does glycolysis happen?", "expected_answer": "Cytoplasm"},
+		{"id": "q3", "question": "What is a ribosome?", "expected_answer": "Protein factory"}
+	]}`, `{"name": "gemini-2.5-flash", "temperature": 0.2}`, "Generate 3 questions.\nUse the document.")
+	right := diffTestArtifact(`{"questions": [
+		{"id": "q2", "question": "Where does glycolysis happen?", "expected_answer": "In the cytoplasm"},
+		{"id": "q1", "question": "What is ATP?", "expected_answer": "Energy currency"},
+		{"id": "q4", "question": "What is a mitochondrion?", "expected_answer": "Powerhouse"}
+	]}`, `{"name": "gemini-2.5-flash", "temperature": 0.7, "seed": 3}`, "Generate 3 hard questions.\nUse the document.")
+
+	diff, err := diffArtifacts(left, right, schema)
+	require.NoError(t, err)
+	assert.False(t, diff.Identical)
+
+	require.Len(t, diff.Collections, 1)
+	assert.Equal(t, CollectionDiff{Path: "$.questions", MatchedBy: "id", Added: 1, Removed: 1, Changed: 1, Unchanged: 1}, diff.Collections[0])
+
+	output := changesByPath(diff.Output)
+	require.Len(t, output, 3)
+	assert.Equal(t, "Cytoplasm", output["$.questions[id=q2].expected_answer changed"].Left)
+	assert.Equal(t, "In the cytoplasm", output["$.questions[id=q2].expected_answer changed"].Right)
+	assert.Contains(t, output, "$.questions[id=q3] removed")
+	assert.Contains(t, output, "$.questions[id=q4] added")
+
+	params := changesByPath(diff.ModelParams)
+	require.Len(t, params, 2)
+	assert.Equal(t, 0.7, params["$.temperature changed"].Right)
+	assert.Equal(t, 3.0, params["$.seed added"].Right)
+
+	require.NotNil(t, diff.PromptRender)
+	assert.Equal(t, []LineDiff{
+		{Kind: DiffAdded, Line: 1, Text: "Generate 3 hard questions."},
+		{Kind: DiffRemoved, Line: 1, Text: "Generate 3 questions."},
+	}, diff.PromptRender.Lines)
+}
+
+func TestDiffArtifacts_TaxonomyBySimilarity(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/taxonomy_schema.json")
+	require.NoError(t, err)
+
+	params := `{"name": "gemini-2.5-flash"}`
+	left := diffTestArtifact(`{"proposed_taxonomy": [
+		{"name": "Cell respiration", "description": "How cells release energy", "children": [
+			{"name": "Glycolysis", "children": []},
+			{"name": "Krebs cycle", "children": []}
+		]},
+		{"name": "Photosynthesis", "children": []}
+	]}`, params, "Build a taxonomy.")
+	right := diffTestArtifact(`{"proposed_taxonomy": [
+		{"name": "Cellular respiration", "description": "How cells release energy", "children": [
+			{"name": "Glycolysis", "children": []},
+			{"name": "Electron transport chain", "children": []}
+		]},
+		{"name": "Photosynthesis", "children": []}
+	]}`, params, "Build a taxonomy.")
+
+	diff, err := diffArtifacts(left, right, schema)
+	require.NoError(t, err)
+	assert.Empty(t, diff.ModelParams)
+	assert.True(t, diff.PromptRender.Equal)
+
+	collections := map[string]CollectionDiff{}
+	for _, collection := range diff.Collections {
+		collections[collection.Path] = collection
+	}
+	top := collections["$.proposed_taxonomy"]
+	assert.Equal(t, "similarity", top.MatchedBy)
+	assert.Equal(t, 1, top.Changed)
+	assert.Equal(t, 1, top.Unchanged)
+	children := collections["$.proposed_taxonomy[0].children"]
+	assert.Equal(t, 1, children.Added)
+	assert.Equal(t, 1, children.Removed)
+	assert.Equal(t, 1, children.Unchanged)
+
+	output := changesByPath(diff.Output)
+	rename := output["$.proposed_taxonomy[0].name changed"]
+	assert.Equal(t, "Cell respiration", rename.Left)
+	assert.Equal(t, "Cellular respiration", rename.Right)
+	require.NotNil(t, rename.Similarity)
+	assert.Contains(t, output, "$.proposed_taxonomy[0].children[1] removed")
+	assert.Contains(t, output, "$.proposed_taxonomy[0].children[1] added")
+}
+
+func TestDiffArtifacts_Identical(t *testing.T) {
+	left := diffTestArtifact(`{"sections": [{"title": "Intro", "difficulty": 1}], "tags": ["a", "b"]}`, `{"name": "m"}`, "p")
+	right := diffTestArtifact(`{"tags": ["b", "a"], "sections": [{"title": "Intro", "difficulty": 1}]}`, `{"name": "m"}`, "p")
+
+	diff, err := diffArtifacts(left, right, nil)
+	require.NoError(t, err)
+	assert.True(t, diff.Identical)
+	assert.Empty(t, diff.Output)
+}
@@ -0,0 +1,16 @@
+package artifacts
+
+import "errors"
+
+// Domain errors for artifacts
+var (
+	ErrInvalidUsageGroup       = errors.New("invalid usage group")
+	ErrInvalidDateRange        = errors.New("invalid date range")
+	ErrArtifactNotFound        = errors.New("artifact not found")
+	ErrInvalidReviewTransition = errors.New("invalid review transition")
+	ErrInvalidReviewer         = errors.New("invalid reviewer")
+	ErrInvalidReviewComment    = errors.New("invalid review comment")
+	ErrNotReviewer             = errors.New("only the assigned reviewer or an admin can decide a review")
+	ErrNotArtifactOwner        = errors.New("only the artifact's requester or an admin can assign a reviewer")
+	ErrInvalidExportFilter     = errors.New("invalid export filter")
+)
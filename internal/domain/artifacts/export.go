@@ -0,0 +1,299 @@
+package artifacts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFilter selects the artifacts written by Export. Empty fields do not
+// filter; From is inclusive and To exclusive.
+type ExportFilter struct {
+	GenerationType   string
+	Status           string
+	From             *time.Time
+	To               *time.Time
+	DocumentID       *uuid.UUID
+	PromptTemplateID *uuid.UUID
+	// PromptTemplateVersion matches the version of the prompt template an
+	// artifact was rendered from; it needs GenerationType or PromptTemplateID
+	PromptTemplateVersion *int32
+	IncludeEvalResults    bool
+	IncludeReviews        bool
+	// Limit caps the number of records; 0 exports everything that matches
+	Limit int
+}
+
+// ExportRecord is one line of an artifact export: the inputs sent to the
+// model, its response and grounding, with optional eval verdicts and review
+// outcome.
+type ExportRecord struct {
+	ArtifactID     uuid.UUID             `json:"artifact_id"`
+	GenerationType string                `json:"generation_type,omitempty"`
+	Status         string                `json:"status"`
+	Model          string                `json:"model,omitempty"`
+	DocumentID     string                `json:"document_id,omitempty"`
+	PromptTemplate *ExportPromptTemplate `json:"prompt_template,omitempty"`
+	System         string                `json:"system,omitempty"`
+	Prompt         string                `json:"prompt"`
+	Response       string                `json:"response"`
+	ResponseJSON   json.RawMessage       `json:"response_json,omitempty"`
+	Grounding      json.RawMessage       `json:"grounding,omitempty"`
+	ModelParams    json.RawMessage       `json:"model_params,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	EvalResults    []ExportEvalResult    `json:"eval_results,omitempty"`
+	Review         *ExportReview         `json:"review,omitempty"`
+}
+
+// ExportPromptTemplate identifies the prompt template version an artifact was
+// rendered from.
+type ExportPromptTemplate struct {
+	ID             uuid.UUID `json:"id"`
+	GenerationType string    `json:"generation_type"`
+	Version        int32     `json:"version"`
+}
+
+// ExportEvalResult is an eval_results row of the eval item the artifact was
+// generated for, or of the items of the evaluation it was ingested into.
+type ExportEvalResult struct {
+	EvalItemID        uuid.UUID       `json:"eval_item_id"`
+	EvalType          string          `json:"eval_type"`
+	Verdict           *string         `json:"verdict,omitempty"`
+	Score             *float64        `json:"score,omitempty"`
+	IsGrounded        *bool           `json:"is_grounded,omitempty"`
+	Reasoning         *string         `json:"reasoning,omitempty"`
+	UnsupportedClaims json.RawMessage `json:"unsupported_claims,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+// ExportReview is the reviewer outcome of an artifact. Outcome is empty while
+// the review is still open.
+type ExportReview struct {
+	ReviewerID      uuid.UUID  `json:"reviewer_id"`
+	Outcome         string     `json:"outcome,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	DecisionComment *string    `json:"decision_comment,omitempty"`
+	Comments        []string   `json:"comments,omitempty"`
+}
+
+// exportArtifactsQuery joins eval results and reviews as JSON per row so that
+// every artifact is exactly one result row.
+const exportArtifactsQuery = `
+	SELECT
+		a.id,
+		COALESCE(a.generation_type::text, ''),
+		a.status,
+		COALESCE(a.model, ''),
+		COALESCE(a.meta->>'document_id', ''),
+		pt.id,
+		COALESCE(pt.generation_type::text, ''),
+		COALESCE(pt.version, 0),
+		COALESCE(a.meta->>'system_instruction_text', ''),
+		COALESCE(NULLIF(a.prompt_render, ''), a.prompt, ''),
+		COALESCE(a.text, ''),
+		a.output_json,
+		a.meta->'grounding',
+		a.model_params,
+		a.created_at,
+		CASE WHEN $8 THEN (
+			SELECT json_agg(json_build_object(
+				'eval_item_id', er.eval_item_id,
+				'eval_type', er.eval_type,
+				'verdict', er.verdict,
+				'score', er.score,
+				'is_grounded', er.is_grounded,
+				'reasoning', er.reasoning,
+				'unsupported_claims', er.unsupported_claims,
+				'created_at', er.created_at
+			) ORDER BY er.created_at)
+			FROM eval_results er
+			JOIN eval_items ei ON ei.id = er.eval_item_id
+			WHERE (a.eval_item_id IS NOT NULL AND ei.id = a.eval_item_id)
+				OR (a.eval_item_id IS NULL AND ei.eval_id = a.eval_id)
+		) END,
+		CASE WHEN $9 AND a.reviewer_id IS NOT NULL THEN json_build_object(
+			'reviewer_id', a.reviewer_id,
+			'outcome', CASE WHEN a.status IN ('APPROVED', 'REJECTED') THEN a.status END,
+			'reviewed_at', a.reviewed_at,
+			'decision_comment', (
+				SELECT e.comment FROM artifact_review_events e
+				WHERE e.artifact_id = a.id AND e.action IN ('APPROVE', 'REJECT')
+				ORDER BY e.created_at DESC
+				LIMIT 1
+			),
+			'comments', (
+				SELECT json_agg(e.comment ORDER BY e.created_at) FROM artifact_review_events e
+				WHERE e.artifact_id = a.id AND e.action = 'COMMENT' AND e.comment IS NOT NULL
+			)
+		) END
+	FROM artifacts a
+	LEFT JOIN prompt_templates pt ON pt.id = a.prompt_template_id
+	WHERE a.generation_type IS NOT NULL
+		AND ($1::text IS NULL OR a.generation_type::text = $1)
+		AND ($2::text IS NULL OR a.status = $2)
+		AND ($3::timestamptz IS NULL OR a.created_at >= $3)
+		AND ($4::timestamptz IS NULL OR a.created_at < $4)
+		AND ($5::text IS NULL OR a.meta->>'document_id' = $5)
+		AND ($6::uuid IS NULL OR a.prompt_template_id = $6)
+		AND ($7::int IS NULL OR pt.version = $7)
+	ORDER BY a.created_at, a.id
+	LIMIT $10
+`
+
+// Validate normalises the filter and checks that it is consistent.
+func (f *ExportFilter) Validate() error {
+	f.GenerationType = strings.ToUpper(strings.TrimSpace(f.GenerationType))
+	f.Status = strings.ToUpper(strings.TrimSpace(f.Status))
+
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidDateRange)
+	}
+	if f.PromptTemplateVersion != nil && f.GenerationType == "" && f.PromptTemplateID == nil {
+		return fmt.Errorf("%w: prompt template version requires a generation type", ErrInvalidExportFilter)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", ErrInvalidExportFilter)
+	}
+	return nil
+}
+
+// args returns the query arguments of exportArtifactsQuery.
+func (f ExportFilter) args() []any {
+	nullString := func(value string) sql.NullString {
+		return sql.NullString{String: value, Valid: value != ""}
+	}
+	nullTime := func(value *time.Time) sql.NullTime {
+		if value == nil {
+			return sql.NullTime{}
+		}
+		return sql.NullTime{Time: *value, Valid: true}
+	}
+
+	var documentID sql.NullString
+	if f.DocumentID != nil {
+		documentID = nullString(f.DocumentID.String())
+	}
+	var promptTemplateID uuid.NullUUID
+	if f.PromptTemplateID != nil {
+		promptTemplateID = uuid.NullUUID{UUID: *f.PromptTemplateID, Valid: true}
+	}
+	var version sql.NullInt32
+	if f.PromptTemplateVersion != nil {
+		version = sql.NullInt32{Int32: *f.PromptTemplateVersion, Valid: true}
+	}
+	var limit sql.NullInt64
+	if f.Limit > 0 {
+		limit = sql.NullInt64{Int64: int64(f.Limit), Valid: true}
+	}
+
+	return []any{
+		nullString(f.GenerationType),
+		nullString(f.Status),
+		nullTime(f.From),
+		nullTime(f.To),
+		documentID,
+		promptTemplateID,
+		version,
+		f.IncludeEvalResults,
+		f.IncludeReviews,
+		limit,
+	}
+}
+
+// Export writes the artifacts matching filter to w as JSONL, one ExportRecord
+// per line, oldest first. Records are encoded as rows are read from the
+// result cursor, so memory use does not grow with the size of the export. It
+// returns the number of records written.
+func (s *Service) Export(ctx context.Context, filter ExportFilter, w io.Writer) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, exportArtifactsQuery, filter.args()...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query artifacts for export: %w", err)
+	}
+	defer rows.Close()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	written := 0
+	for rows.Next() {
+		record, err := scanExportRecord(rows)
+		if err != nil {
+			return written, err
+		}
+		if err := encoder.Encode(record); err != nil {
+			return written, fmt.Errorf("failed to write export record: %w", err)
+		}
+		written++
+	}
+	if err := rows.Err(); err != nil {
+		return written, fmt.Errorf("failed to iterate export rows: %w", err)
+	}
+
+	return written, nil
+}
+
+func scanExportRecord(rows *sql.Rows) (*ExportRecord, error) {
+	var (
+		record           ExportRecord
+		promptTemplateID uuid.NullUUID
+		promptTemplate   ExportPromptTemplate
+		responseJSON     []byte
+		grounding        []byte
+		modelParams      []byte
+		evalResults      []byte
+		review           []byte
+	)
+	if err := rows.Scan(
+		&record.ArtifactID,
+		&record.GenerationType,
+		&record.Status,
+		&record.Model,
+		&record.DocumentID,
+		&promptTemplateID,
+		&promptTemplate.GenerationType,
+		&promptTemplate.Version,
+		&record.System,
+		&record.Prompt,
+		&record.Response,
+		&responseJSON,
+		&grounding,
+		&modelParams,
+		&record.CreatedAt,
+		&evalResults,
+		&review,
+	); err != nil {
+		return nil, fmt.Errorf("failed to scan export row: %w", err)
+	}
+
+	if promptTemplateID.Valid {
+		promptTemplate.ID = promptTemplateID.UUID
+		record.PromptTemplate = &promptTemplate
+	}
+	record.ResponseJSON = responseJSON
+	record.Grounding = grounding
+	record.ModelParams = modelParams
+
+	if len(evalResults) > 0 {
+		if err := json.Unmarshal(evalResults, &record.EvalResults); err != nil {
+			return nil, fmt.Errorf("failed to parse eval results of artifact %s: %w", record.ArtifactID, err)
+		}
+	}
+	if len(review) > 0 {
+		record.Review = &ExportReview{}
+		if err := json.Unmarshal(review, record.Review); err != nil {
+			return nil, fmt.Errorf("failed to parse review of artifact %s: %w", record.ArtifactID, err)
+		}
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,153 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+func TestParseExportFilter(t *testing.T) {
+	documentID := uuid.New()
+	r := httptest.NewRequest("GET", "/artifacts/export?generation_type=questions&status=approved&from=2026-01-01&to=2026-01-31&document_id="+documentID.String()+"&prompt_template_version=3&include_eval_results=true&limit=50", nil)
+
+	filter, err := parseExportFilter(r)
+	require.NoError(t, err)
+	require.NoError(t, filter.Validate())
+
+	assert.Equal(t, "QUESTIONS", filter.GenerationType)
+	assert.Equal(t, "APPROVED", filter.Status)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), *filter.To)
+	assert.Equal(t, documentID, *filter.DocumentID)
+	assert.Equal(t, int32(3), *filter.PromptTemplateVersion)
+	assert.True(t, filter.IncludeEvalResults)
+	assert.False(t, filter.IncludeReviews)
+	assert.Equal(t, 50, filter.Limit)
+
+	args := filter.args()
+	require.Len(t, args, 10)
+	assert.Equal(t, sql.NullString{String: "QUESTIONS", Valid: true}, args[0])
+	assert.Equal(t, sql.NullString{String: documentID.String(), Valid: true}, args[4])
+	assert.Equal(t, uuid.NullUUID{}, args[5])
+	assert.Equal(t, sql.NullInt32{Int32: 3, Valid: true}, args[6])
+	assert.Equal(t, sql.NullInt64{Int64: 50, Valid: true}, args[9])
+
+	for _, query := range []string{"from=yesterday", "document_id=nope", "prompt_template_version=v2", "include_reviews=maybe"} {
+		_, err := parseExportFilter(httptest.NewRequest("GET", "/artifacts/export?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestExportFilter_Validate(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	version := int32(2)
+
+	filter := ExportFilter{From: &from, To: &to}
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidDateRange)
+
+	filter = ExportFilter{PromptTemplateVersion: &version}
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidExportFilter)
+
+	filter = ExportFilter{Limit: -1}
+	assert.ErrorIs(t, filter.Validate(), ErrInvalidExportFilter)
+
+	filter = ExportFilter{}
+	require.NoError(t, filter.Validate())
+	args := filter.args()
+	for i, arg := range append(args[:7:7], args[9]) {
+		value, err := arg.(driver.Valuer).Value()
+		require.NoError(t, err)
+		assert.Nil(t, value, "argument %d", i+1)
+	}
+}
+
+func TestExport_RunsAgainstDatabase(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	service := NewService(db)
+
+	userID := uuid.New()
+	_, err := queries.CreateUser(ctx, store.CreateUserParams{
+		ID:        userID,
+		Email:     fmt.Sprintf("export-test-%s@example.com", userID),
+		Password:  "password123",
+		IsTeacher: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM artifacts WHERE user_id = $1", userID)
+		_ = queries.DeleteUser(ctx, userID)
+	})
+
+	var templateID uuid.UUID
+	var templateVersion int32
+	require.NoError(t, db.QueryRowContext(ctx,
+		"SELECT id, version FROM prompt_templates WHERE generation_type = 'QUESTIONS' ORDER BY version DESC LIMIT 1",
+	).Scan(&templateID, &templateVersion))
+
+	documentID := uuid.New()
+	artifact, err := service.CreateArtifact(ctx, CreateArtifactParams{
+		Type:             string(store.ArtifactTypeOTHER),
+		GenerationType:   "QUESTIONS",
+		Status:           StatusReady,
+		UserID:           userID,
+		Text:             `{"questions": []}`,
+		OutputJSON:       json.RawMessage(`{"questions": []}`),
+		Model:            "synthetic:1",
+		Prompt:           "Generate questions",
+		PromptTemplateID: uuid.NullUUID{UUID: templateID, Valid: true},
+		Meta:             json.RawMessage(`{"document_id": "` + documentID.String() + `"}`),
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	written, err := service.Export(ctx, ExportFilter{
+		GenerationType:        "questions",
+		Status:                "ready",
+		DocumentID:            &documentID,
+		PromptTemplateVersion: &templateVersion,
+		IncludeEvalResults:    true,
+		IncludeReviews:        true,
+	}, &out)
+	require.NoError(t, err)
+	require.Equal(t, 1, written)
+
+	var record ExportRecord
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, artifact.ID, record.ArtifactID)
+	assert.Equal(t, documentID.String(), record.DocumentID)
+	assert.Equal(t, "Generate questions", record.Prompt)
+	require.NotNil(t, record.PromptTemplate)
+	assert.Equal(t, templateID, record.PromptTemplate.ID)
+	assert.Equal(t, "QUESTIONS", record.PromptTemplate.GenerationType)
+	assert.Equal(t, templateVersion, record.PromptTemplate.Version)
+
+	otherVersion := templateVersion + 1000
+	out.Reset()
+	written, err = service.Export(ctx, ExportFilter{DocumentID: &documentID, PromptTemplateID: &templateID, PromptTemplateVersion: &otherVersion}, &out)
+	require.NoError(t, err)
+	assert.Zero(t, written)
+}
@@ -1,11 +1,19 @@
 package artifacts
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
+	"learning-core-api/internal/domain/evals"
 	httpPkg "learning-core-api/internal/http"
 	"learning-core-api/internal/http/authz"
 	"learning-core-api/internal/http/render"
@@ -13,10 +21,11 @@ import (
 
 type Handler struct {
 	service *Service
+	ingest  *evals.IngestService
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, ingest *evals.IngestService) *Handler {
+	return &Handler{service: service, ingest: ingest}
 }
 
 func (h *Handler) RegisterPublicRoutes(r chi.Router) {
@@ -27,6 +36,8 @@ func (h *Handler) RegisterAdminRoutes(r chi.Router) {
 	r.With(authz.RequireScope("read")).Get("/artifacts", h.ListArtifacts)
 	r.With(authz.RequireScope("read")).Get("/artifacts/{id}", h.GetArtifactByID)
 	r.With(authz.RequireScope("read")).Get("/artifacts/stats", h.GetArtifactStats)
+	r.With(authz.RequireScope("read")).Get("/artifacts/usage/{group_by}", h.GetArtifactUsage)
+	r.With(authz.RequireScope("read")).Get("/artifacts/export", h.ExportArtifacts)
 	r.With(authz.RequireScope("read")).Get("/artifacts/type/{type}", h.GetArtifactsByType)
 	r.With(authz.RequireScope("read")).Get("/artifacts/status/{status}", h.GetArtifactsByStatus)
 }
@@ -36,6 +47,19 @@ func (h *Handler) RegisterTeacherRoutes(r chi.Router) {
 	r.With(authz.RequireScope("read")).Get("/artifacts/stats", h.GetArtifactStats)
 }
 
+// RegisterStaffRoutes registers the routes shared by teachers and admins.
+func (h *Handler) RegisterStaffRoutes(r chi.Router) {
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/ingest", h.IngestArtifact)
+	r.With(authz.RequireScope("read")).Get("/artifacts/{id}/lineage", h.GetArtifactLineage)
+	r.With(authz.RequireScope("read")).Get("/artifacts/{id}/diff/{other_id}", h.DiffArtifacts)
+	r.With(authz.RequireScope("read")).Get("/artifacts/review-queue", h.ListReviewQueue)
+	r.With(authz.RequireScope("read")).Get("/artifacts/{id}/review", h.GetArtifactReview)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/review/assign", h.AssignArtifactReviewer)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/review/comments", h.CommentArtifactReview)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/review/approve", h.ApproveArtifact)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/review/reject", h.RejectArtifact)
+}
+
 func (h *Handler) RegisterLearnerRoutes(r chi.Router) {
 	// Learners have no access to artifacts
 }
@@ -118,6 +142,90 @@ func (h *Handler) GetArtifactByID(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, http.StatusOK, ConvertFromStore(*artifact))
 }
 
+// GetArtifactLineage returns the regeneration and refinement tree of an artifact.
+// @Summary Get artifact lineage
+// @Description Walks parent links up to the original generation and returns it with every artifact regenerated or refined from it, nested under their parents. Works for any artifact in the tree. Teachers can only request artifacts they requested, and the feedback of artifacts other users refined is left out.
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Param id path string true "Artifact ID (UUID)"
+// @Success 200 {object} artifacts.Lineage "Lineage tree"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/lineage [get]
+func (h *Handler) GetArtifactLineage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	requesterID, err := requesterFilter(r)
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	lineage, err := h.service.GetLineage(r.Context(), id, requesterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			render.Error(w, http.StatusNotFound, "Artifact not found")
+			return
+		}
+		log.Printf("ERROR: failed to get lineage of artifact %s: %v", id, err)
+		render.Error(w, http.StatusInternalServerError, "Failed to get artifact lineage")
+		return
+	}
+
+	render.JSON(w, http.StatusOK, lineage)
+}
+
+// DiffArtifacts compares two artifacts.
+// @Summary Diff two artifacts
+// @Description Teachers can only diff artifacts they requested. Compares the output_json of two artifacts along the structure of their schema template (the left one's, or the right one's when left has none). Array items such as questions, taxonomy nodes or sections are paired by a stable key (id, key, slug or code) when every item has a unique one, otherwise by word overlap of their text, and reported as added, removed or changed. The prompt renders are compared line by line and the model params field by field.
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Param id path string true "Left artifact ID (UUID)"
+// @Param other_id path string true "Right artifact ID (UUID)"
+// @Success 200 {object} artifacts.ArtifactDiff "Structured diff"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/diff/{other_id} [get]
+func (h *Handler) DiffArtifacts(w http.ResponseWriter, r *http.Request) {
+	leftID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+	rightID, err := uuid.Parse(chi.URLParam(r, "other_id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid other artifact ID")
+		return
+	}
+
+	requesterID, err := requesterFilter(r)
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	diff, err := h.service.DiffArtifacts(r.Context(), leftID, rightID, requesterID)
+	if err != nil {
+		if errors.Is(err, ErrArtifactNotFound) {
+			render.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("ERROR: failed to diff artifacts %s and %s: %v", leftID, rightID, err)
+		render.Error(w, http.StatusInternalServerError, "Failed to diff artifacts")
+		return
+	}
+
+	render.JSON(w, http.StatusOK, diff)
+}
+
 // GetArtifactsByType godoc
 // @Summary Get artifacts by type
 // @Description Retrieve artifacts filtered by their type with pagination
@@ -197,7 +305,7 @@ func (h *Handler) GetArtifactsByStatus(w http.ResponseWriter, r *http.Request) {
 
 // GetArtifactStats retrieves artifact statistics.
 // @Summary Get artifact statistics
-// @Description Retrieve statistics about artifacts (counts by status, etc.)
+// @Description Retrieve statistics about artifacts (counts by status, token usage and cost totals)
 // @Tags Artifacts
 // @Security OAuth2[read]
 // @Success 200 {object} store.GetArtifactStatsRow "Artifact statistics"
@@ -213,3 +321,475 @@ func (h *Handler) GetArtifactStats(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, http.StatusOK, stats)
 }
+
+// defaultUsageWindow is the report range used when no from date is given.
+const defaultUsageWindow = 30 * 24 * time.Hour
+
+// GetArtifactUsage breaks down token usage and spend.
+// @Summary Get artifact token usage and cost
+// @Description Aggregate token usage and cost of artifacts created in a date range, grouped by user, generation type, document or day (UTC). Dates accept RFC 3339 or YYYY-MM-DD; a date-only "to" includes that whole day. Defaults to the last 30 days.
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Param group_by path string true "Grouping" Enums(user, generation_type, document, day)
+// @Param from query string false "Start of the range (inclusive)"
+// @Param to query string false "End of the range (exclusive for timestamps)"
+// @Success 200 {object} artifacts.UsageReport "Usage breakdown"
+// @Failure 400 {object} map[string]string "Bad request - invalid grouping or date range"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/usage/{group_by} [get]
+func (h *Handler) GetArtifactUsage(w http.ResponseWriter, r *http.Request) {
+	groupBy := chi.URLParam(r, "group_by")
+
+	to := time.Now().UTC()
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		parsed, dateOnly, err := parseUsageTime(raw)
+		if err != nil {
+			render.Error(w, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+		to = parsed
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+
+	from := to.Add(-defaultUsageWindow)
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		parsed, _, err := parseUsageTime(raw)
+		if err != nil {
+			render.Error(w, http.StatusBadRequest, "Invalid from date")
+			return
+		}
+		from = parsed
+	}
+
+	breakdown, err := h.service.GetUsage(r.Context(), groupBy, from, to)
+	if err != nil {
+		if errors.Is(err, ErrInvalidUsageGroup) || errors.Is(err, ErrInvalidDateRange) {
+			render.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, UsageReport{
+		GroupBy: groupBy,
+		From:    from,
+		To:      to,
+		Data:    breakdown,
+	})
+}
+
+// parseUsageTime accepts an RFC 3339 timestamp or a YYYY-MM-DD date (UTC).
+func parseUsageTime(raw string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.DateOnly, raw); err == nil {
+		return parsed, true, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	return parsed, false, err
+}
+
+// ExportArtifacts streams artifacts as a JSONL dataset.
+// @Summary Export artifacts as JSONL
+// @Description Streams generation artifacts as JSON Lines, oldest first, one record per artifact with the system instruction, rendered prompt, response (text and structured output), grounding metadata and model params. Use it to build fine-tuning and regression datasets, e.g. status=APPROVED for reviewed generations. Eval verdicts of the artifact's eval items and the reviewer outcome are joined on request. Dates accept RFC 3339 or YYYY-MM-DD; a date-only "to" includes that whole day. A failure after the first record aborts the response, so a truncated download is never mistaken for a complete one.
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Produce application/x-ndjson
+// @Param generation_type query string false "Generation type"
+// @Param status query string false "Artifact status" Enums(READY, IN_REVIEW, APPROVED, REJECTED, INVALID, ERROR)
+// @Param from query string false "Created at or after"
+// @Param to query string false "Created before"
+// @Param document_id query string false "Source document ID"
+// @Param prompt_template_id query string false "Prompt template version ID"
+// @Param prompt_template_version query int false "Prompt template version (with generation_type)"
+// @Param include_eval_results query bool false "Join eval_results verdicts"
+// @Param include_reviews query bool false "Join reviewer outcomes"
+// @Param limit query int false "Maximum number of records (default: all)"
+// @Success 200 {object} artifacts.ExportRecord "One record per line"
+// @Failure 400 {object} map[string]string "Bad request - invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/export [get]
+func (h *Handler) ExportArtifacts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseExportFilter(r)
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		render.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="artifacts-%s.jsonl"`, time.Now().UTC().Format("20060102-150405")))
+
+	written, err := h.service.Export(r.Context(), filter, w)
+	if err != nil {
+		if written == 0 {
+			w.Header().Del("Content-Disposition")
+			render.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		// The status line is already sent; abort the connection instead of
+		// ending the body cleanly.
+		log.Printf("ERROR: artifact export failed after %d records: %v", written, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// parseExportFilter reads the export filter from the query string.
+func parseExportFilter(r *http.Request) (ExportFilter, error) {
+	query := r.URL.Query()
+	filter := ExportFilter{
+		GenerationType: query.Get("generation_type"),
+		Status:         query.Get("status"),
+	}
+
+	if raw := query.Get("from"); raw != "" {
+		parsed, _, err := parseUsageTime(raw)
+		if err != nil {
+			return filter, errors.New("invalid from date")
+		}
+		filter.From = &parsed
+	}
+	if raw := query.Get("to"); raw != "" {
+		parsed, dateOnly, err := parseUsageTime(raw)
+		if err != nil {
+			return filter, errors.New("invalid to date")
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		filter.To = &parsed
+	}
+	if raw := query.Get("document_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid document_id")
+		}
+		filter.DocumentID = &id
+	}
+	if raw := query.Get("prompt_template_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("invalid prompt_template_id")
+		}
+		filter.PromptTemplateID = &id
+	}
+	if raw := query.Get("prompt_template_version"); raw != "" {
+		version, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return filter, errors.New("invalid prompt_template_version")
+		}
+		v := int32(version)
+		filter.PromptTemplateVersion = &v
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.IncludeEvalResults, err = parseBoolParam(query.Get("include_eval_results")); err != nil {
+		return filter, errors.New("invalid include_eval_results")
+	}
+	if filter.IncludeReviews, err = parseBoolParam(query.Get("include_reviews")); err != nil {
+		return filter, errors.New("invalid include_reviews")
+	}
+
+	return filter, nil
+}
+
+func parseBoolParam(raw string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+// IngestArtifact turns a generated question artifact into a draft evaluation.
+// @Summary Ingest a question artifact into an evaluation
+// @Description Creates a draft evaluation owned by the requesting user with one eval item per generated question, copying the artifact's grounding metadata and source document onto each item, and links the artifact to the new evaluation. Only READY or APPROVED QUESTIONS and MULTIPLE_CHOICE artifacts can be ingested (only APPROVED ones when REQUIRE_APPROVED_ARTIFACTS is set), and each artifact only once. Teachers can only ingest artifacts they requested. The request body is optional.
+// @Tags Artifacts
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID"
+// @Param request body evals.IngestArtifactRequest false "Overrides for the draft evaluation"
+// @Success 201 {object} evals.IngestResult "Created evaluation and items"
+// @Failure 400 {object} map[string]string "Bad request - artifact cannot be ingested"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 409 {object} map[string]string "Artifact already ingested"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/ingest [post]
+func (h *Handler) IngestArtifact(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req evals.IngestArtifactRequest
+	if err := render.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	result, err := h.ingest.IngestArtifact(r.Context(), id, userID, req, isAdmin(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, evals.ErrArtifactNotFound):
+			render.Error(w, http.StatusNotFound, "Artifact not found")
+		case errors.Is(err, evals.ErrArtifactAlreadyIngested):
+			render.Error(w, http.StatusConflict, err.Error())
+		case errors.Is(err, evals.ErrArtifactNotIngestible),
+			errors.Is(err, evals.ErrInvalidTitle),
+			errors.Is(err, evals.ErrTitleTooLong),
+			errors.Is(err, evals.ErrInvalidDifficulty):
+			render.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			render.Error(w, http.StatusInternalServerError, "Failed to ingest artifact")
+		}
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, result)
+}
+
+// ListReviewQueue lists the artifacts assigned to the requesting user for review.
+// @Summary List my artifact reviews
+// @Description Artifacts whose reviewer is the requesting user, most recently assigned first. Filter by status to get open reviews (IN_REVIEW) or past decisions (APPROVED, REJECTED).
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Param status query string false "Artifact status" Enums(IN_REVIEW, APPROVED, REJECTED)
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
+// @Success 200 {array} artifacts.Artifact "Assigned artifacts"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/review-queue [get]
+func (h *Handler) ListReviewQueue(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	pagination := httpPkg.GetPaginationParams(r)
+	storeArtifacts, err := h.service.ListReviewQueue(r.Context(), userID, r.URL.Query().Get("status"), int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	domainArtifacts := make([]Artifact, 0, len(storeArtifacts))
+	for _, storeArtifact := range storeArtifacts {
+		domainArtifacts = append(domainArtifacts, ConvertFromStore(storeArtifact))
+	}
+	render.JSON(w, http.StatusOK, domainArtifacts)
+}
+
+// GetArtifactReview returns the review state and history of an artifact.
+// @Summary Get artifact review
+// @Description Status, reviewer, review timestamps and the history of assignments, comments and decisions of an artifact.
+// @Tags Artifacts
+// @Security OAuth2[read]
+// @Param id path string true "Artifact ID (UUID)"
+// @Success 200 {object} artifacts.ArtifactReview "Artifact review"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/review [get]
+func (h *Handler) GetArtifactReview(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	review, err := h.service.GetReview(r.Context(), id)
+	if err != nil {
+		render.Error(w, reviewErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, review)
+}
+
+// AssignArtifactReviewer assigns a reviewer to an artifact.
+// @Summary Assign an artifact reviewer
+// @Description Moves a READY artifact to IN_REVIEW with the given teacher or admin as reviewer. Only the user who requested the artifact or an admin can assign, and the requester cannot review their own artifact. Assigning again while in review replaces the reviewer.
+// @Tags Artifacts
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body artifacts.AssignReviewerRequest true "Reviewer and optional comment"
+// @Success 200 {object} artifacts.ArtifactReview "Updated review"
+// @Failure 400 {object} map[string]string "Bad request - invalid reviewer or comment"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the artifact's requester"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 409 {object} map[string]string "Artifact cannot be assigned in its current status"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/review/assign [post]
+func (h *Handler) AssignArtifactReviewer(w http.ResponseWriter, r *http.Request) {
+	var req AssignReviewerRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	h.review(w, r, func(id, userID uuid.UUID) (*ArtifactReview, error) {
+		return h.service.AssignReviewer(r.Context(), id, userID, req, isAdmin(r))
+	})
+}
+
+// CommentArtifactReview adds a comment to an artifact's review.
+// @Summary Comment on an artifact review
+// @Description Adds a comment to the review history without changing the status.
+// @Tags Artifacts
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body artifacts.ReviewCommentRequest true "Comment"
+// @Success 200 {object} artifacts.ArtifactReview "Updated review"
+// @Failure 400 {object} map[string]string "Bad request - missing comment"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 409 {object} map[string]string "Artifact is not part of the review workflow"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/review/comments [post]
+func (h *Handler) CommentArtifactReview(w http.ResponseWriter, r *http.Request) {
+	var req ReviewCommentRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	h.review(w, r, func(id, userID uuid.UUID) (*ArtifactReview, error) {
+		return h.service.CommentReview(r.Context(), id, userID, req)
+	})
+}
+
+// ApproveArtifact approves an artifact in review.
+// @Summary Approve an artifact
+// @Description Moves an IN_REVIEW artifact to APPROVED. Only the assigned reviewer or an admin can approve; the artifact's requester never can. The request body is optional.
+// @Tags Artifacts
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body artifacts.ReviewCommentRequest false "Optional comment"
+// @Success 200 {object} artifacts.ArtifactReview "Updated review"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the assigned reviewer"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 409 {object} map[string]string "Artifact is not in review"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/review/approve [post]
+func (h *Handler) ApproveArtifact(w http.ResponseWriter, r *http.Request) {
+	h.decideReview(w, r, ReviewActionApprove)
+}
+
+// RejectArtifact rejects an artifact in review.
+// @Summary Reject an artifact
+// @Description Moves an IN_REVIEW artifact to REJECTED. Only the assigned reviewer or an admin can reject, and a comment explaining why is required.
+// @Tags Artifacts
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body artifacts.ReviewCommentRequest true "Reason for the rejection"
+// @Success 200 {object} artifacts.ArtifactReview "Updated review"
+// @Failure 400 {object} map[string]string "Bad request - missing comment"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the assigned reviewer"
+// @Failure 404 {object} map[string]string "Artifact not found"
+// @Failure 409 {object} map[string]string "Artifact is not in review"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /artifacts/{id}/review/reject [post]
+func (h *Handler) RejectArtifact(w http.ResponseWriter, r *http.Request) {
+	h.decideReview(w, r, ReviewActionReject)
+}
+
+func (h *Handler) decideReview(w http.ResponseWriter, r *http.Request, action string) {
+	var req ReviewCommentRequest
+	if err := render.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	h.review(w, r, func(id, userID uuid.UUID) (*ArtifactReview, error) {
+		return h.service.DecideReview(r.Context(), id, userID, action, req, isAdmin(r))
+	})
+}
+
+// review parses the artifact and user IDs, runs apply and renders the review.
+func (h *Handler) review(w http.ResponseWriter, r *http.Request, apply func(id, userID uuid.UUID) (*ArtifactReview, error)) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	review, err := apply(id, userID)
+	if err != nil {
+		render.Error(w, reviewErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, review)
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrArtifactNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidReviewer), errors.Is(err, ErrInvalidReviewComment):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotReviewer), errors.Is(err, ErrNotArtifactOwner):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidReviewTransition):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// requesterFilter returns nil for admins, who can see every artifact, and the
+// caller's user ID for everyone else.
+func requesterFilter(r *http.Request) (*uuid.UUID, error) {
+	if isAdmin(r) {
+		return nil, nil
+	}
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		return nil, err
+	}
+	return &userID, nil
+}
+
+func isAdmin(r *http.Request) bool {
+	for _, role := range authz.RolesFromContext(r.Context()) {
+		if role == authz.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
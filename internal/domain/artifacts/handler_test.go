@@ -0,0 +1,199 @@
+package artifacts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/evals"
+	"learning-core-api/internal/http/authz"
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+// artifactFixture is a database with users and artifacts for handler tests.
+type artifactFixture struct {
+	ctx     context.Context
+	db      *sql.DB
+	queries *store.Queries
+	service *Service
+}
+
+func newArtifactFixture(t *testing.T) *artifactFixture {
+	t.Helper()
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return &artifactFixture{ctx: context.Background(), db: db, queries: store.New(db), service: NewService(db)}
+}
+
+func (f *artifactFixture) createUser(t *testing.T, isAdmin, isTeacher bool) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	_, err := f.queries.CreateUser(f.ctx, store.CreateUserParams{
+		ID:        id,
+		Email:     fmt.Sprintf("review-test-%s@example.com", id),
+		Password:  "password123",
+		IsAdmin:   isAdmin,
+		IsTeacher: isTeacher,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = f.queries.DeleteUser(f.ctx, id)
+	})
+	return id
+}
+
+func (f *artifactFixture) createArtifact(t *testing.T, userID uuid.UUID) *store.Artifact {
+	t.Helper()
+	artifact, err := f.service.CreateArtifact(f.ctx, CreateArtifactParams{
+		Type:           string(store.ArtifactTypeOTHER),
+		GenerationType: "QUESTIONS",
+		Status:         StatusReady,
+		UserID:         userID,
+		Text:           `{"questions": []}`,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = f.db.ExecContext(f.ctx, "DELETE FROM artifacts WHERE id = $1", artifact.ID)
+	})
+	return artifact
+}
+
+// serve sends a request to the staff routes as userID with role.
+func (f *artifactFixture) serve(userID uuid.UUID, role, method, path, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := authz.WithAuth(req.Context(), userID.String(), []string{role}, []string{"read", "write"})
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	})
+	NewHandler(f.service, evals.NewIngestService(f.db, false)).RegisterStaffRoutes(r)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_TeacherApprovesAssignedArtifact(t *testing.T) {
+	f := newArtifactFixture(t)
+	adminID := f.createUser(t, true, false)
+	reviewerID := f.createUser(t, false, true)
+	otherTeacherID := f.createUser(t, false, true)
+	artifact := f.createArtifact(t, adminID)
+
+	_, err := f.service.AssignReviewer(f.ctx, artifact.ID, adminID, AssignReviewerRequest{ReviewerID: reviewerID}, true)
+	require.NoError(t, err)
+
+	approve := func(userID uuid.UUID) *httptest.ResponseRecorder {
+		return f.serve(userID, authz.RoleTeacher, http.MethodPost, "/artifacts/"+artifact.ID.String()+"/review/approve", `{"comment": "Looks good"}`)
+	}
+
+	assert.Equal(t, http.StatusForbidden, approve(otherTeacherID).Code)
+
+	w := approve(reviewerID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var review ArtifactReview
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &review))
+	assert.Equal(t, StatusApproved, review.Status)
+	require.NotNil(t, review.ReviewerID)
+	assert.Equal(t, reviewerID, *review.ReviewerID)
+	assert.NotNil(t, review.ReviewedAt)
+	require.Len(t, review.Events, 2)
+	assert.Equal(t, ReviewActionApprove, review.Events[1].Action)
+}
+
+func TestHandler_TeacherCannotApproveOwnArtifact(t *testing.T) {
+	f := newArtifactFixture(t)
+	ownerID := f.createUser(t, false, true)
+	otherTeacherID := f.createUser(t, false, true)
+	artifact := f.createArtifact(t, ownerID)
+	assignPath := "/artifacts/" + artifact.ID.String() + "/review/assign"
+
+	// The requester cannot review their own artifact.
+	w := f.serve(ownerID, authz.RoleTeacher, http.MethodPost, assignPath, fmt.Sprintf(`{"reviewer_id": %q}`, ownerID))
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	// Other teachers cannot assign the artifact, not even to themselves.
+	w = f.serve(otherTeacherID, authz.RoleTeacher, http.MethodPost, assignPath, fmt.Sprintf(`{"reviewer_id": %q}`, otherTeacherID))
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	// The requester cannot approve it either, even after an admin assigned it
+	// to someone else.
+	adminID := f.createUser(t, true, false)
+	_, err := f.service.AssignReviewer(f.ctx, artifact.ID, adminID, AssignReviewerRequest{ReviewerID: otherTeacherID}, true)
+	require.NoError(t, err)
+	w = f.serve(ownerID, authz.RoleTeacher, http.MethodPost, "/artifacts/"+artifact.ID.String()+"/review/approve", "")
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	review, err := f.service.GetReview(f.ctx, artifact.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusInReview, review.Status)
+}
+
+func TestHandler_DiffLimitedToRequester(t *testing.T) {
+	f := newArtifactFixture(t)
+	ownerID := f.createUser(t, false, true)
+	otherTeacherID := f.createUser(t, false, true)
+	adminID := f.createUser(t, true, false)
+	left := f.createArtifact(t, ownerID)
+	right := f.createArtifact(t, ownerID)
+	path := "/artifacts/" + left.ID.String() + "/diff/" + right.ID.String()
+
+	assert.Equal(t, http.StatusOK, f.serve(ownerID, authz.RoleTeacher, http.MethodGet, path, "").Code)
+	assert.Equal(t, http.StatusOK, f.serve(adminID, authz.RoleAdmin, http.MethodGet, path, "").Code)
+	assert.Equal(t, http.StatusNotFound, f.serve(otherTeacherID, authz.RoleTeacher, http.MethodGet, path, "").Code)
+
+	// One foreign artifact is enough to hide the diff.
+	foreign := f.createArtifact(t, otherTeacherID)
+	path = "/artifacts/" + left.ID.String() + "/diff/" + foreign.ID.String()
+	assert.Equal(t, http.StatusNotFound, f.serve(ownerID, authz.RoleTeacher, http.MethodGet, path, "").Code)
+}
+
+func TestHandler_IngestLimitedToRequester(t *testing.T) {
+	f := newArtifactFixture(t)
+	ownerID := f.createUser(t, false, true)
+	otherTeacherID := f.createUser(t, false, true)
+	artifact := f.createArtifact(t, ownerID)
+	path := "/artifacts/" + artifact.ID.String() + "/ingest"
+
+	w := f.serve(otherTeacherID, authz.RoleTeacher, http.MethodPost, path, "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	// The requester gets past the ownership check; the artifact has no
+	// questions to ingest.
+	w = f.serve(ownerID, authz.RoleTeacher, http.MethodPost, path, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestHandler_LineageLimitedToRequester(t *testing.T) {
+	f := newArtifactFixture(t)
+	ownerID := f.createUser(t, false, true)
+	otherTeacherID := f.createUser(t, false, true)
+	adminID := f.createUser(t, true, false)
+	artifact := f.createArtifact(t, ownerID)
+	path := "/artifacts/" + artifact.ID.String() + "/lineage"
+
+	assert.Equal(t, http.StatusOK, f.serve(ownerID, authz.RoleTeacher, http.MethodGet, path, "").Code)
+	assert.Equal(t, http.StatusOK, f.serve(adminID, authz.RoleAdmin, http.MethodGet, path, "").Code)
+	assert.Equal(t, http.StatusNotFound, f.serve(otherTeacherID, authz.RoleTeacher, http.MethodGet, path, "").Code)
+}
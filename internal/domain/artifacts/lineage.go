@@ -0,0 +1,79 @@
+package artifacts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+// Derivations of an artifact from its parent.
+const (
+	DerivationRegenerate = "REGENERATE"
+	DerivationRefine     = "REFINE"
+)
+
+// Lineage is the tree of artifacts regenerated or refined from one original
+// generation. ArtifactID is the artifact the tree was requested for.
+// @Description Regeneration and refinement tree of an artifact
+type Lineage struct {
+	ArtifactID uuid.UUID   `json:"artifact_id"`
+	Root       LineageNode `json:"root"`
+	Size       int         `json:"size" example:"4"`
+}
+
+// LineageNode is one artifact in a lineage tree with the artifacts derived from it.
+type LineageNode struct {
+	ID               uuid.UUID     `json:"id"`
+	ParentArtifactID *uuid.UUID    `json:"parent_artifact_id,omitempty"`
+	Derivation       *string       `json:"derivation,omitempty" example:"REFINE"`
+	Feedback         string        `json:"feedback,omitempty" example:"Make the questions harder"`
+	GenerationType   *string       `json:"generation_type,omitempty" example:"QUESTIONS"`
+	Status           string        `json:"status" example:"READY"`
+	Model            *string       `json:"model,omitempty"`
+	UserID           *uuid.UUID    `json:"user_id,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	Children         []LineageNode `json:"children,omitempty"`
+}
+
+// buildLineage nests lineage rows under their parents. Rows come ordered by
+// depth, so the first one is the root and parents precede their children.
+// When requesterID is set, the feedback of artifacts other users derived is
+// left out.
+func buildLineage(artifactID uuid.UUID, rows []store.GetArtifactLineageRow, requesterID *uuid.UUID) *Lineage {
+	children := make(map[uuid.UUID][]store.GetArtifactLineageRow, len(rows))
+	for _, row := range rows[1:] {
+		if row.ParentArtifactID.Valid {
+			children[row.ParentArtifactID.UUID] = append(children[row.ParentArtifactID.UUID], row)
+		}
+	}
+
+	var build func(row store.GetArtifactLineageRow) LineageNode
+	build = func(row store.GetArtifactLineageRow) LineageNode {
+		node := LineageNode{
+			ID:               row.ID,
+			ParentArtifactID: toUUIDPtr(row.ParentArtifactID),
+			Derivation:       toStringPtr(row.Derivation),
+			Feedback:         row.Feedback,
+			GenerationType:   toGenerationTypePtr(row.GenerationType),
+			Status:           row.Status,
+			Model:            toStringPtr(row.Model),
+			UserID:           toUUIDPtr(row.UserID),
+			CreatedAt:        row.CreatedAt,
+		}
+		if requesterID != nil && (!row.UserID.Valid || row.UserID.UUID != *requesterID) {
+			node.Feedback = ""
+		}
+		for _, child := range children[row.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	return &Lineage{
+		ArtifactID: artifactID,
+		Root:       build(rows[0]),
+		Size:       len(rows),
+	}
+}
@@ -0,0 +1,74 @@
+package artifacts
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+func TestBuildLineage(t *testing.T) {
+	root, regenerated, refined, refinedAgain := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	child := func(id, parent uuid.UUID, derivation, feedback string, depth int32) store.GetArtifactLineageRow {
+		return store.GetArtifactLineageRow{
+			ID:               id,
+			ParentArtifactID: uuid.NullUUID{UUID: parent, Valid: true},
+			Derivation:       sql.NullString{String: derivation, Valid: true},
+			Feedback:         feedback,
+			Status:           "READY",
+			CreatedAt:        now,
+			Depth:            depth,
+		}
+	}
+
+	lineage := buildLineage(refined, []store.GetArtifactLineageRow{
+		{ID: root, Status: "READY", CreatedAt: now},
+		child(regenerated, root, DerivationRegenerate, "", 1),
+		child(refined, root, DerivationRefine, "Make them harder", 1),
+		child(refinedAgain, refined, DerivationRefine, "Fewer questions", 2),
+	}, nil)
+
+	assert.Equal(t, refined, lineage.ArtifactID)
+	assert.Equal(t, 4, lineage.Size)
+	assert.Equal(t, root, lineage.Root.ID)
+	assert.Nil(t, lineage.Root.Derivation)
+	require.Len(t, lineage.Root.Children, 2)
+	assert.Equal(t, regenerated, lineage.Root.Children[0].ID)
+	assert.Empty(t, lineage.Root.Children[0].Children)
+
+	refinedNode := lineage.Root.Children[1]
+	assert.Equal(t, DerivationRefine, *refinedNode.Derivation)
+	assert.Equal(t, "Make them harder", refinedNode.Feedback)
+	require.Len(t, refinedNode.Children, 1)
+	assert.Equal(t, refinedAgain, refinedNode.Children[0].ID)
+	assert.Equal(t, refined, *refinedNode.Children[0].ParentArtifactID)
+}
+
+func TestBuildLineage_HidesOtherUsersFeedback(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	root, mine, theirs := uuid.New(), uuid.New(), uuid.New()
+	row := func(id uuid.UUID, userID uuid.UUID, feedback string) store.GetArtifactLineageRow {
+		return store.GetArtifactLineageRow{
+			ID:               id,
+			ParentArtifactID: uuid.NullUUID{UUID: root, Valid: id != root},
+			UserID:           uuid.NullUUID{UUID: userID, Valid: true},
+			Feedback:         feedback,
+			Status:           "READY",
+		}
+	}
+	rows := []store.GetArtifactLineageRow{row(root, owner, ""), row(mine, owner, "Shorter"), row(theirs, other, "Add sources")}
+
+	lineage := buildLineage(root, rows, &owner)
+	require.Len(t, lineage.Root.Children, 2)
+	assert.Equal(t, "Shorter", lineage.Root.Children[0].Feedback)
+	assert.Empty(t, lineage.Root.Children[1].Feedback)
+
+	lineage = buildLineage(root, rows, nil)
+	assert.Equal(t, "Add sources", lineage.Root.Children[1].Feedback)
+}
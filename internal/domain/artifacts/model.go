@@ -2,28 +2,69 @@ package artifacts
 
 import (
 	"database/sql"
+	"encoding/json"
 	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 // Artifact represents an artifact for API responses (Swagger-friendly)
 type Artifact struct {
-	ID               uuid.UUID  `json:"id"`
-	Type             string     `json:"type"`
-	Status           string     `json:"status"`
-	EvalID           *uuid.UUID `json:"eval_id,omitempty"`
-	EvalItemID       *uuid.UUID `json:"eval_item_id,omitempty"`
-	AttemptID        *uuid.UUID `json:"attempt_id,omitempty"`
-	ReviewerID       *uuid.UUID `json:"reviewer_id,omitempty"`
-	Text             *string    `json:"text,omitempty"`
-	Model            *string    `json:"model,omitempty"`
-	Prompt           *string    `json:"prompt,omitempty"`
-	InputHash        *string    `json:"input_hash,omitempty"`
-	Error            *string    `json:"error,omitempty"`
-	CreatedAt        time.Time  `json:"created_at"`
-	PromptTemplateID *uuid.UUID `json:"prompt_template_id,omitempty"`
+	ID                uuid.UUID       `json:"id"`
+	Type              string          `json:"type"`
+	GenerationType    *string         `json:"generation_type,omitempty"`
+	Status            string          `json:"status"`
+	UserID            *uuid.UUID      `json:"user_id,omitempty"`
+	EvalID            *uuid.UUID      `json:"eval_id,omitempty"`
+	EvalItemID        *uuid.UUID      `json:"eval_item_id,omitempty"`
+	AttemptID         *uuid.UUID      `json:"attempt_id,omitempty"`
+	ThreadID          *uuid.UUID      `json:"thread_id,omitempty"`
+	ReviewerID        *uuid.UUID      `json:"reviewer_id,omitempty"`
+	Text              *string         `json:"text,omitempty"`
+	OutputJSON        json.RawMessage `json:"output_json,omitempty" swaggertype:"object"`
+	Model             *string         `json:"model,omitempty"`
+	Prompt            *string         `json:"prompt,omitempty"`
+	PromptRender      *string         `json:"prompt_render,omitempty"`
+	ModelParams       json.RawMessage `json:"model_params,omitempty" swaggertype:"object"`
+	Meta              json.RawMessage `json:"meta,omitempty" swaggertype:"object"`
+	InputHash         *string         `json:"input_hash,omitempty"`
+	Error             *string         `json:"error,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	PromptTemplateID  *uuid.UUID      `json:"prompt_template_id,omitempty"`
+	SchemaTemplateID  *uuid.UUID      `json:"schema_template_id,omitempty"`
+	PromptTokens      *int32          `json:"prompt_tokens,omitempty"`
+	CandidateTokens   *int32          `json:"candidate_tokens,omitempty"`
+	CachedTokens      *int32          `json:"cached_tokens,omitempty"`
+	TotalTokens       *int32          `json:"total_tokens,omitempty"`
+	CostUSD           *float64        `json:"cost_usd,omitempty"`
+	ParentArtifactID  *uuid.UUID      `json:"parent_artifact_id,omitempty"`
+	Derivation        *string         `json:"derivation,omitempty" example:"REFINE"`
+	ReviewRequestedAt *time.Time      `json:"review_requested_at,omitempty"`
+	ReviewedAt        *time.Time      `json:"reviewed_at,omitempty"`
+}
+
+// UsageBreakdown is the token usage and spend of one group in a usage report.
+// Key is the user ID, generation type, document ID or day (YYYY-MM-DD) the
+// row was grouped by; it is empty for artifacts without a user or type.
+type UsageBreakdown struct {
+	Key             string  `json:"key"`
+	ArtifactCount   int64   `json:"artifact_count"`
+	PromptTokens    int64   `json:"prompt_tokens"`
+	CandidateTokens int64   `json:"candidate_tokens"`
+	CachedTokens    int64   `json:"cached_tokens"`
+	TotalTokens     int64   `json:"total_tokens"`
+	CostUSD         float64 `json:"cost_usd"`
+}
+
+// UsageReport is the response of the usage breakdown endpoints.
+type UsageReport struct {
+	GroupBy string           `json:"group_by"`
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Data    []UsageBreakdown `json:"data"`
 }
 
 // ArtifactListResponse represents a paginated response for artifacts
@@ -45,20 +86,37 @@ type PaginationMeta struct {
 // ConvertFromStore converts a store.Artifact to domain Artifact
 func ConvertFromStore(storeArtifact store.Artifact) Artifact {
 	return Artifact{
-		ID:               storeArtifact.ID,
-		Type:             storeArtifact.Type,
-		Status:           storeArtifact.Status,
-		EvalID:           toUUIDPtr(storeArtifact.EvalID),
-		EvalItemID:       toUUIDPtr(storeArtifact.EvalItemID),
-		AttemptID:        toUUIDPtr(storeArtifact.AttemptID),
-		ReviewerID:       toUUIDPtr(storeArtifact.ReviewerID),
-		Text:             toStringPtr(storeArtifact.Text),
-		Model:            toStringPtr(storeArtifact.Model),
-		Prompt:           toStringPtr(storeArtifact.Prompt),
-		InputHash:        toStringPtr(storeArtifact.InputHash),
-		Error:            toStringPtr(storeArtifact.Error),
-		CreatedAt:        storeArtifact.CreatedAt,
-		PromptTemplateID: toUUIDPtr(storeArtifact.PromptTemplateID),
+		ID:                storeArtifact.ID,
+		Type:              storeArtifact.Type,
+		GenerationType:    toGenerationTypePtr(storeArtifact.GenerationType),
+		Status:            storeArtifact.Status,
+		UserID:            toUUIDPtr(storeArtifact.UserID),
+		EvalID:            toUUIDPtr(storeArtifact.EvalID),
+		EvalItemID:        toUUIDPtr(storeArtifact.EvalItemID),
+		AttemptID:         toUUIDPtr(storeArtifact.AttemptID),
+		ThreadID:          toUUIDPtr(storeArtifact.ThreadID),
+		ReviewerID:        toUUIDPtr(storeArtifact.ReviewerID),
+		Text:              toStringPtr(storeArtifact.Text),
+		OutputJSON:        toRawMessage(storeArtifact.OutputJson),
+		Model:             toStringPtr(storeArtifact.Model),
+		Prompt:            toStringPtr(storeArtifact.Prompt),
+		PromptRender:      toStringPtr(storeArtifact.PromptRender),
+		ModelParams:       toRawMessage(storeArtifact.ModelParams),
+		Meta:              toRawMessage(storeArtifact.Meta),
+		InputHash:         toStringPtr(storeArtifact.InputHash),
+		Error:             toStringPtr(storeArtifact.Error),
+		CreatedAt:         storeArtifact.CreatedAt,
+		PromptTemplateID:  toUUIDPtr(storeArtifact.PromptTemplateID),
+		SchemaTemplateID:  toUUIDPtr(storeArtifact.SchemaTemplateID),
+		PromptTokens:      utils.NullInt32ToPtr(storeArtifact.PromptTokens),
+		CandidateTokens:   utils.NullInt32ToPtr(storeArtifact.CandidateTokens),
+		CachedTokens:      utils.NullInt32ToPtr(storeArtifact.CachedTokens),
+		TotalTokens:       utils.NullInt32ToPtr(storeArtifact.TotalTokens),
+		CostUSD:           utils.NullFloat64ToPtr(storeArtifact.CostUsd),
+		ParentArtifactID:  toUUIDPtr(storeArtifact.ParentArtifactID),
+		Derivation:        toStringPtr(storeArtifact.Derivation),
+		ReviewRequestedAt: utils.NullTimeToPtr(storeArtifact.ReviewRequestedAt),
+		ReviewedAt:        utils.NullTimeToPtr(storeArtifact.ReviewedAt),
 	}
 }
 
@@ -75,3 +133,18 @@ func toStringPtr(s sql.NullString) *string {
 	}
 	return &s.String
 }
+
+func toRawMessage(m pqtype.NullRawMessage) json.RawMessage {
+	if !m.Valid {
+		return nil
+	}
+	return m.RawMessage
+}
+
+func toGenerationTypePtr(g store.NullGenerationType) *string {
+	if !g.Valid {
+		return nil
+	}
+	value := string(g.GenerationType)
+	return &value
+}
@@ -0,0 +1,289 @@
+package artifacts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
+)
+
+// Artifact statuses of the review workflow. Generation leaves a successful
+// artifact READY; assigning a reviewer moves it to IN_REVIEW and the reviewer
+// approves or rejects it.
+const (
+	StatusReady    = "READY"
+	StatusInReview = "IN_REVIEW"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+)
+
+// Review actions recorded in an artifact's review history.
+const (
+	ReviewActionAssign  = "ASSIGN"
+	ReviewActionComment = "COMMENT"
+	ReviewActionApprove = "APPROVE"
+	ReviewActionReject  = "REJECT"
+)
+
+const maxReviewCommentLength = 4000
+
+// ArtifactReview is the review state of an artifact with its history.
+// @Description Review status, reviewer and history of an artifact
+type ArtifactReview struct {
+	ArtifactID        uuid.UUID     `json:"artifact_id"`
+	Status            string        `json:"status" example:"IN_REVIEW"`
+	ReviewerID        *uuid.UUID    `json:"reviewer_id,omitempty"`
+	ReviewRequestedAt *time.Time    `json:"review_requested_at,omitempty"`
+	ReviewedAt        *time.Time    `json:"reviewed_at,omitempty"`
+	Events            []ReviewEvent `json:"events"`
+}
+
+// ReviewEvent is one assignment, comment or decision on an artifact.
+type ReviewEvent struct {
+	ID         uuid.UUID  `json:"id"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"`
+	Action     string     `json:"action" example:"REJECT"`
+	FromStatus string     `json:"from_status" example:"IN_REVIEW"`
+	ToStatus   string     `json:"to_status" example:"REJECTED"`
+	ReviewerID *uuid.UUID `json:"reviewer_id,omitempty"`
+	Comment    *string    `json:"comment,omitempty" example:"Question 3 is not supported by the source"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// AssignReviewerRequest assigns a teacher or admin to review an artifact.
+type AssignReviewerRequest struct {
+	ReviewerID uuid.UUID `json:"reviewer_id"`
+	Comment    string    `json:"comment,omitempty"`
+}
+
+// ReviewCommentRequest carries the comment of a review action. Rejections
+// require one.
+type ReviewCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+// nextReviewStatus returns the status an artifact in status moves to when
+// action is applied.
+func nextReviewStatus(status, action string) (string, error) {
+	switch action {
+	case ReviewActionAssign:
+		if status == StatusReady || status == StatusInReview {
+			return StatusInReview, nil
+		}
+	case ReviewActionApprove:
+		if status == StatusInReview {
+			return StatusApproved, nil
+		}
+	case ReviewActionReject:
+		if status == StatusInReview {
+			return StatusRejected, nil
+		}
+	case ReviewActionComment:
+		switch status {
+		case StatusReady, StatusInReview, StatusApproved, StatusRejected:
+			return status, nil
+		}
+	default:
+		return "", fmt.Errorf("%w: unknown action %q", ErrInvalidReviewTransition, action)
+	}
+	return "", fmt.Errorf("%w: cannot %s an artifact that is %s", ErrInvalidReviewTransition, strings.ToLower(action), status)
+}
+
+// AssignReviewer puts an artifact in review with reviewerID as its reviewer.
+// Only the user who requested the artifact or an admin may assign, and the
+// requester cannot be its reviewer. Assigning again while in review replaces
+// the reviewer.
+func (s *Service) AssignReviewer(ctx context.Context, id, actorID uuid.UUID, req AssignReviewerRequest, actorIsAdmin bool) (*ArtifactReview, error) {
+	comment, err := reviewComment(req.Comment, false)
+	if err != nil {
+		return nil, err
+	}
+	reviewer, err := s.queries.GetUser(ctx, req.ReviewerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: user %s not found", ErrInvalidReviewer, req.ReviewerID)
+		}
+		return nil, fmt.Errorf("failed to get reviewer: %w", err)
+	}
+	if !reviewer.IsTeacher && !reviewer.IsAdmin {
+		return nil, fmt.Errorf("%w: user %s is not a teacher or admin", ErrInvalidReviewer, req.ReviewerID)
+	}
+
+	reviewerID := uuid.NullUUID{UUID: reviewer.ID, Valid: true}
+	return s.applyReview(ctx, id, actorID, ReviewActionAssign, comment, reviewerID, func(q *store.Queries, artifact store.Artifact, _ string) (store.Artifact, error) {
+		if !actorIsAdmin && !isRequester(artifact, actorID) {
+			return store.Artifact{}, ErrNotArtifactOwner
+		}
+		if isRequester(artifact, reviewer.ID) {
+			return store.Artifact{}, fmt.Errorf("%w: the requester of an artifact cannot review it", ErrInvalidReviewer)
+		}
+		return q.AssignArtifactReviewer(ctx, store.AssignArtifactReviewerParams{
+			ID:         artifact.ID,
+			ReviewerID: reviewerID,
+			FromStatus: artifact.Status,
+		})
+	})
+}
+
+// DecideReview approves or rejects an artifact in review. Only the assigned
+// reviewer or an admin may decide, never the artifact's requester, and a
+// rejection needs a comment.
+func (s *Service) DecideReview(ctx context.Context, id, actorID uuid.UUID, action string, req ReviewCommentRequest, actorIsAdmin bool) (*ArtifactReview, error) {
+	if action != ReviewActionApprove && action != ReviewActionReject {
+		return nil, fmt.Errorf("%w: unknown decision %q", ErrInvalidReviewTransition, action)
+	}
+	comment, err := reviewComment(req.Comment, action == ReviewActionReject)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyReview(ctx, id, actorID, action, comment, uuid.NullUUID{}, func(q *store.Queries, artifact store.Artifact, toStatus string) (store.Artifact, error) {
+		if !actorIsAdmin && (!artifact.ReviewerID.Valid || artifact.ReviewerID.UUID != actorID || isRequester(artifact, actorID)) {
+			return store.Artifact{}, ErrNotReviewer
+		}
+		return q.CompleteArtifactReview(ctx, store.CompleteArtifactReviewParams{ID: artifact.ID, ToStatus: toStatus})
+	})
+}
+
+// CommentReview adds a comment to an artifact's review history without
+// changing its status.
+func (s *Service) CommentReview(ctx context.Context, id, actorID uuid.UUID, req ReviewCommentRequest) (*ArtifactReview, error) {
+	comment, err := reviewComment(req.Comment, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyReview(ctx, id, actorID, ReviewActionComment, comment, uuid.NullUUID{}, nil)
+}
+
+// GetReview returns the review state and history of an artifact.
+func (s *Service) GetReview(ctx context.Context, id uuid.UUID) (*ArtifactReview, error) {
+	artifact, err := s.queries.GetArtifact(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	events, err := s.queries.ListArtifactReviewEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact review events: %w", err)
+	}
+	return toArtifactReview(artifact, events), nil
+}
+
+// ListReviewQueue returns the artifacts assigned to reviewerID, optionally
+// limited to one status.
+func (s *Service) ListReviewQueue(ctx context.Context, reviewerID uuid.UUID, status string, limit, offset int32) ([]store.Artifact, error) {
+	artifacts, err := s.queries.ListArtifactsByReviewerAndStatus(ctx, store.ListArtifactsByReviewerAndStatusParams{
+		ReviewerID: uuid.NullUUID{UUID: reviewerID, Valid: true},
+		Status:     utils.ToNullString(status),
+		RowLimit:   limit,
+		RowOffset:  offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts by reviewer: %w", err)
+	}
+	return artifacts, nil
+}
+
+// applyReview checks action against the artifact's status, runs update (when
+// the action changes the artifact) and records the event in one transaction.
+// update only succeeds while the artifact is still in the status that was
+// checked, so concurrent reviews cannot both apply.
+func (s *Service) applyReview(ctx context.Context, id, actorID uuid.UUID, action string, comment sql.NullString, reviewerID uuid.NullUUID,
+	update func(q *store.Queries, artifact store.Artifact, toStatus string) (store.Artifact, error)) (*ArtifactReview, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	q := s.queries.WithTx(tx)
+
+	artifact, err := q.GetArtifact(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	toStatus, err := nextReviewStatus(artifact.Status, action)
+	if err != nil {
+		return nil, err
+	}
+
+	if update != nil {
+		if _, err := update(q, artifact, toStatus); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: artifact status changed concurrently", ErrInvalidReviewTransition)
+			}
+			if errors.Is(err, ErrNotReviewer) || errors.Is(err, ErrNotArtifactOwner) || errors.Is(err, ErrInvalidReviewer) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to update artifact review: %w", err)
+		}
+	}
+
+	if _, err := q.CreateArtifactReviewEvent(ctx, store.CreateArtifactReviewEventParams{
+		ArtifactID: artifact.ID,
+		ActorID:    uuid.NullUUID{UUID: actorID, Valid: actorID != uuid.Nil},
+		Action:     action,
+		FromStatus: artifact.Status,
+		ToStatus:   toStatus,
+		ReviewerID: reviewerID,
+		Comment:    comment,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create artifact review event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit artifact review: %w", err)
+	}
+	return s.GetReview(ctx, id)
+}
+
+// isRequester reports whether userID requested artifact.
+func isRequester(artifact store.Artifact, userID uuid.UUID) bool {
+	return artifact.UserID.Valid && artifact.UserID.UUID == userID
+}
+
+func reviewComment(comment string, required bool) (sql.NullString, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" && required {
+		return sql.NullString{}, fmt.Errorf("%w: comment is required", ErrInvalidReviewComment)
+	}
+	if len(comment) > maxReviewCommentLength {
+		return sql.NullString{}, fmt.Errorf("%w: comment must be at most %d characters", ErrInvalidReviewComment, maxReviewCommentLength)
+	}
+	return utils.ToNullString(comment), nil
+}
+
+func toArtifactReview(artifact store.Artifact, events []store.ArtifactReviewEvent) *ArtifactReview {
+	review := &ArtifactReview{
+		ArtifactID:        artifact.ID,
+		Status:            artifact.Status,
+		ReviewerID:        toUUIDPtr(artifact.ReviewerID),
+		ReviewRequestedAt: utils.NullTimeToPtr(artifact.ReviewRequestedAt),
+		ReviewedAt:        utils.NullTimeToPtr(artifact.ReviewedAt),
+		Events:            make([]ReviewEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		review.Events = append(review.Events, ReviewEvent{
+			ID:         event.ID,
+			ActorID:    toUUIDPtr(event.ActorID),
+			Action:     event.Action,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ReviewerID: toUUIDPtr(event.ReviewerID),
+			Comment:    toStringPtr(event.Comment),
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return review
+}
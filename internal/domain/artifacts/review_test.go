@@ -0,0 +1,55 @@
+package artifacts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextReviewStatus(t *testing.T) {
+	allowed := []struct{ status, action, next string }{
+		{StatusReady, ReviewActionAssign, StatusInReview},
+		{StatusInReview, ReviewActionAssign, StatusInReview},
+		{StatusInReview, ReviewActionApprove, StatusApproved},
+		{StatusInReview, ReviewActionReject, StatusRejected},
+		{StatusReady, ReviewActionComment, StatusReady},
+		{StatusRejected, ReviewActionComment, StatusRejected},
+	}
+	for _, tc := range allowed {
+		next, err := nextReviewStatus(tc.status, tc.action)
+		require.NoError(t, err, "%s %s", tc.action, tc.status)
+		assert.Equal(t, tc.next, next)
+	}
+
+	rejected := []struct{ status, action string }{
+		{StatusReady, ReviewActionApprove},
+		{StatusReady, ReviewActionReject},
+		{StatusApproved, ReviewActionAssign},
+		{StatusApproved, ReviewActionReject},
+		{StatusRejected, ReviewActionApprove},
+		{"ERROR", ReviewActionAssign},
+		{"INVALID", ReviewActionComment},
+		{StatusInReview, "ESCALATE"},
+	}
+	for _, tc := range rejected {
+		_, err := nextReviewStatus(tc.status, tc.action)
+		assert.ErrorIs(t, err, ErrInvalidReviewTransition, "%s %s", tc.action, tc.status)
+	}
+}
+
+func TestReviewComment(t *testing.T) {
+	comment, err := reviewComment("  Looks good  ", false)
+	require.NoError(t, err)
+	assert.Equal(t, "Looks good", comment.String)
+
+	comment, err = reviewComment(" ", false)
+	require.NoError(t, err)
+	assert.False(t, comment.Valid)
+
+	_, err = reviewComment(" ", true)
+	assert.ErrorIs(t, err, ErrInvalidReviewComment)
+	_, err = reviewComment(strings.Repeat("x", maxReviewCommentLength+1), false)
+	assert.ErrorIs(t, err, ErrInvalidReviewComment)
+}
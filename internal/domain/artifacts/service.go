@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -35,6 +37,7 @@ type CreateArtifactParams struct {
 	EvalID           uuid.NullUUID
 	EvalItemID       uuid.NullUUID
 	AttemptID        uuid.NullUUID
+	ThreadID         uuid.NullUUID
 	Text             string
 	OutputJSON       json.RawMessage
 	Model            string
@@ -46,6 +49,13 @@ type CreateArtifactParams struct {
 	ModelParams      json.RawMessage
 	Meta             json.RawMessage
 	Error            string
+	PromptTokens     *int32
+	CandidateTokens  *int32
+	CachedTokens     *int32
+	TotalTokens      *int32
+	CostUSD          *float64
+	ParentArtifactID uuid.NullUUID
+	Derivation       string
 }
 
 func (s *Service) CreateArtifact(ctx context.Context, params CreateArtifactParams) (*store.Artifact, error) {
@@ -69,6 +79,15 @@ func (s *Service) CreateArtifact(ctx context.Context, params CreateArtifactParam
 		InputHash:        utils.ToNullString(params.InputHash),
 		Meta:             utils.ToNullRawMessage(params.Meta),
 		Error:            utils.ToNullString(params.Error),
+		UserID:           uuid.NullUUID{UUID: params.UserID, Valid: params.UserID != uuid.Nil},
+		PromptTokens:     utils.SqlNullInt32(params.PromptTokens),
+		CandidateTokens:  utils.SqlNullInt32(params.CandidateTokens),
+		CachedTokens:     utils.SqlNullInt32(params.CachedTokens),
+		TotalTokens:      utils.SqlNullInt32(params.TotalTokens),
+		CostUsd:          utils.SqlNullFloat64(params.CostUSD),
+		ThreadID:         params.ThreadID,
+		ParentArtifactID: params.ParentArtifactID,
+		Derivation:       utils.ToNullString(params.Derivation),
 	}
 
 	artifact, err := s.queries.CreateArtifact(ctx, storeParams)
@@ -153,6 +172,91 @@ func (s *Service) GetArtifactByID(ctx context.Context, id uuid.UUID) (*store.Art
 	return &artifact, nil
 }
 
+// ListGenerationArtifacts returns paginated artifacts produced by the generation service
+func (s *Service) ListGenerationArtifacts(ctx context.Context, limit, offset int32) ([]store.Artifact, int64, error) {
+	artifacts, err := s.queries.ListGenerationArtifacts(ctx, store.ListGenerationArtifactsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list generation artifacts: %w", err)
+	}
+
+	total, err := s.queries.CountGenerationArtifacts(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count generation artifacts: %w", err)
+	}
+
+	return artifacts, total, nil
+}
+
+// ListGenerationArtifactsByUser returns paginated generation artifacts requested by a user
+func (s *Service) ListGenerationArtifactsByUser(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]store.Artifact, int64, error) {
+	artifacts, err := s.queries.ListGenerationArtifactsByUser(ctx, store.ListGenerationArtifactsByUserParams{
+		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list generation artifacts by user: %w", err)
+	}
+
+	total, err := s.queries.CountGenerationArtifactsByUser(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count generation artifacts by user: %w", err)
+	}
+
+	return artifacts, total, nil
+}
+
+// GetLatestReusableArtifactByInputHash returns the most recent artifact produced
+// from the same inputs whose output can be reused: an APPROVED one when there
+// is one, otherwise a READY one.
+func (s *Service) GetLatestReusableArtifactByInputHash(ctx context.Context, inputHash string) (*store.Artifact, error) {
+	artifact, err := s.queries.GetLatestReusableArtifactByInputHash(ctx, utils.ToNullString(inputHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact by input hash: %w", err)
+	}
+	return &artifact, nil
+}
+
+// GetLineage returns the lineage tree containing an artifact, starting at the
+// artifact it was originally generated as. When requesterID is set the
+// artifact must have been requested by that user.
+func (s *Service) GetLineage(ctx context.Context, id uuid.UUID, requesterID *uuid.UUID) (*Lineage, error) {
+	rows, err := s.queries.GetArtifactLineage(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact lineage: %w", err)
+	}
+	requested := false
+	for _, row := range rows {
+		if row.ID == id {
+			requested = requesterID == nil || (row.UserID.Valid && row.UserID.UUID == *requesterID)
+			break
+		}
+	}
+	if !requested {
+		return nil, fmt.Errorf("failed to get artifact lineage: %w", sql.ErrNoRows)
+	}
+	return buildLineage(id, rows, requesterID), nil
+}
+
+// getRequestedArtifact returns artifact id, limited to the artifacts requested
+// by requesterID when it is set.
+func (s *Service) getRequestedArtifact(ctx context.Context, id uuid.UUID, requesterID *uuid.UUID) (store.Artifact, error) {
+	artifact, err := s.queries.GetArtifact(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.Artifact{}, fmt.Errorf("%w: %s", ErrArtifactNotFound, id)
+		}
+		return store.Artifact{}, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	if requesterID != nil && !isRequester(artifact, *requesterID) {
+		return store.Artifact{}, fmt.Errorf("%w: %s", ErrArtifactNotFound, id)
+	}
+	return artifact, nil
+}
+
 // GetArtifactStats returns statistics about artifacts
 func (s *Service) GetArtifactStats(ctx context.Context) (*store.GetArtifactStatsRow, error) {
 	stats, err := s.queries.GetArtifactStats(ctx)
@@ -161,3 +265,68 @@ func (s *Service) GetArtifactStats(ctx context.Context) (*store.GetArtifactStats
 	}
 	return &stats, nil
 }
+
+// Usage report groupings.
+const (
+	UsageByUser           = "user"
+	UsageByGenerationType = "generation_type"
+	UsageByDocument       = "document"
+	UsageByDay            = "day"
+)
+
+// GetUsage returns token usage and spend for artifacts created in [from, to),
+// grouped by user, generation type, document or day.
+func (s *Service) GetUsage(ctx context.Context, groupBy string, from, to time.Time) ([]UsageBreakdown, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidDateRange)
+	}
+
+	var (
+		breakdown []UsageBreakdown
+		err       error
+	)
+	switch groupBy {
+	case UsageByUser:
+		var rows []store.GetArtifactUsageByUserRow
+		rows, err = s.queries.GetArtifactUsageByUser(ctx, store.GetArtifactUsageByUserParams{FromTime: from, ToTime: to})
+		for _, row := range rows {
+			key := ""
+			if row.UserID.Valid {
+				key = row.UserID.UUID.String()
+			}
+			breakdown = append(breakdown, UsageBreakdown{key, row.ArtifactCount, row.PromptTokens, row.CandidateTokens, row.CachedTokens, row.TotalTokens, row.CostUsd})
+		}
+	case UsageByGenerationType:
+		var rows []store.GetArtifactUsageByGenerationTypeRow
+		rows, err = s.queries.GetArtifactUsageByGenerationType(ctx, store.GetArtifactUsageByGenerationTypeParams{FromTime: from, ToTime: to})
+		for _, row := range rows {
+			key := ""
+			if row.GenerationType.Valid {
+				key = string(row.GenerationType.GenerationType)
+			}
+			breakdown = append(breakdown, UsageBreakdown{key, row.ArtifactCount, row.PromptTokens, row.CandidateTokens, row.CachedTokens, row.TotalTokens, row.CostUsd})
+		}
+	case UsageByDocument:
+		var rows []store.GetArtifactUsageByDocumentRow
+		rows, err = s.queries.GetArtifactUsageByDocument(ctx, store.GetArtifactUsageByDocumentParams{FromTime: from, ToTime: to})
+		for _, row := range rows {
+			breakdown = append(breakdown, UsageBreakdown{row.DocumentID, row.ArtifactCount, row.PromptTokens, row.CandidateTokens, row.CachedTokens, row.TotalTokens, row.CostUsd})
+		}
+	case UsageByDay:
+		var rows []store.GetArtifactUsageByDayRow
+		rows, err = s.queries.GetArtifactUsageByDay(ctx, store.GetArtifactUsageByDayParams{FromTime: from, ToTime: to})
+		for _, row := range rows {
+			breakdown = append(breakdown, UsageBreakdown{row.Day.Format(time.DateOnly), row.ArtifactCount, row.PromptTokens, row.CandidateTokens, row.CachedTokens, row.TotalTokens, row.CostUsd})
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUsageGroup, groupBy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact usage by %s: %w", groupBy, err)
+	}
+
+	if breakdown == nil {
+		breakdown = []UsageBreakdown{}
+	}
+	return breakdown, nil
+}
@@ -463,7 +463,7 @@ func (s *Service) downloadAndProcessPDF(ctx context.Context, book BookDownloadIn
 	if s.generationService != nil {
 		go func() {
 			log.Printf("[PDF_CLASSIFICATION] Starting classification generation for document: %s", doc.ID)
-			err := s.generateClassificationArtifacts(context.Background(), doc.ID, book.Title)
+			err := s.generateClassificationArtifacts(context.Background(), doc.ID, book.Title, userID)
 			if err != nil {
 				log.Printf("[PDF_CLASSIFICATION] FAILED for document %s: %v", doc.ID, err)
 			} else {
@@ -513,7 +513,7 @@ func sanitizeFilename(title string) string {
 }
 
 // generateClassificationArtifacts triggers classification generation for a document using the file search store
-func (s *Service) generateClassificationArtifacts(ctx context.Context, documentID uuid.UUID, title string) error {
+func (s *Service) generateClassificationArtifacts(ctx context.Context, documentID uuid.UUID, title string, userID uuid.UUID) error {
 	log.Printf("[PDF_CLASSIFICATION] Generating classification artifacts for document: %s (%s)", documentID, title)
 
 	// Create file search tool config with the document's file search store reference
@@ -528,7 +528,7 @@ func (s *Service) generateClassificationArtifacts(ctx context.Context, documentI
 
 	// Create generation request for classification
 	generateReq := generation.GenerateRequest{
-		UserID: uuid.New(), // TODO: Get actual user ID from context
+		UserID: userID,
 		Target: generation.Target{
 			DocumentID: &documentID,
 		},
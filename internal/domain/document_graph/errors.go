@@ -0,0 +1,12 @@
+package document_graph
+
+import "errors"
+
+// Domain errors for the document graph
+var (
+	ErrArtifactNotFound        = errors.New("artifact not found")
+	ErrInvalidSectionsArtifact = errors.New("artifact cannot be ingested as document sections")
+	ErrDocumentMismatch        = errors.New("artifact was generated for a different document")
+	ErrNoParagraphs            = errors.New("document graph has no paragraphs; build the graph first")
+	ErrInvalidSectionFilter    = errors.New("invalid section filter")
+)
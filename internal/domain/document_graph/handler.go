@@ -1,6 +1,7 @@
 package document_graph
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -10,11 +11,12 @@ import (
 )
 
 type Handler struct {
-	service *Service
+	service  *Service
+	sections *SectionService
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, sections *SectionService) *Handler {
+	return &Handler{service: service, sections: sections}
 }
 
 func (h *Handler) RegisterPublicRoutes(r chi.Router) {}
@@ -29,6 +31,11 @@ func (h *Handler) RegisterTeacherRoutes(r chi.Router) {
 	r.Post("/documents/{id}/graph/query", h.QueryGraph)
 }
 
+// RegisterStaffRoutes registers the routes shared by teachers and admins.
+func (h *Handler) RegisterStaffRoutes(r chi.Router) {
+	r.Post("/documents/{id}/graph/sections", h.IngestSections)
+}
+
 func (h *Handler) RegisterLearnerRoutes(r chi.Router) {}
 
 func (h *Handler) BuildGraph(w http.ResponseWriter, r *http.Request) {
@@ -78,3 +85,44 @@ func (h *Handler) QueryGraph(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, http.StatusOK, response)
 }
+
+// IngestSections adds the sections of a SECTION_TOPICS artifact to the graph.
+func (h *Handler) IngestSections(w http.ResponseWriter, r *http.Request) {
+	if h.sections == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Graph service unavailable")
+		return
+	}
+
+	documentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+
+	var req IngestSectionsRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if req.ArtifactID == uuid.Nil {
+		render.Error(w, http.StatusBadRequest, "artifact_id is required")
+		return
+	}
+
+	result, err := h.sections.IngestSections(r.Context(), documentID, req.ArtifactID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrArtifactNotFound):
+			render.Error(w, http.StatusNotFound, "Artifact not found")
+		case errors.Is(err, ErrInvalidSectionsArtifact),
+			errors.Is(err, ErrDocumentMismatch),
+			errors.Is(err, ErrNoParagraphs):
+			render.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			render.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	render.JSON(w, http.StatusOK, result)
+}
@@ -43,3 +43,46 @@ type QueryResponse struct {
 	Nodes      []Node    `json:"nodes"`
 	Edges      []Edge    `json:"edges"`
 }
+
+// NodeTypeSection marks nodes created from SECTION_TOPICS artifacts. Their
+// metadata holds SectionMetadata and they "contain" the section's paragraphs.
+const NodeTypeSection = "section"
+
+// SectionMetadata is stored as the metadata of section nodes.
+type SectionMetadata struct {
+	Title          string    `json:"title"`
+	Topic          string    `json:"topic"`
+	Difficulty     int       `json:"difficulty"`
+	Summary        string    `json:"summary"`
+	Position       int       `json:"position"`
+	ParagraphCount int       `json:"paragraph_count"`
+	ArtifactID     uuid.UUID `json:"artifact_id"`
+}
+
+// SectionFilter narrows graph searches to nodes in matching sections. Sections
+// match on title or topic, case-insensitively. A zero difficulty bound is
+// ignored; TargetDifficulty ranks sections closest to it first.
+type SectionFilter struct {
+	Sections         []string `json:"sections,omitempty"`
+	MinDifficulty    int      `json:"min_difficulty,omitempty"`
+	MaxDifficulty    int      `json:"max_difficulty,omitempty"`
+	TargetDifficulty int      `json:"target_difficulty,omitempty"`
+}
+
+// IsZero reports whether the filter neither restricts nor reorders results.
+func (f SectionFilter) IsZero() bool {
+	return len(f.Sections) == 0 && f.MinDifficulty == 0 && f.MaxDifficulty == 0 && f.TargetDifficulty == 0
+}
+
+type IngestSectionsRequest struct {
+	ArtifactID uuid.UUID `json:"artifact_id"`
+}
+
+type IngestSectionsResult struct {
+	DocumentID      uuid.UUID `json:"document_id"`
+	ArtifactID      uuid.UUID `json:"artifact_id"`
+	SectionsCreated int       `json:"sections_created"`
+	EdgesCreated    int       `json:"edges_created"`
+	// Unmatched lists section titles no paragraph heading was found for.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
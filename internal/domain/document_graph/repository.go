@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -39,50 +40,11 @@ func (r *Repository) ReplaceGraph(ctx context.Context, documentID uuid.UUID, nod
 		return fmt.Errorf("failed to clear nodes: %w", err)
 	}
 
-	if len(nodes) > 0 {
-		stmt, err := tx.PrepareContext(ctx, `
-			INSERT INTO document_graph_nodes (id, document_id, node_type, text_content, page_number, metadata)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to prepare node insert: %w", err)
-		}
-		defer stmt.Close()
-
-		for _, node := range nodes {
-			var pageNumber sql.NullInt32
-			if node.PageNumber != nil {
-				pageNumber = sql.NullInt32{Int32: int32(*node.PageNumber), Valid: true}
-			}
-			metadata := json.RawMessage(nil)
-			if len(node.Metadata) > 0 {
-				metadata = node.Metadata
-			}
-			if _, err := stmt.ExecContext(ctx, node.ID, node.DocumentID, node.NodeType, node.Text, pageNumber, metadata); err != nil {
-				return fmt.Errorf("failed to insert node: %w", err)
-			}
-		}
+	if err := insertNodes(ctx, tx, nodes); err != nil {
+		return err
 	}
-
-	if len(edges) > 0 {
-		stmt, err := tx.PrepareContext(ctx, `
-			INSERT INTO document_graph_edges (id, document_id, from_node_id, to_node_id, relation, metadata)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to prepare edge insert: %w", err)
-		}
-		defer stmt.Close()
-
-		for _, edge := range edges {
-			metadata := json.RawMessage(nil)
-			if len(edge.Metadata) > 0 {
-				metadata = edge.Metadata
-			}
-			if _, err := stmt.ExecContext(ctx, edge.ID, edge.DocumentID, edge.FromNodeID, edge.ToNodeID, edge.Relation, metadata); err != nil {
-				return fmt.Errorf("failed to insert edge: %w", err)
-			}
-		}
+	if err := insertEdges(ctx, tx, edges); err != nil {
+		return err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -208,3 +170,248 @@ func (r *Repository) FetchNeighbors(ctx context.Context, documentID uuid.UUID, n
 
 	return nodes, edges, nil
 }
+
+// ReplaceSections replaces the "section" nodes of a document and their edges,
+// leaving the rest of the graph untouched. Rebuilding the graph removes the
+// sections, so they have to be ingested again afterwards.
+func (r *Repository) ReplaceSections(ctx context.Context, documentID uuid.UUID, nodes []Node, edges []Edge) error {
+	if documentID == uuid.Nil {
+		return fmt.Errorf("document id is required")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Edges of the removed sections go with them through ON DELETE CASCADE.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM document_graph_nodes WHERE document_id = $1 AND node_type = $2", documentID, NodeTypeSection); err != nil {
+		return fmt.Errorf("failed to clear section nodes: %w", err)
+	}
+	if err := insertNodes(ctx, tx, nodes); err != nil {
+		return err
+	}
+	if err := insertEdges(ctx, tx, edges); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit section insert: %w", err)
+	}
+
+	return nil
+}
+
+func insertNodes(ctx context.Context, tx *sql.Tx, nodes []Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO document_graph_nodes (id, document_id, node_type, text_content, page_number, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare node insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, node := range nodes {
+		var pageNumber sql.NullInt32
+		if node.PageNumber != nil {
+			pageNumber = sql.NullInt32{Int32: int32(*node.PageNumber), Valid: true}
+		}
+		metadata := json.RawMessage(nil)
+		if len(node.Metadata) > 0 {
+			metadata = node.Metadata
+		}
+		if _, err := stmt.ExecContext(ctx, node.ID, node.DocumentID, node.NodeType, node.Text, pageNumber, metadata); err != nil {
+			return fmt.Errorf("failed to insert node: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertEdges(ctx context.Context, tx *sql.Tx, edges []Edge) error {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO document_graph_edges (id, document_id, from_node_id, to_node_id, relation, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare edge insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, edge := range edges {
+		metadata := json.RawMessage(nil)
+		if len(edge.Metadata) > 0 {
+			metadata = edge.Metadata
+		}
+		if _, err := stmt.ExecContext(ctx, edge.ID, edge.DocumentID, edge.FromNodeID, edge.ToNodeID, edge.Relation, metadata); err != nil {
+			return fmt.Errorf("failed to insert edge: %w", err)
+		}
+	}
+	return nil
+}
+
+// SearchNodesInSections searches nodes like SearchNodes, restricted and ordered
+// by the section containing each node. Section nodes count as their own
+// section; nodes outside any section are dropped by the section and difficulty
+// filters and ranked last when boosting a difficulty.
+func (r *Repository) SearchNodesInSections(ctx context.Context, documentID uuid.UUID, query string, filter SectionFilter, limit int) ([]Node, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	sections := make([]string, 0, len(filter.Sections))
+	for _, section := range filter.Sections {
+		sections = append(sections, strings.ToLower(strings.TrimSpace(section)))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT n.id, n.document_id, n.node_type, n.text_content, n.page_number, n.metadata, n.created_at
+		FROM document_graph_nodes n
+		LEFT JOIN LATERAL (
+			SELECT s.text_content AS title, s.metadata
+			FROM document_graph_edges e
+			JOIN document_graph_nodes s ON s.id = e.from_node_id AND s.node_type = 'section'
+			WHERE e.to_node_id = n.id AND e.relation = 'contains'
+			LIMIT 1
+		) parent ON TRUE
+		CROSS JOIN LATERAL (
+			SELECT
+				CASE WHEN n.node_type = 'section' THEN n.text_content ELSE parent.title END AS title,
+				CASE WHEN n.node_type = 'section' THEN n.metadata ELSE parent.metadata END AS metadata
+		) sec
+		WHERE n.document_id = $1 AND n.text_content ILIKE $2
+			AND (cardinality($3::text[]) = 0 OR lower(sec.title) = ANY($3) OR lower(sec.metadata->>'topic') = ANY($3))
+			AND ($4::int = 0 OR (sec.metadata->>'difficulty')::int >= $4)
+			AND ($5::int = 0 OR (sec.metadata->>'difficulty')::int <= $5)
+		ORDER BY
+			CASE WHEN $6::int > 0 THEN abs((sec.metadata->>'difficulty')::int - $6) END ASC NULLS LAST,
+			n.created_at DESC
+		LIMIT $7
+	`, documentID, "%"+query+"%", pq.Array(sections), filter.MinDifficulty, filter.MaxDifficulty, filter.TargetDifficulty, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search nodes in sections: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []Node
+	for rows.Next() {
+		var node Node
+		var pageNumber sql.NullInt32
+		var metadata json.RawMessage
+		if err := rows.Scan(&node.ID, &node.DocumentID, &node.NodeType, &node.Text, &pageNumber, &metadata, &node.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan node: %w", err)
+		}
+		if pageNumber.Valid {
+			value := int(pageNumber.Int32)
+			node.PageNumber = &value
+		}
+		node.Metadata = metadata
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate node rows: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// ListParagraphNodes returns the "paragraph" nodes of a document in reading order.
+func (r *Repository) ListParagraphNodes(ctx context.Context, documentID uuid.UUID) ([]Node, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, document_id, node_type, text_content, page_number, metadata, created_at
+		FROM document_graph_nodes
+		WHERE document_id = $1 AND node_type = 'paragraph'
+		ORDER BY page_number ASC, (metadata->>'position')::int ASC
+	`, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paragraph nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []Node
+	for rows.Next() {
+		var node Node
+		var page sql.NullInt32
+		var metadata json.RawMessage
+		if err := rows.Scan(&node.ID, &node.DocumentID, &node.NodeType, &node.Text, &page, &metadata, &node.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan paragraph node: %w", err)
+		}
+		if page.Valid {
+			value := int(page.Int32)
+			node.PageNumber = &value
+		}
+		node.Metadata = metadata
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate paragraph nodes: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// GetPageNode returns the "page" node holding the full text of a document page.
+func (r *Repository) GetPageNode(ctx context.Context, documentID uuid.UUID, pageNumber int) (*Node, error) {
+	var node Node
+	var page sql.NullInt32
+	var metadata json.RawMessage
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, document_id, node_type, text_content, page_number, metadata, created_at
+		FROM document_graph_nodes
+		WHERE document_id = $1 AND node_type = 'page' AND page_number = $2
+		LIMIT 1
+	`, documentID, pageNumber).Scan(&node.ID, &node.DocumentID, &node.NodeType, &node.Text, &page, &metadata, &node.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page node: %w", err)
+	}
+	if page.Valid {
+		value := int(page.Int32)
+		node.PageNumber = &value
+	}
+	node.Metadata = metadata
+	return &node, nil
+}
+
+// ListPageNodes returns the "page" nodes of a document in page order.
+func (r *Repository) ListPageNodes(ctx context.Context, documentID uuid.UUID) ([]Node, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, document_id, node_type, text_content, page_number, metadata, created_at
+		FROM document_graph_nodes
+		WHERE document_id = $1 AND node_type = 'page'
+		ORDER BY page_number ASC
+	`, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list page nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []Node
+	for rows.Next() {
+		var node Node
+		var page sql.NullInt32
+		var metadata json.RawMessage
+		if err := rows.Scan(&node.ID, &node.DocumentID, &node.NodeType, &node.Text, &page, &metadata, &node.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan page node: %w", err)
+		}
+		if page.Valid {
+			value := int(page.Int32)
+			node.PageNumber = &value
+		}
+		node.Metadata = metadata
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate page nodes: %w", err)
+	}
+
+	return nodes, nil
+}
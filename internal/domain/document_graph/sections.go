@@ -0,0 +1,259 @@
+package document_graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
+)
+
+const (
+	artifactStatusReady    = "READY"
+	artifactStatusApproved = "APPROVED"
+	maxSectionDifficulty   = 5
+)
+
+// SectionService materialises SECTION_TOPICS artifacts as section nodes in
+// the document graph.
+type SectionService struct {
+	repo            *Repository
+	queries         *store.Queries
+	requireApproved bool
+}
+
+// NewSectionService creates a new section service. With requireApproved only
+// artifacts approved in review can be ingested; otherwise READY ones can too.
+func NewSectionService(repo *Repository, db *sql.DB, requireApproved bool) (*SectionService, error) {
+	if repo == nil {
+		return nil, fmt.Errorf("graph repository is required")
+	}
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	return &SectionService{repo: repo, queries: store.New(db), requireApproved: requireApproved}, nil
+}
+
+type sectionTopicsOutput struct {
+	Sections []sectionTopic `json:"sections"`
+}
+
+type sectionTopic struct {
+	Title      string `json:"title"`
+	Topic      string `json:"topic"`
+	Difficulty int    `json:"difficulty"`
+	Summary    string `json:"summary"`
+}
+
+// IngestSections matches each section of a SECTION_TOPICS artifact to the
+// paragraph that heads it and creates a section node that "contains" the
+// paragraphs up to the next section's heading. Sections from an earlier ingest
+// are replaced.
+func (s *SectionService) IngestSections(ctx context.Context, documentID, artifactID uuid.UUID) (*IngestSectionsResult, error) {
+	if documentID == uuid.Nil {
+		return nil, fmt.Errorf("document id is required")
+	}
+
+	artifact, err := s.queries.GetArtifact(ctx, artifactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	sections, err := parseSectionsArtifact(artifact, documentID, s.requireApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	paragraphs, err := s.repo.ListParagraphNodes(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(paragraphs) == 0 {
+		return nil, ErrNoParagraphs
+	}
+
+	nodes, edges, unmatched := buildSectionNodes(documentID, artifact.ID, sections, paragraphs)
+	if err := s.repo.ReplaceSections(ctx, documentID, nodes, edges); err != nil {
+		return nil, err
+	}
+
+	return &IngestSectionsResult{
+		DocumentID:      documentID,
+		ArtifactID:      artifact.ID,
+		SectionsCreated: len(nodes),
+		EdgesCreated:    len(edges),
+		Unmatched:       unmatched,
+	}, nil
+}
+
+func parseSectionsArtifact(artifact store.Artifact, documentID uuid.UUID, requireApproved bool) ([]sectionTopic, error) {
+	switch {
+	case requireApproved && artifact.Status != artifactStatusApproved:
+		return nil, fmt.Errorf("%w: status is %s, expected %s", ErrInvalidSectionsArtifact, artifact.Status, artifactStatusApproved)
+	case artifact.Status != artifactStatusReady && artifact.Status != artifactStatusApproved:
+		return nil, fmt.Errorf("%w: status is %s, expected %s or %s", ErrInvalidSectionsArtifact, artifact.Status, artifactStatusReady, artifactStatusApproved)
+	}
+	if generationType := utils.GenerationType(artifact.GenerationType.GenerationType); generationType != utils.GenerationTypeSectionTopics {
+		return nil, fmt.Errorf("%w: generation type %q is not %s", ErrInvalidSectionsArtifact, generationType, utils.GenerationTypeSectionTopics)
+	}
+	if !artifact.OutputJson.Valid {
+		return nil, fmt.Errorf("%w: artifact has no output_json", ErrInvalidSectionsArtifact)
+	}
+
+	if artifact.Meta.Valid {
+		var meta struct {
+			DocumentID *uuid.UUID `json:"document_id"`
+		}
+		if err := json.Unmarshal(artifact.Meta.RawMessage, &meta); err == nil && meta.DocumentID != nil && *meta.DocumentID != documentID {
+			return nil, fmt.Errorf("%w: artifact document is %s", ErrDocumentMismatch, *meta.DocumentID)
+		}
+	}
+
+	var output sectionTopicsOutput
+	if err := json.Unmarshal(artifact.OutputJson.RawMessage, &output); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse sections: %v", ErrInvalidSectionsArtifact, err)
+	}
+	if len(output.Sections) == 0 {
+		return nil, fmt.Errorf("%w: artifact contains no sections", ErrInvalidSectionsArtifact)
+	}
+	for i, section := range output.Sections {
+		if normalizeHeading(section.Title) == "" {
+			return nil, fmt.Errorf("%w: section %d has no title", ErrInvalidSectionsArtifact, i)
+		}
+	}
+	return output.Sections, nil
+}
+
+// buildSectionNodes locates each section's heading among the paragraphs, in
+// reading order, and returns the section nodes with their "contains" edges and
+// the titles that could not be located.
+func buildSectionNodes(documentID, artifactID uuid.UUID, sections []sectionTopic, paragraphs []Node) ([]Node, []Edge, []string) {
+	type located struct {
+		section sectionTopic
+		start   int
+	}
+
+	headings := make([]string, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		headings[i] = normalizeHeading(paragraph.Text)
+	}
+
+	var found []located
+	var unmatched []string
+	cursor := 0
+	for _, section := range sections {
+		start := findHeading(headings, normalizeHeading(section.Title), cursor)
+		if start < 0 {
+			unmatched = append(unmatched, section.Title)
+			continue
+		}
+		found = append(found, located{section: section, start: start})
+		cursor = start + 1
+	}
+
+	var nodes []Node
+	var edges []Edge
+	for i, loc := range found {
+		end := len(paragraphs)
+		if i+1 < len(found) {
+			end = found[i+1].start
+		}
+
+		metadata, _ := json.Marshal(SectionMetadata{
+			Title:          loc.section.Title,
+			Topic:          loc.section.Topic,
+			Difficulty:     loc.section.Difficulty,
+			Summary:        loc.section.Summary,
+			Position:       i + 1,
+			ParagraphCount: end - loc.start,
+			ArtifactID:     artifactID,
+		})
+		sectionID := uuid.New()
+		nodes = append(nodes, Node{
+			ID:         sectionID,
+			DocumentID: documentID,
+			NodeType:   NodeTypeSection,
+			Text:       loc.section.Title,
+			PageNumber: paragraphs[loc.start].PageNumber,
+			Metadata:   metadata,
+		})
+		for _, paragraph := range paragraphs[loc.start:end] {
+			edges = append(edges, Edge{
+				ID:         uuid.New(),
+				DocumentID: documentID,
+				FromNodeID: sectionID,
+				ToNodeID:   paragraph.ID,
+				Relation:   "contains",
+			})
+		}
+	}
+
+	return nodes, edges, unmatched
+}
+
+// findHeading returns the index of the paragraph heading a section titled
+// title, searching from cursor onwards. A paragraph equal to the title wins
+// over one that merely starts with it, as body text often opens with the
+// section's subject.
+func findHeading(headings []string, title string, cursor int) int {
+	for i := cursor; i < len(headings); i++ {
+		if headings[i] == title {
+			return i
+		}
+	}
+	for i := cursor; i < len(headings); i++ {
+		if strings.HasPrefix(headings[i], title+" ") {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeHeading lower-cases text, collapses punctuation into single spaces
+// and drops leading section numbering such as "2.3" or "IV.".
+func normalizeHeading(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && isSectionNumber(words[0]) {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func isSectionNumber(word string) bool {
+	if strings.Trim(word, "0123456789") == "" {
+		return true
+	}
+	switch word {
+	case "i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix", "x":
+		return true
+	}
+	return false
+}
+
+// Validate checks that difficulty bounds lie within the SECTION_TOPICS scale.
+func (f SectionFilter) Validate() error {
+	for name, value := range map[string]int{
+		"min_difficulty":    f.MinDifficulty,
+		"max_difficulty":    f.MaxDifficulty,
+		"target_difficulty": f.TargetDifficulty,
+	} {
+		if value < 0 || value > maxSectionDifficulty {
+			return fmt.Errorf("%w: %s must be between 1 and %d", ErrInvalidSectionFilter, name, maxSectionDifficulty)
+		}
+	}
+	if f.MinDifficulty > 0 && f.MaxDifficulty > 0 && f.MinDifficulty > f.MaxDifficulty {
+		return fmt.Errorf("%w: min_difficulty is greater than max_difficulty", ErrInvalidSectionFilter)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package document_graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paragraphNodes(documentID uuid.UUID, texts ...string) []Node {
+	nodes := make([]Node, 0, len(texts))
+	for i, text := range texts {
+		page := i/3 + 1
+		nodes = append(nodes, Node{ID: uuid.New(), DocumentID: documentID, NodeType: "paragraph", Text: text, PageNumber: &page})
+	}
+	return nodes
+}
+
+func TestNormalizeHeading(t *testing.T) {
+	assert.Equal(t, "photosynthesis", normalizeHeading("2.3 Photosynthesis"))
+	assert.Equal(t, "the light reactions", normalizeHeading("IV. The Light-Reactions"))
+	assert.Equal(t, "cell respiration", normalizeHeading("  Cell   respiration: "))
+	assert.Equal(t, "1", normalizeHeading("1"))
+}
+
+func TestBuildSectionNodes(t *testing.T) {
+	documentID := uuid.New()
+	artifactID := uuid.New()
+	paragraphs := paragraphNodes(documentID,
+		"Biology Basics",
+		"1. Cells",
+		"Cells are the basic unit of life.",
+		"Every organism is made of cells.",
+		"2. Photosynthesis",
+		"Photosynthesis converts light into chemical energy.",
+	)
+
+	nodes, edges, unmatched := buildSectionNodes(documentID, artifactID, []sectionTopic{
+		{Title: "Cells", Topic: "Cell biology", Difficulty: 1, Summary: "What cells are."},
+		{Title: "Genetics", Topic: "Heredity", Difficulty: 4},
+		{Title: "Photosynthesis", Topic: "Plant energy", Difficulty: 3, Summary: "How plants make sugar."},
+	}, paragraphs)
+
+	assert.Equal(t, []string{"Genetics"}, unmatched)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, NodeTypeSection, nodes[0].NodeType)
+	assert.Equal(t, "Cells", nodes[0].Text)
+	assert.Equal(t, 1, *nodes[0].PageNumber)
+	assert.Equal(t, 2, *nodes[1].PageNumber)
+
+	var meta SectionMetadata
+	require.NoError(t, json.Unmarshal(nodes[1].Metadata, &meta))
+	assert.Equal(t, SectionMetadata{
+		Title:          "Photosynthesis",
+		Topic:          "Plant energy",
+		Difficulty:     3,
+		Summary:        "How plants make sugar.",
+		Position:       2,
+		ParagraphCount: 2,
+		ArtifactID:     artifactID,
+	}, meta)
+
+	contains := map[uuid.UUID][]uuid.UUID{}
+	for _, edge := range edges {
+		assert.Equal(t, "contains", edge.Relation)
+		contains[edge.FromNodeID] = append(contains[edge.FromNodeID], edge.ToNodeID)
+	}
+	assert.Equal(t, []uuid.UUID{paragraphs[1].ID, paragraphs[2].ID, paragraphs[3].ID}, contains[nodes[0].ID])
+	assert.Equal(t, []uuid.UUID{paragraphs[4].ID, paragraphs[5].ID}, contains[nodes[1].ID])
+}
+
+func TestBuildSectionNodes_PrefersExactHeading(t *testing.T) {
+	documentID := uuid.New()
+	paragraphs := paragraphNodes(documentID,
+		"Enzymes speed up reactions, as the next section explains.",
+		"Enzymes",
+		"They lower activation energy.",
+	)
+
+	nodes, edges, unmatched := buildSectionNodes(documentID, uuid.New(), []sectionTopic{{Title: "Enzymes", Difficulty: 2}}, paragraphs)
+	assert.Empty(t, unmatched)
+	require.Len(t, nodes, 1)
+	require.Len(t, edges, 2)
+	assert.Equal(t, paragraphs[1].ID, edges[0].ToNodeID)
+}
+
+func TestSectionFilter_Validate(t *testing.T) {
+	assert.NoError(t, SectionFilter{}.Validate())
+	assert.NoError(t, SectionFilter{Sections: []string{"Cells"}, MinDifficulty: 2, MaxDifficulty: 4, TargetDifficulty: 3}.Validate())
+	assert.ErrorIs(t, SectionFilter{MaxDifficulty: 6}.Validate(), ErrInvalidSectionFilter)
+	assert.ErrorIs(t, SectionFilter{TargetDifficulty: -1}.Validate(), ErrInvalidSectionFilter)
+	assert.ErrorIs(t, SectionFilter{MinDifficulty: 4, MaxDifficulty: 2}.Validate(), ErrInvalidSectionFilter)
+	assert.True(t, SectionFilter{}.IsZero())
+	assert.False(t, SectionFilter{TargetDifficulty: 2}.IsZero())
+}
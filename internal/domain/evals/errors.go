@@ -20,4 +20,7 @@ var (
 	ErrCannotDeletePublished   = errors.New("cannot delete published evaluation")
 	ErrEvalHasItems            = errors.New("evaluation has items and cannot be deleted")
 	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrArtifactNotFound        = errors.New("artifact not found")
+	ErrArtifactNotIngestible   = errors.New("artifact cannot be ingested")
+	ErrArtifactAlreadyIngested = errors.New("artifact is already linked to an evaluation")
 )
@@ -0,0 +1,303 @@
+package evals
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
+)
+
+// Artifact statuses that can be ingested: READY is a successful generation,
+// APPROVED one that passed review.
+const (
+	artifactStatusReady    = "READY"
+	artifactStatusApproved = "APPROVED"
+)
+
+// IngestService converts generated question artifacts into draft evaluations.
+type IngestService struct {
+	db              *sql.DB
+	queries         *store.Queries
+	requireApproved bool
+}
+
+// NewIngestService creates a new ingest service. With requireApproved only
+// artifacts approved in review can be ingested; otherwise READY ones can too.
+func NewIngestService(db *sql.DB, requireApproved bool) *IngestService {
+	return &IngestService{db: db, queries: store.New(db), requireApproved: requireApproved}
+}
+
+type generatedQuestionsPayload struct {
+	Questions []generatedQuestion `json:"questions"`
+}
+
+// generatedQuestion covers both QUESTIONS (expected_answer) and
+// MULTIPLE_CHOICE (options, correct_idx, hint, explanation) output.
+type generatedQuestion struct {
+	ID             string   `json:"id"`
+	Question       string   `json:"question"`
+	ExpectedAnswer string   `json:"expected_answer"`
+	Options        []string `json:"options"`
+	CorrectIdx     *int32   `json:"correct_idx"`
+	Hint           string   `json:"hint"`
+	Explanation    string   `json:"explanation"`
+}
+
+// IngestArtifact creates a draft evaluation owned by userID with one item per
+// generated question, then links the artifact to it. Everything happens in one
+// transaction, so an artifact is ingested at most once. Unless actorIsAdmin,
+// userID must have requested the artifact; other artifacts are reported as not
+// found.
+func (s *IngestService) IngestArtifact(ctx context.Context, artifactID, userID uuid.UUID, req IngestArtifactRequest, actorIsAdmin bool) (*IngestResult, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if req.Difficulty != nil && !req.Difficulty.IsValid() {
+		return nil, ErrInvalidDifficulty
+	}
+
+	artifact, err := s.queries.GetArtifact(ctx, artifactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("failed to get artifact: %w", err)
+	}
+	if !actorIsAdmin && (!artifact.UserID.Valid || artifact.UserID.UUID != userID) {
+		return nil, ErrArtifactNotFound
+	}
+	if err := checkIngestible(artifact, s.requireApproved); err != nil {
+		return nil, err
+	}
+
+	questions, err := parseGeneratedQuestions(artifact.OutputJson.RawMessage)
+	if err != nil {
+		return nil, err
+	}
+	grounding, documentID := artifactGrounding(artifact.Meta)
+
+	title := fmt.Sprintf("Generated questions %s", artifact.CreatedAt.UTC().Format("2006-01-02 15:04"))
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if title == "" {
+		return nil, ErrInvalidTitle
+	}
+	if len(title) > 255 {
+		return nil, ErrTitleTooLong
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	q := s.queries.WithTx(tx)
+
+	var difficulty sql.NullString
+	if req.Difficulty != nil {
+		difficulty = sql.NullString{String: string(*req.Difficulty), Valid: true}
+	}
+	storeEval, err := q.CreateEval(ctx, store.CreateEvalParams{
+		Title:        title,
+		Description:  utils.SqlNullString(req.Description),
+		Status:       string(EvalStatusDraft),
+		Difficulty:   difficulty,
+		Instructions: utils.SqlNullString(req.Instructions),
+		UserID:       userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eval: %w", err)
+	}
+
+	items := make([]*EvalItem, 0, len(questions))
+	for _, question := range questions {
+		metadata, err := json.Marshal(map[string]any{
+			"artifact_id":     artifact.ID,
+			"generation_type": artifact.GenerationType.GenerationType,
+			"question_id":     question.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal eval item metadata: %w", err)
+		}
+
+		options, correctIdx := question.answerOptions()
+		storeItem, err := q.CreateEvalItem(ctx, store.CreateEvalItemParams{
+			EvalID:            storeEval.ID,
+			Prompt:            question.Question,
+			Options:           options,
+			CorrectIdx:        correctIdx,
+			Hint:              utils.ToNullString(question.Hint),
+			Explanation:       utils.ToNullString(question.Explanation),
+			Metadata:          utils.ToNullRawMessage(metadata),
+			GroundingMetadata: utils.ToNullRawMessage(grounding),
+			SourceDocumentID:  documentID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create eval item: %w", err)
+		}
+		items = append(items, toDomainEvalItem(storeItem))
+	}
+
+	if _, err := q.LinkArtifactEval(ctx, store.LinkArtifactEvalParams{
+		ID:     artifact.ID,
+		EvalID: uuid.NullUUID{UUID: storeEval.ID, Valid: true},
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArtifactAlreadyIngested
+		}
+		return nil, fmt.Errorf("failed to link artifact to eval: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit ingest: %w", err)
+	}
+
+	return &IngestResult{
+		ArtifactID: artifact.ID,
+		Eval:       toDomainEval(storeEval),
+		Items:      items,
+	}, nil
+}
+
+func checkIngestible(artifact store.Artifact, requireApproved bool) error {
+	if artifact.EvalID.Valid {
+		return ErrArtifactAlreadyIngested
+	}
+	switch {
+	case requireApproved && artifact.Status != artifactStatusApproved:
+		return fmt.Errorf("%w: status is %s, expected %s", ErrArtifactNotIngestible, artifact.Status, artifactStatusApproved)
+	case artifact.Status != artifactStatusReady && artifact.Status != artifactStatusApproved:
+		return fmt.Errorf("%w: status is %s, expected %s or %s", ErrArtifactNotIngestible, artifact.Status, artifactStatusReady, artifactStatusApproved)
+	}
+	generationType := utils.GenerationType(artifact.GenerationType.GenerationType)
+	if generationType != utils.GenerationTypeQuestions && generationType != utils.GenerationTypeMultipleChoice {
+		return fmt.Errorf("%w: generation type %q is not QUESTIONS or MULTIPLE_CHOICE", ErrArtifactNotIngestible, generationType)
+	}
+	if !artifact.OutputJson.Valid {
+		return fmt.Errorf("%w: artifact has no output_json", ErrArtifactNotIngestible)
+	}
+	return nil
+}
+
+func parseGeneratedQuestions(payload json.RawMessage) ([]generatedQuestion, error) {
+	var parsed generatedQuestionsPayload
+	if err := json.Unmarshal(payload, &parsed); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse questions: %v", ErrArtifactNotIngestible, err)
+	}
+	if len(parsed.Questions) == 0 {
+		return nil, fmt.Errorf("%w: artifact contains no questions", ErrArtifactNotIngestible)
+	}
+
+	for i, question := range parsed.Questions {
+		if strings.TrimSpace(question.Question) == "" {
+			return nil, fmt.Errorf("%w: question %d has no text", ErrArtifactNotIngestible, i)
+		}
+		options, correctIdx := question.answerOptions()
+		if len(options) == 0 {
+			return nil, fmt.Errorf("%w: question %d has no answer", ErrArtifactNotIngestible, i)
+		}
+		if correctIdx < 0 || int(correctIdx) >= len(options) {
+			return nil, fmt.Errorf("%w: question %d has correct_idx %d outside its %d options", ErrArtifactNotIngestible, i, correctIdx, len(options))
+		}
+	}
+	return parsed.Questions, nil
+}
+
+// answerOptions returns the item options. Open questions become a single
+// option holding the expected answer.
+func (q generatedQuestion) answerOptions() ([]string, int32) {
+	if len(q.Options) > 0 {
+		if q.CorrectIdx == nil {
+			return q.Options, -1
+		}
+		return q.Options, *q.CorrectIdx
+	}
+	if answer := strings.TrimSpace(q.ExpectedAnswer); answer != "" {
+		return []string{answer}, 0
+	}
+	return nil, 0
+}
+
+// artifactGrounding extracts the grounding metadata and source document stored
+// in artifact meta by the generation service.
+func artifactGrounding(meta pqtype.NullRawMessage) (json.RawMessage, uuid.NullUUID) {
+	if !meta.Valid {
+		return nil, uuid.NullUUID{}
+	}
+	var parsed struct {
+		Grounding       json.RawMessage `json:"grounding"`
+		GroundingChunks json.RawMessage `json:"groundingChunks"`
+		DocumentID      *uuid.UUID      `json:"document_id"`
+	}
+	if err := json.Unmarshal(meta.RawMessage, &parsed); err != nil {
+		return nil, uuid.NullUUID{}
+	}
+	// Without other metadata the generation service stores the grounding
+	// metadata as meta itself.
+	if len(parsed.Grounding) == 0 && len(parsed.GroundingChunks) > 0 {
+		parsed.Grounding = meta.RawMessage
+	}
+
+	var documentID uuid.NullUUID
+	if parsed.DocumentID != nil {
+		documentID = uuid.NullUUID{UUID: *parsed.DocumentID, Valid: true}
+	}
+	return parsed.Grounding, documentID
+}
+
+func toDomainEval(e store.Eval) *Eval {
+	eval := &Eval{
+		ID:           e.ID,
+		Title:        e.Title,
+		Description:  utils.NullStringToPtr(e.Description),
+		Status:       EvalStatus(e.Status),
+		Instructions: utils.NullStringToPtr(e.Instructions),
+		UserID:       e.UserID,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+	if e.Difficulty.Valid {
+		difficulty := DifficultyLevel(e.Difficulty.String)
+		eval.Difficulty = &difficulty
+	}
+	if e.PublishedAt.Valid {
+		eval.PublishedAt = &e.PublishedAt.Time
+	}
+	if e.ArchivedAt.Valid {
+		eval.ArchivedAt = &e.ArchivedAt.Time
+	}
+	return eval
+}
+
+func toDomainEvalItem(item store.EvalItem) *EvalItem {
+	domainItem := &EvalItem{
+		ID:          item.ID,
+		EvalID:      item.EvalID,
+		Prompt:      item.Prompt,
+		Options:     item.Options,
+		CorrectIdx:  item.CorrectIdx,
+		Hint:        utils.NullStringToPtr(item.Hint),
+		Explanation: utils.NullStringToPtr(item.Explanation),
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+	if item.Metadata.Valid {
+		domainItem.Metadata = item.Metadata.RawMessage
+	}
+	if item.GroundingMetadata.Valid {
+		domainItem.GroundingMetadata = item.GroundingMetadata.RawMessage
+	}
+	if item.SourceDocumentID.Valid {
+		domainItem.SourceDocumentID = &item.SourceDocumentID.UUID
+	}
+	return domainItem
+}
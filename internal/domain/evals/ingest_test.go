@@ -0,0 +1,99 @@
+package evals
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+func TestParseGeneratedQuestions_OpenQuestions(t *testing.T) {
+	questions, err := parseGeneratedQuestions(json.RawMessage(`{"questions": [
+		{"id": "q1", "question": "What is photosynthesis?", "expected_answer": "Converting light into chemical energy"}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, questions, 1)
+
+	options, correctIdx := questions[0].answerOptions()
+	assert.Equal(t, []string{"Converting light into chemical energy"}, options)
+	assert.EqualValues(t, 0, correctIdx)
+}
+
+func TestParseGeneratedQuestions_MultipleChoice(t *testing.T) {
+	questions, err := parseGeneratedQuestions(json.RawMessage(`{"questions": [
+		{"id": "q1", "question": "Capital of France?", "options": ["Lyon", "Paris", "Nice"], "correct_idx": 1, "hint": "Eiffel Tower"}
+	]}`))
+	require.NoError(t, err)
+
+	options, correctIdx := questions[0].answerOptions()
+	assert.Equal(t, []string{"Lyon", "Paris", "Nice"}, options)
+	assert.EqualValues(t, 1, correctIdx)
+	assert.Equal(t, "Eiffel Tower", questions[0].Hint)
+}
+
+func TestParseGeneratedQuestions_Rejects(t *testing.T) {
+	for name, payload := range map[string]string{
+		"invalid json":         `{"questions": `,
+		"no questions":         `{"questions": []}`,
+		"empty question":       `{"questions": [{"question": " ", "expected_answer": "x"}]}`,
+		"no answer":            `{"questions": [{"question": "Why?"}]}`,
+		"correct out of range": `{"questions": [{"question": "Why?", "options": ["a", "b"], "correct_idx": 2}]}`,
+		"missing correct":      `{"questions": [{"question": "Why?", "options": ["a", "b"]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseGeneratedQuestions(json.RawMessage(payload))
+			assert.ErrorIs(t, err, ErrArtifactNotIngestible)
+		})
+	}
+}
+
+func TestArtifactGrounding(t *testing.T) {
+	documentID := uuid.New()
+
+	grounding, docID := artifactGrounding(pqtype.NullRawMessage{
+		RawMessage: json.RawMessage(`{"document_id": "` + documentID.String() + `", "grounding": {"groundingChunks": [{"retrievedContext": {"text": "a"}}]}}`),
+		Valid:      true,
+	})
+	assert.JSONEq(t, `{"groundingChunks": [{"retrievedContext": {"text": "a"}}]}`, string(grounding))
+	assert.True(t, docID.Valid)
+	assert.Equal(t, documentID, docID.UUID)
+
+	bare := json.RawMessage(`{"groundingChunks": [{"retrievedContext": {"text": "b"}}]}`)
+	grounding, docID = artifactGrounding(pqtype.NullRawMessage{RawMessage: bare, Valid: true})
+	assert.JSONEq(t, string(bare), string(grounding))
+	assert.False(t, docID.Valid)
+
+	grounding, docID = artifactGrounding(pqtype.NullRawMessage{})
+	assert.Nil(t, grounding)
+	assert.False(t, docID.Valid)
+}
+
+func TestCheckIngestible_Status(t *testing.T) {
+	artifact := store.Artifact{
+		ID:             uuid.New(),
+		GenerationType: store.NullGenerationType{GenerationType: store.GenerationTypeQUESTIONS, Valid: true},
+		OutputJson:     pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"questions": []}`), Valid: true},
+	}
+
+	for status, requireApproved := range map[string]map[bool]bool{
+		"READY":     {false: true, true: false},
+		"APPROVED":  {false: true, true: true},
+		"IN_REVIEW": {false: false, true: false},
+		"REJECTED":  {false: false, true: false},
+	} {
+		artifact.Status = status
+		for approvalRequired, ok := range requireApproved {
+			err := checkIngestible(artifact, approvalRequired)
+			if ok {
+				assert.NoError(t, err, "%s (require approved: %v)", status, approvalRequired)
+			} else {
+				assert.ErrorIs(t, err, ErrArtifactNotIngestible, "%s (require approved: %v)", status, approvalRequired)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package evals
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -54,6 +55,37 @@ type CreateEvalRequest struct {
 	UserID       uuid.UUID        `json:"user_id" validate:"required"`
 }
 
+// EvalItem represents a single question of an evaluation
+type EvalItem struct {
+	ID                uuid.UUID       `json:"id"`
+	EvalID            uuid.UUID       `json:"eval_id"`
+	Prompt            string          `json:"prompt"`
+	Options           []string        `json:"options"`
+	CorrectIdx        int32           `json:"correct_idx"`
+	Hint              *string         `json:"hint,omitempty"`
+	Explanation       *string         `json:"explanation,omitempty"`
+	Metadata          json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	GroundingMetadata json.RawMessage `json:"grounding_metadata,omitempty" swaggertype:"object"`
+	SourceDocumentID  *uuid.UUID      `json:"source_document_id,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// IngestArtifactRequest represents the request to turn a generated artifact into a draft evaluation
+type IngestArtifactRequest struct {
+	Title        *string          `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description  *string          `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Difficulty   *DifficultyLevel `json:"difficulty,omitempty"`
+	Instructions *string          `json:"instructions,omitempty" validate:"omitempty,max=5000"`
+}
+
+// IngestResult is the draft evaluation created from an artifact
+type IngestResult struct {
+	ArtifactID uuid.UUID   `json:"artifact_id"`
+	Eval       *Eval       `json:"eval"`
+	Items      []*EvalItem `json:"items"`
+}
+
 // UpdateEvalRequest represents the request to update an evaluation
 type UpdateEvalRequest struct {
 	Title        *string          `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
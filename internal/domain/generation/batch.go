@@ -0,0 +1,419 @@
+package generation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/infra/progress"
+	"learning-core-api/internal/persistance/store"
+)
+
+// Batch statuses stored in generation_batches.status.
+const (
+	BatchStatusRunning   = "RUNNING"
+	BatchStatusCompleted = "COMPLETED"
+	BatchStatusFailed    = "FAILED"
+)
+
+// Batch item outcomes.
+const (
+	BatchItemSucceeded = "succeeded"
+	BatchItemFailed    = "failed"
+)
+
+const (
+	defaultBatchConcurrency = 4
+	maxBatchConcurrency     = 16
+	maxBatchDocuments       = 500
+	batchProgressTaskType   = "generation_batch"
+)
+
+// BatchGenerateRequest runs the same generation for every document in DocumentIDs.
+// Instructions, Output and Tools are shared; Target.DocumentID is set per document.
+type BatchGenerateRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+
+	DocumentIDs []uuid.UUID `json:"document_ids"`
+
+	Instructions           Instructions `json:"instructions"`
+	Output                 OutputConfig `json:"output"`
+	Tools                  []ToolConfig `json:"tools,omitempty"`
+	ModelConfigID          uuid.UUID    `json:"model_config_id"`
+	FallbackModelConfigIDs []uuid.UUID  `json:"fallback_model_config_ids,omitempty"`
+	ReuseCached            bool         `json:"reuse_cached,omitempty"`
+
+	// Concurrency is the number of workers (default 4, max 16). Per-model
+	// requests_per_minute limits still apply across all workers.
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// BatchItemResult is the outcome of one document in a batch.
+type BatchItemResult struct {
+	DocumentID     uuid.UUID  `json:"document_id"`
+	Status         string     `json:"status"`
+	ArtifactID     *uuid.UUID `json:"artifact_id,omitempty"`
+	ArtifactStatus string     `json:"artifact_status,omitempty"`
+	Cached         bool       `json:"cached,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// Batch is the API view of a generation_batches row.
+// @Description Batch generation job and its per-document results
+type Batch struct {
+	ID          uuid.UUID         `json:"id"`
+	UserID      *uuid.UUID        `json:"user_id,omitempty"`
+	Status      string            `json:"status" example:"RUNNING"`
+	Total       int32             `json:"total"`
+	Succeeded   int32             `json:"succeeded"`
+	Failed      int32             `json:"failed"`
+	Request     json.RawMessage   `json:"request" swaggertype:"object"`
+	Results     []BatchItemResult `json:"results"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// singleGenerator is the part of Service used by batches.
+type singleGenerator interface {
+	Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error)
+}
+
+// BatchService starts batch jobs and runs them in the background. Progress is
+// published through the progress tracker under the batch ID, so clients can
+// follow it on /ws/progress?jobId=<batch id>.
+type BatchService struct {
+	queries   *store.Queries
+	generator singleGenerator
+	tracker   *progress.ProgressTracker
+}
+
+func NewBatchService(db *sql.DB, generator *Service) (*BatchService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	if generator == nil {
+		return nil, fmt.Errorf("generation service is required")
+	}
+	return &BatchService{
+		queries:   store.New(db),
+		generator: generator,
+		tracker:   progress.GetTracker(),
+	}, nil
+}
+
+// Start validates and records a batch, then runs it in the background. The
+// returned batch is in RUNNING state.
+func (s *BatchService) Start(ctx context.Context, req BatchGenerateRequest) (*Batch, error) {
+	documentIDs, err := validateBatchRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	req.DocumentIDs = documentIDs
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+
+	row, err := s.queries.CreateGenerationBatch(ctx, store.CreateGenerationBatchParams{
+		UserID:  uuid.NullUUID{UUID: req.UserID, Valid: req.UserID != uuid.Nil},
+		Request: payload,
+		Total:   int32(len(documentIDs)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create generation batch: %w", err)
+	}
+
+	jobID := row.ID.String()
+	s.tracker.StartJob(jobID, batchProgressTaskType)
+	s.tracker.UpdateProgress(jobID, "started", fmt.Sprintf("Starting generation for %d documents", len(documentIDs)), 0, nil)
+
+	// The batch outlives the HTTP request that started it.
+	go s.run(context.WithoutCancel(ctx), row.ID, req)
+
+	return toBatch(row)
+}
+
+// GetBatch returns a batch by ID.
+func (s *BatchService) GetBatch(ctx context.Context, id uuid.UUID) (*Batch, error) {
+	row, err := s.queries.GetGenerationBatch(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("failed to get generation batch: %w", err)
+	}
+	return toBatch(row)
+}
+
+// ListBatches lists batches, newest first. A non-nil userID restricts the list
+// to batches started by that user.
+func (s *BatchService) ListBatches(ctx context.Context, userID *uuid.UUID, limit, offset int32) ([]Batch, int64, error) {
+	var rows []store.GenerationBatch
+	var total int64
+	var err error
+
+	if userID != nil {
+		rows, err = s.queries.ListGenerationBatchesByUser(ctx, store.ListGenerationBatchesByUserParams{
+			UserID: uuid.NullUUID{UUID: *userID, Valid: true},
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err == nil {
+			total, err = s.queries.CountGenerationBatchesByUser(ctx, uuid.NullUUID{UUID: *userID, Valid: true})
+		}
+	} else {
+		rows, err = s.queries.ListGenerationBatches(ctx, store.ListGenerationBatchesParams{Limit: limit, Offset: offset})
+		if err == nil {
+			total, err = s.queries.CountGenerationBatches(ctx)
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list generation batches: %w", err)
+	}
+
+	batches := make([]Batch, 0, len(rows))
+	for _, row := range rows {
+		batch, err := toBatch(row)
+		if err != nil {
+			return nil, 0, err
+		}
+		batches = append(batches, *batch)
+	}
+	return batches, total, nil
+}
+
+func (s *BatchService) run(ctx context.Context, batchID uuid.UUID, req BatchGenerateRequest) {
+	jobID := batchID.String()
+	total := len(req.DocumentIDs)
+	var succeeded, failed int32
+
+	results := runBatch(ctx, req.DocumentIDs, req.Concurrency, func(ctx context.Context, documentID uuid.UUID) BatchItemResult {
+		return s.generateOne(ctx, req, documentID)
+	}, func(done int, result BatchItemResult) {
+		if result.Status == BatchItemSucceeded {
+			succeeded++
+		} else {
+			failed++
+		}
+		// Save each result as it finishes so an interrupted batch keeps them.
+		resultJSON, err := json.Marshal(result)
+		if err == nil {
+			err = s.queries.AppendGenerationBatchResult(ctx, store.AppendGenerationBatchResultParams{
+				ID:        batchID,
+				Succeeded: succeeded,
+				Failed:    failed,
+				Result:    resultJSON,
+			})
+		}
+		if err != nil {
+			log.Printf("[GENERATION_BATCH] [Batch:%s] failed to save result for document %s: %v", jobID, result.DocumentID, err)
+		}
+
+		message := fmt.Sprintf("Generated %d/%d documents (%d failed)", done, total, failed)
+		if result.Status == BatchItemFailed {
+			s.tracker.UpdateProgressWithError(jobID, "processing", message, result.Error, (done*100)/total)
+			return
+		}
+		s.tracker.UpdateProgress(jobID, "processing", message, (done*100)/total, result)
+	})
+
+	status := BatchStatusCompleted
+	errorMsg := ""
+	if succeeded == 0 {
+		status = BatchStatusFailed
+		errorMsg = "every document in the batch failed"
+	}
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		log.Printf("[GENERATION_BATCH] [Batch:%s] failed to marshal results: %v", jobID, err)
+		resultsJSON = []byte("[]")
+	}
+
+	if _, err := s.queries.CompleteGenerationBatch(ctx, store.CompleteGenerationBatchParams{
+		ID:        batchID,
+		Status:    status,
+		Succeeded: succeeded,
+		Failed:    failed,
+		Results:   resultsJSON,
+		Error:     sql.NullString{String: errorMsg, Valid: errorMsg != ""},
+	}); err != nil {
+		log.Printf("[GENERATION_BATCH] [Batch:%s] failed to save results: %v", jobID, err)
+		s.tracker.FailJob(jobID, fmt.Sprintf("failed to save batch results: %v", err))
+		return
+	}
+
+	summary := map[string]any{"batch_id": batchID, "succeeded": succeeded, "failed": failed, "total": total}
+	if status == BatchStatusFailed {
+		s.tracker.FailJob(jobID, errorMsg)
+		return
+	}
+	s.tracker.UpdateProgress(jobID, "completed", fmt.Sprintf("Generated %d/%d documents (%d failed)", succeeded, total, failed), 100, summary)
+	s.tracker.CompleteJob(jobID)
+}
+
+// interruptedBatchError is recorded on batches that were still running when the
+// process stopped.
+const interruptedBatchError = "batch was interrupted before it finished"
+
+// FailInterruptedBatches marks batches left RUNNING by a previous process as
+// FAILED, keeping the results saved so far. Batches only run in the process
+// that started them, so call it once at startup before any batch starts.
+func (s *BatchService) FailInterruptedBatches(ctx context.Context) (int64, error) {
+	count, err := s.queries.FailRunningGenerationBatches(ctx, interruptedBatchError)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted generation batches: %w", err)
+	}
+	return count, nil
+}
+
+func (s *BatchService) generateOne(ctx context.Context, req BatchGenerateRequest, documentID uuid.UUID) BatchItemResult {
+	result := BatchItemResult{DocumentID: documentID}
+
+	resp, err := s.generator.Generate(ctx, GenerateRequest{
+		UserID:                 req.UserID,
+		Target:                 Target{DocumentID: &documentID},
+		Instructions:           req.Instructions,
+		Output:                 req.Output,
+		Tools:                  req.Tools,
+		ModelConfigID:          req.ModelConfigID,
+		FallbackModelConfigIDs: req.FallbackModelConfigIDs,
+		ReuseCached:            req.ReuseCached,
+	})
+	if err != nil {
+		result.Status = BatchItemFailed
+		result.Error = generationErrorMessage(err)
+		return result
+	}
+
+	artifactID := resp.ArtifactID
+	result.Status = BatchItemSucceeded
+	result.ArtifactID = &artifactID
+	result.ArtifactStatus = resp.Status
+	result.Cached = resp.Cached
+	return result
+}
+
+// runBatch fans documentIDs out to a bounded worker pool. onDone is called
+// serially after each document with the number finished so far; results keep
+// the input order.
+func runBatch(
+	ctx context.Context,
+	documentIDs []uuid.UUID,
+	concurrency int,
+	generate func(ctx context.Context, documentID uuid.UUID) BatchItemResult,
+	onDone func(done int, result BatchItemResult),
+) []BatchItemResult {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > len(documentIDs) {
+		concurrency = len(documentIDs)
+	}
+
+	results := make([]BatchItemResult, len(documentIDs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var result BatchItemResult
+				if err := ctx.Err(); err != nil {
+					result = BatchItemResult{DocumentID: documentIDs[i], Status: BatchItemFailed, Error: err.Error()}
+				} else {
+					result = generate(ctx, documentIDs[i])
+				}
+				results[i] = result
+
+				mu.Lock()
+				done++
+				if onDone != nil {
+					onDone(done, result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range documentIDs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// validateBatchRequest checks the request and returns the de-duplicated document IDs.
+func validateBatchRequest(req BatchGenerateRequest) ([]uuid.UUID, error) {
+	if len(req.DocumentIDs) == 0 {
+		return nil, fmt.Errorf("%w: document_ids is required", ErrInvalidRequest)
+	}
+	if req.Instructions.Inline == "" && req.Instructions.GenerationType == "" {
+		return nil, fmt.Errorf("%w: instructions.generation_type or instructions.inline is required", ErrInvalidRequest)
+	}
+	if req.Concurrency < 0 || req.Concurrency > maxBatchConcurrency {
+		return nil, fmt.Errorf("%w: concurrency must be between 1 and %d", ErrInvalidRequest, maxBatchConcurrency)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.DocumentIDs))
+	documentIDs := make([]uuid.UUID, 0, len(req.DocumentIDs))
+	for _, id := range req.DocumentIDs {
+		if id == uuid.Nil {
+			return nil, fmt.Errorf("%w: document_ids must not contain empty IDs", ErrInvalidRequest)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		documentIDs = append(documentIDs, id)
+	}
+	if len(documentIDs) > maxBatchDocuments {
+		return nil, fmt.Errorf("%w: at most %d documents per batch", ErrInvalidRequest, maxBatchDocuments)
+	}
+	return documentIDs, nil
+}
+
+func toBatch(row store.GenerationBatch) (*Batch, error) {
+	batch := &Batch{
+		ID:        row.ID,
+		Status:    row.Status,
+		Total:     row.Total,
+		Succeeded: row.Succeeded,
+		Failed:    row.Failed,
+		Request:   row.Request,
+		Results:   []BatchItemResult{},
+		Error:     row.Error.String,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.UserID.Valid {
+		userID := row.UserID.UUID
+		batch.UserID = &userID
+	}
+	if row.CompletedAt.Valid {
+		completedAt := row.CompletedAt.Time
+		batch.CompletedAt = &completedAt
+	}
+	if len(row.Results) > 0 {
+		if err := json.Unmarshal(row.Results, &batch.Results); err != nil {
+			return nil, fmt.Errorf("failed to parse batch results: %w", err)
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,176 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+func TestRunBatch_BoundedConcurrencyAndOrder(t *testing.T) {
+	documentIDs := make([]uuid.UUID, 20)
+	for i := range documentIDs {
+		documentIDs[i] = uuid.New()
+	}
+	failing := documentIDs[3]
+
+	var running, peak atomic.Int32
+	var progress []int
+	results := runBatch(context.Background(), documentIDs, 3, func(ctx context.Context, documentID uuid.UUID) BatchItemResult {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+
+		if documentID == failing {
+			return BatchItemResult{DocumentID: documentID, Status: BatchItemFailed, Error: "boom"}
+		}
+		artifactID := uuid.New()
+		return BatchItemResult{DocumentID: documentID, Status: BatchItemSucceeded, ArtifactID: &artifactID}
+	}, func(done int, result BatchItemResult) {
+		progress = append(progress, done)
+	})
+
+	require.Len(t, results, len(documentIDs))
+	for i, result := range results {
+		assert.Equal(t, documentIDs[i], result.DocumentID)
+	}
+	assert.Equal(t, BatchItemFailed, results[3].Status)
+	assert.Equal(t, BatchItemSucceeded, results[4].Status)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Len(t, progress, len(documentIDs))
+	assert.Equal(t, len(documentIDs), progress[len(progress)-1])
+}
+
+func TestRunBatch_CancelledContextFailsRemaining(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := runBatch(ctx, []uuid.UUID{uuid.New(), uuid.New()}, 0, func(ctx context.Context, documentID uuid.UUID) BatchItemResult {
+		t.Fatal("generate must not run after cancellation")
+		return BatchItemResult{}
+	}, nil)
+
+	for _, result := range results {
+		assert.Equal(t, BatchItemFailed, result.Status)
+		assert.Contains(t, result.Error, context.Canceled.Error())
+	}
+}
+
+func TestValidateBatchRequest(t *testing.T) {
+	doc := uuid.New()
+	ids, err := validateBatchRequest(BatchGenerateRequest{
+		DocumentIDs:  []uuid.UUID{doc, doc},
+		Instructions: Instructions{GenerationType: "SECTION_TOPICS"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{doc}, ids)
+
+	for name, req := range map[string]BatchGenerateRequest{
+		"no documents":    {Instructions: Instructions{GenerationType: "SECTION_TOPICS"}},
+		"no instructions": {DocumentIDs: []uuid.UUID{doc}},
+		"nil document":    {DocumentIDs: []uuid.UUID{uuid.Nil}, Instructions: Instructions{Inline: "x"}},
+		"concurrency":     {DocumentIDs: []uuid.UUID{doc}, Instructions: Instructions{Inline: "x"}, Concurrency: maxBatchConcurrency + 1},
+	} {
+		_, err := validateBatchRequest(req)
+		assert.True(t, errors.Is(err, ErrInvalidRequest), name)
+	}
+}
+
+func TestRateLimitedGenerator_SpacesCallsPerModel(t *testing.T) {
+	rpm := int32(600) // one call every 100ms
+	limited := &ModelConfig{Name: "synthetic:1", RequestsPerMinute: &rpm}
+	generator := NewRateLimitedGenerator(NewSyntheticGenerator())
+
+	started := time.Now()
+	for range 3 {
+		_, err := generator.Generate(context.Background(), GeneratorRequest{Prompt: "x", Model: limited})
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(started), 180*time.Millisecond)
+
+	// Models without a limit are not delayed.
+	started = time.Now()
+	for range 3 {
+		_, err := generator.Generate(context.Background(), GeneratorRequest{Prompt: "x", Model: &ModelConfig{Name: "synthetic:2"}})
+		require.NoError(t, err)
+	}
+	assert.Less(t, time.Since(started), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := generator.Generate(ctx, GeneratorRequest{Prompt: "x", Model: limited})
+	assert.Error(t, err)
+}
+
+func TestRunBatch_RateLimitQueueDoesNotTimeOutCalls(t *testing.T) {
+	rpm := int32(600) // one call every 100ms
+	model := &ModelConfig{Name: "synthetic:1", RequestsPerMinute: &rpm}
+	// Every call but the first queues longer than the per-call timeout.
+	generator := NewResilientGenerator(NewRateLimitedGenerator(NewSyntheticGenerator()), RetryPolicy{MaxAttempts: 1, CallTimeout: 50 * time.Millisecond})
+
+	documentIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	results := runBatch(context.Background(), documentIDs, len(documentIDs), func(ctx context.Context, documentID uuid.UUID) BatchItemResult {
+		if _, err := generator.Generate(ctx, GeneratorRequest{Prompt: documentID.String(), Model: model}); err != nil {
+			return BatchItemResult{DocumentID: documentID, Status: BatchItemFailed, Error: err.Error()}
+		}
+		return BatchItemResult{DocumentID: documentID, Status: BatchItemSucceeded}
+	}, nil)
+
+	for _, result := range results {
+		assert.Equal(t, BatchItemSucceeded, result.Status, result.Error)
+	}
+}
+
+func TestBatchService_SavesResultsAndFailsInterruptedBatches(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	service := &BatchService{queries: queries}
+
+	row, err := queries.CreateGenerationBatch(ctx, store.CreateGenerationBatchParams{Request: []byte(`{}`), Total: 2})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM generation_batches WHERE id = $1", row.ID)
+	})
+
+	// One of two documents finished before the process stopped.
+	finished := BatchItemResult{DocumentID: uuid.New(), Status: BatchItemFailed, Error: "model call failed"}
+	result, err := json.Marshal(finished)
+	require.NoError(t, err)
+	require.NoError(t, queries.AppendGenerationBatchResult(ctx, store.AppendGenerationBatchResultParams{ID: row.ID, Failed: 1, Result: result}))
+
+	interrupted, err := service.FailInterruptedBatches(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, interrupted, int64(1))
+
+	batch, err := service.GetBatch(ctx, row.ID)
+	require.NoError(t, err)
+	assert.Equal(t, BatchStatusFailed, batch.Status)
+	assert.Equal(t, interruptedBatchError, batch.Error)
+	assert.Equal(t, int32(1), batch.Failed)
+	assert.NotNil(t, batch.CompletedAt)
+	assert.Equal(t, []BatchItemResult{finished}, batch.Results)
+}
@@ -0,0 +1,214 @@
+package generation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"learning-core-api/internal/domain/document_graph"
+	"learning-core-api/internal/domain/taxonomy"
+)
+
+// Built-in function names.
+const (
+	FunctionLookupTaxonomyNode = "lookup_taxonomy_node"
+	FunctionSearchGraphNodes   = "search_graph_nodes"
+	FunctionGetDocumentPage    = "get_document_page"
+)
+
+const (
+	maxTaxonomyChildren   = 50
+	defaultGraphToolLimit = 8
+	maxGraphToolLimit     = 25
+	maxPageTextLength     = 12000
+)
+
+// registerBuiltinTools adds the functions backed by the taxonomy and the
+// document graph. Graph functions are skipped when no graph repository is set.
+func registerBuiltinTools(registry *ToolRegistry, taxonomyRepo taxonomy.Repository, graphRepo *document_graph.Repository) error {
+	tools := []FunctionTool{{
+		Declaration: FunctionDeclaration{
+			Name:        FunctionLookupTaxonomyNode,
+			Description: "Look up an active taxonomy node by its slash-separated path (e.g. \"mathematics/algebra\") and list its direct children. An empty path lists the top-level nodes.",
+			Parameters:  json.RawMessage(`{"type": "OBJECT", "properties": {"path": {"type": "STRING", "description": "Taxonomy path; empty for the root"}}}`),
+		},
+		Handler: lookupTaxonomyNode(taxonomyRepo),
+	}}
+
+	if graphRepo != nil {
+		tools = append(tools,
+			FunctionTool{
+				Declaration: FunctionDeclaration{
+					Name:        FunctionSearchGraphNodes,
+					Description: "Search the target document's graph for pages and paragraphs containing the query text.",
+					Parameters:  json.RawMessage(`{"type": "OBJECT", "properties": {"query": {"type": "STRING"}, "limit": {"type": "INTEGER", "description": "Maximum nodes to return (default 8, max 25)"}}, "required": ["query"]}`),
+				},
+				Handler: searchGraphNodes(graphRepo),
+			},
+			FunctionTool{
+				Declaration: FunctionDeclaration{
+					Name:        FunctionGetDocumentPage,
+					Description: "Return the full text of a page of the target document.",
+					Parameters:  json.RawMessage(`{"type": "OBJECT", "properties": {"page": {"type": "INTEGER", "description": "1-based page number"}}, "required": ["page"]}`),
+				},
+				Handler: getDocumentPage(graphRepo),
+			},
+		)
+	}
+
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type taxonomyNodeSummary struct {
+	Path        string  `json:"path"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Depth       int32   `json:"depth"`
+	State       string  `json:"state"`
+}
+
+type taxonomyLookupResult struct {
+	Node     *taxonomyNodeSummary  `json:"node,omitempty"`
+	Children []taxonomyNodeSummary `json:"children"`
+}
+
+func lookupTaxonomyNode(repo taxonomy.Repository) ToolHandler {
+	return func(ctx context.Context, _ ToolCallContext, args json.RawMessage) (any, error) {
+		var params struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		path := strings.Trim(strings.TrimSpace(params.Path), "/")
+
+		result := taxonomyLookupResult{Children: []taxonomyNodeSummary{}}
+		prefix := ""
+		childDepth := int32(-1)
+		if path != "" {
+			node, err := repo.GetActiveByPath(ctx, path)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, fmt.Errorf("no active taxonomy node at path %q", path)
+				}
+				return nil, err
+			}
+			summary := summarizeTaxonomyNode(node)
+			result.Node = &summary
+			prefix = path + "/"
+			childDepth = node.Depth + 1
+		}
+
+		nodes, err := repo.ListByPrefix(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if !node.IsActive || strings.Contains(strings.TrimPrefix(node.Path, prefix), "/") {
+				continue
+			}
+			if childDepth >= 0 && node.Depth != childDepth {
+				continue
+			}
+			result.Children = append(result.Children, summarizeTaxonomyNode(node))
+			if len(result.Children) == maxTaxonomyChildren {
+				break
+			}
+		}
+		return result, nil
+	}
+}
+
+func summarizeTaxonomyNode(node *taxonomy.TaxonomyNode) taxonomyNodeSummary {
+	return taxonomyNodeSummary{
+		Path:        node.Path,
+		Name:        node.Name,
+		Description: node.Description,
+		Depth:       node.Depth,
+		State:       node.State,
+	}
+}
+
+type graphNodeSummary struct {
+	ID         string `json:"id"`
+	NodeType   string `json:"node_type"`
+	Text       string `json:"text"`
+	PageNumber *int   `json:"page_number,omitempty"`
+}
+
+func searchGraphNodes(repo *document_graph.Repository) ToolHandler {
+	return func(ctx context.Context, call ToolCallContext, args json.RawMessage) (any, error) {
+		if call.DocumentID == nil {
+			return nil, fmt.Errorf("the generation has no target document")
+		}
+		var params struct {
+			Query string `json:"query"`
+			Limit int    `json:"limit"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		query := strings.TrimSpace(params.Query)
+		if query == "" {
+			return nil, fmt.Errorf("query is required")
+		}
+		limit := params.Limit
+		if limit <= 0 {
+			limit = defaultGraphToolLimit
+		}
+		limit = min(limit, maxGraphToolLimit)
+
+		nodes, err := repo.SearchNodes(ctx, *call.DocumentID, query, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]graphNodeSummary, 0, len(nodes))
+		for _, node := range nodes {
+			results = append(results, graphNodeSummary{
+				ID:         node.ID.String(),
+				NodeType:   node.NodeType,
+				Text:       truncate(node.Text, 1000),
+				PageNumber: node.PageNumber,
+			})
+		}
+		return map[string]any{"nodes": results}, nil
+	}
+}
+
+func getDocumentPage(repo *document_graph.Repository) ToolHandler {
+	return func(ctx context.Context, call ToolCallContext, args json.RawMessage) (any, error) {
+		if call.DocumentID == nil {
+			return nil, fmt.Errorf("the generation has no target document")
+		}
+		var params struct {
+			Page int `json:"page"`
+		}
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+		if params.Page <= 0 {
+			return nil, fmt.Errorf("page must be a positive page number")
+		}
+
+		node, err := repo.GetPageNode(ctx, *call.DocumentID, params.Page)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("page %d not found", params.Page)
+			}
+			return nil, err
+		}
+		return map[string]any{
+			"page": params.Page,
+			"text": truncate(node.Text, maxPageTextLength),
+		}, nil
+	}
+}
@@ -0,0 +1,92 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/artifacts"
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+func TestGenerate_ReuseCachedCopiesArtifactForRequester(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	artifactsService := artifacts.NewService(db)
+	service, err := NewService(db, artifactsService, NewSyntheticGenerator(), nil)
+	require.NoError(t, err)
+
+	createUser := func() uuid.UUID {
+		id := uuid.New()
+		_, err := queries.CreateUser(ctx, store.CreateUserParams{
+			ID:        id,
+			Email:     fmt.Sprintf("cache-test-%s@example.com", id),
+			Password:  "password123",
+			IsTeacher: true,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = queries.DeleteUser(ctx, id)
+		})
+		return id
+	}
+
+	prompt := "Explain photosynthesis " + uuid.NewString()
+	generate := func(userID uuid.UUID, documentID uuid.UUID) *GenerateResponse {
+		resp, err := service.Generate(ctx, GenerateRequest{
+			UserID:       userID,
+			Target:       Target{DocumentID: &documentID},
+			Instructions: Instructions{Inline: prompt},
+			Output:       OutputConfig{Format: "text"},
+			ReuseCached:  true,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, _ = db.ExecContext(ctx, "DELETE FROM artifacts WHERE id = $1", resp.ArtifactID)
+		})
+		return resp
+	}
+	cachedFrom := func(resp *GenerateResponse) (store.Artifact, map[string]any) {
+		artifact, err := artifactsService.GetArtifactByID(ctx, resp.ArtifactID)
+		require.NoError(t, err)
+		var meta map[string]any
+		require.NoError(t, json.Unmarshal(artifact.Meta.RawMessage, &meta))
+		return *artifact, meta
+	}
+
+	first := generate(createUser(), uuid.New())
+	assert.False(t, first.Cached)
+
+	requester, documentID := createUser(), uuid.New()
+	copied := generate(requester, documentID)
+	assert.True(t, copied.Cached)
+	assert.NotEqual(t, first.ArtifactID, copied.ArtifactID)
+	assert.Equal(t, first.OutputText, copied.OutputText)
+
+	artifact, meta := cachedFrom(copied)
+	assert.Equal(t, requester, artifact.UserID.UUID)
+	assert.Equal(t, documentID.String(), meta["document_id"])
+	assert.Equal(t, first.ArtifactID.String(), meta["cached_from"])
+	assert.False(t, artifact.CostUsd.Valid)
+
+	// An approved output is preferred over newer READY ones.
+	_, err = db.ExecContext(ctx, "UPDATE artifacts SET status = 'APPROVED' WHERE id = $1", first.ArtifactID)
+	require.NoError(t, err)
+	_, meta = cachedFrom(generate(requester, documentID))
+	assert.Equal(t, first.ArtifactID.String(), meta["cached_from"])
+}
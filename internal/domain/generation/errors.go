@@ -0,0 +1,12 @@
+package generation
+
+import "errors"
+
+// Domain errors for generation
+var (
+	ErrInvalidRequest       = errors.New("invalid generation request")
+	ErrGeneratorUnavailable = errors.New("generator is not configured")
+	ErrGenerationFailed     = errors.New("generation failed")
+	ErrGenerationNotFound   = errors.New("generation not found")
+	ErrBatchNotFound        = errors.New("generation batch not found")
+)
@@ -0,0 +1,108 @@
+package generation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/prompt_templates"
+)
+
+type fakePromptTemplates struct {
+	prompt_templates.Repository
+	active     *prompt_templates.PromptTemplate
+	byID       map[uuid.UUID]*prompt_templates.PromptTemplate
+	experiment *prompt_templates.PromptExperiment
+}
+
+func (f *fakePromptTemplates) GetActiveByGenerationType(ctx context.Context, generationType string) (*prompt_templates.PromptTemplate, error) {
+	return f.active, nil
+}
+
+func (f *fakePromptTemplates) GetByID(ctx context.Context, id uuid.UUID) (*prompt_templates.PromptTemplate, error) {
+	tmpl, ok := f.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get prompt template: %w", sql.ErrNoRows)
+	}
+	return tmpl, nil
+}
+
+func (f *fakePromptTemplates) GetRunningExperiment(ctx context.Context, generationType string) (*prompt_templates.PromptExperiment, error) {
+	if f.experiment == nil || f.experiment.GenerationType != generationType {
+		return nil, fmt.Errorf("failed to get running prompt experiment: %w", sql.ErrNoRows)
+	}
+	return f.experiment, nil
+}
+
+func TestResolveInstructions_PicksExperimentVariant(t *testing.T) {
+	active := &prompt_templates.PromptTemplate{ID: uuid.New(), GenerationType: "SUMMARY", Version: 1, Template: "Summarise (v1)"}
+	candidate := &prompt_templates.PromptTemplate{ID: uuid.New(), GenerationType: "SUMMARY", Version: 2, Template: "Summarise (v2)"}
+	repo := &fakePromptTemplates{
+		active: active,
+		byID:   map[uuid.UUID]*prompt_templates.PromptTemplate{active.ID: active, candidate.ID: candidate},
+		experiment: &prompt_templates.PromptExperiment{
+			ID:             uuid.New(),
+			Name:           "v2 only",
+			GenerationType: "SUMMARY",
+			Status:         prompt_templates.ExperimentStatusRunning,
+			Variants:       []prompt_templates.ExperimentVariant{{PromptTemplateID: candidate.ID, PromptVersion: 2, Weight: 1}},
+		},
+	}
+	service := &Service{promptTemplates: repo}
+
+	resolved, err := service.resolveInstructions(context.Background(), Instructions{GenerationType: "SUMMARY"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Summarise (v2)", resolved.Prompt)
+	assert.Equal(t, candidate.ID, resolved.PromptTemplateID)
+	require.NotNil(t, resolved.Experiment)
+	assert.Equal(t, repo.experiment.ID, resolved.Experiment.ID)
+	assert.EqualValues(t, 2, resolved.Experiment.PromptVersion)
+
+	meta := mergeMeta(nil, map[string]any{"experiment": resolved.Experiment})
+	assert.JSONEq(t, fmt.Sprintf(`{"experiment": {"id": %q, "name": "v2 only", "prompt_template_id": %q, "prompt_version": 2}}`,
+		repo.experiment.ID, candidate.ID), string(meta))
+}
+
+func TestResolveInstructions_PreviewReportsExperiment(t *testing.T) {
+	active := &prompt_templates.PromptTemplate{ID: uuid.New(), GenerationType: "SUMMARY", Version: 1, Template: "Summarise (v1)"}
+	candidate := &prompt_templates.PromptTemplate{ID: uuid.New(), GenerationType: "SUMMARY", Version: 2, Template: "Summarise (v2)"}
+	repo := &fakePromptTemplates{
+		active: active,
+		byID:   map[uuid.UUID]*prompt_templates.PromptTemplate{active.ID: active, candidate.ID: candidate},
+		experiment: &prompt_templates.PromptExperiment{
+			ID:             uuid.New(),
+			Name:           "v1 vs v2",
+			GenerationType: "SUMMARY",
+			Status:         prompt_templates.ExperimentStatusRunning,
+			Variants: []prompt_templates.ExperimentVariant{
+				{PromptTemplateID: active.ID, PromptVersion: 1, Weight: 1},
+				{PromptTemplateID: candidate.ID, PromptVersion: 2, Weight: 1},
+			},
+		},
+	}
+	service := &Service{promptTemplates: repo}
+
+	for i := 0; i < 10; i++ {
+		resolved, err := service.resolveInstructions(context.Background(), Instructions{GenerationType: "SUMMARY"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, "Summarise (v1)", resolved.Prompt)
+		assert.Nil(t, resolved.Experiment)
+		require.NotNil(t, resolved.RunningExperiment)
+		assert.Len(t, resolved.RunningExperiment.Variants, 2)
+	}
+}
+
+func TestResolveInstructions_ActiveWithoutExperiment(t *testing.T) {
+	active := &prompt_templates.PromptTemplate{ID: uuid.New(), GenerationType: "SUMMARY", Version: 1, Template: "Summarise (v1)"}
+	service := &Service{promptTemplates: &fakePromptTemplates{active: active}}
+
+	resolved, err := service.resolveInstructions(context.Background(), Instructions{GenerationType: "SUMMARY"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Summarise (v1)", resolved.Prompt)
+	assert.Nil(t, resolved.Experiment)
+}
@@ -8,14 +8,28 @@ import (
 // Generator defines the boundary for provider-specific generation calls.
 type Generator interface {
 	Generate(ctx context.Context, req GeneratorRequest) (*GeneratorResponse, error)
+	// GenerateStream calls onChunk with each partial text chunk as it arrives and
+	// returns the aggregated response once the stream completes.
+	GenerateStream(ctx context.Context, req GeneratorRequest, onChunk ChunkHandler) (*GeneratorResponse, error)
 }
 
+// ChunkHandler receives partial output text. Returning an error aborts the stream.
+type ChunkHandler func(chunk string) error
+
 type GeneratorRequest struct {
-	Prompt            string
-	SystemInstruction string
-	OutputSchema      json.RawMessage
-	Tools             []ToolConfig
-	Model             *ModelConfig
+	Prompt            string          `json:"prompt"`
+	SystemInstruction string          `json:"system_instruction,omitempty"`
+	OutputSchema      json.RawMessage `json:"output_schema,omitempty"`
+	Tools             []ToolConfig    `json:"tools,omitempty"`
+	Model             *ModelConfig    `json:"model,omitempty"`
+	// Fallbacks are tried in order when Model keeps failing (see ResilientGenerator).
+	Fallbacks []*ModelConfig `json:"fallbacks,omitempty"`
+	// Functions the model may call; calls are returned in GeneratorResponse.FunctionCalls.
+	Functions []FunctionDeclaration `json:"functions,omitempty"`
+	// History holds earlier conversation messages, oldest first, sent before Prompt.
+	History []HistoryMessage `json:"history,omitempty"`
+	// Turns are earlier function-calling exchanges replayed after Prompt.
+	Turns []ConversationTurn `json:"-"`
 }
 
 type GeneratorResponse struct {
@@ -23,4 +37,56 @@ type GeneratorResponse struct {
 	FinishReason      string
 	ModelUsed         string
 	GroundingMetadata json.RawMessage
+	Attempts          []GeneratorAttempt
+	Usage             *TokenUsage // nil when the provider did not report usage
+	FunctionCalls     []FunctionCall
+	// Candidates holds every candidate output, OutputText first, when the model
+	// config requests more than one. Streamed responses only carry OutputText.
+	Candidates []string
+	// Model is the config that produced the output when it may differ from
+	// GeneratorRequest.Model, such as a fallback. Set by ResilientGenerator.
+	Model *ModelConfig
+}
+
+// FunctionDeclaration describes a function the model may call. Parameters use
+// the same schema dialect as GeneratorRequest.OutputSchema.
+type FunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// FunctionCall is a function invocation requested by the model.
+type FunctionCall struct {
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name"`
+	Args      json.RawMessage `json:"args,omitempty"`
+	Signature []byte          `json:"-"` // opaque provider data that must be sent back with the call
+}
+
+// FunctionResult is the output of a FunctionCall sent back to the model.
+type FunctionResult struct {
+	ID       string
+	Name     string
+	Response json.RawMessage
+}
+
+// Roles of HistoryMessage.
+const (
+	HistoryRoleUser  = "user"
+	HistoryRoleModel = "model"
+)
+
+// HistoryMessage is a plain-text message from an earlier conversation turn.
+type HistoryMessage struct {
+	Role string `json:"role"` // HistoryRoleUser or HistoryRoleModel
+	Text string `json:"text"`
+}
+
+// ConversationTurn is either a model turn (Text and Calls) or the results of
+// those calls.
+type ConversationTurn struct {
+	Text    string
+	Calls   []FunctionCall
+	Results []FunctionResult
 }
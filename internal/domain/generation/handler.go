@@ -0,0 +1,575 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/domain/artifacts"
+	httpPkg "learning-core-api/internal/http"
+	"learning-core-api/internal/http/authz"
+	"learning-core-api/internal/http/render"
+	"learning-core-api/internal/persistance/store"
+)
+
+type Handler struct {
+	service *Service
+	batches *BatchService
+}
+
+func NewHandler(service *Service, batches *BatchService) *Handler {
+	return &Handler{service: service, batches: batches}
+}
+
+func (h *Handler) RegisterPublicRoutes(r chi.Router) {
+	// No public routes for generations
+}
+
+func (h *Handler) RegisterAdminRoutes(r chi.Router) {
+	// Admins use the staff routes
+}
+
+func (h *Handler) RegisterTeacherRoutes(r chi.Router) {
+	// Teachers use the staff routes
+}
+
+// RegisterStaffRoutes registers the routes shared by teachers and admins.
+// Teachers can run generations, see the ones they requested and regenerate or
+// refine their own artifacts.
+func (h *Handler) RegisterStaffRoutes(r chi.Router) {
+	r.With(authz.RequireScope("write")).Post("/generations", h.Generate)
+	r.With(authz.RequireScope("write")).Post("/generations/stream", h.StreamGeneration)
+	r.With(authz.RequireScope("read")).Post("/generations/preview", h.PreviewGeneration)
+	r.With(authz.RequireScope("write")).Post("/generations/batches", h.StartBatch)
+	r.With(authz.RequireScope("read")).Get("/generations/batches", h.ListBatches)
+	r.With(authz.RequireScope("read")).Get("/generations/batches/{batch_id}", h.GetBatch)
+	r.With(authz.RequireScope("read")).Get("/generations", h.ListGenerations)
+	r.With(authz.RequireScope("read")).Get("/generations/{artifact_id}", h.GetGeneration)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/regenerate", h.RegenerateArtifact)
+	r.With(authz.RequireScope("write")).Post("/artifacts/{id}/refine", h.RefineArtifact)
+}
+
+func (h *Handler) RegisterLearnerRoutes(r chi.Router) {
+	// Learners cannot run generations directly
+}
+
+// Generate runs a generation and returns its result.
+// @Summary Run a generation
+// @Description Resolve templates, call the configured model and persist the result as an artifact. The requesting user is taken from the JWT. The saved artifact is available at GET /generations/{artifact_id}.
+// @Tags Generations
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param request body GenerateRequest true "Generation request"
+// @Success 201 {object} GenerateResponse "Generation result"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 502 {object} map[string]string "Model call failed"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /generations [post]
+func (h *Handler) Generate(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req GenerateRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	req.UserID = userID
+
+	resp, err := h.service.Generate(r.Context(), req)
+	if err != nil {
+		render.Error(w, generationErrorStatus(err), generationErrorMessage(err))
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, resp)
+}
+
+// PreviewGeneration resolves a generation request without calling the model.
+// @Summary Preview a generation
+// @Description Dry run: resolves the prompt template (with partials and variables), system instruction, schema, model config and graph_rag context exactly as POST /generations would and returns the assembled model request with the template IDs and versions used. The model is not called and no artifact is saved. When a prompt experiment is running for the generation type, the preview renders the active prompt template and returns the experiment with all its variants instead of picking one; set instructions.prompt_version to preview a variant.
+// @Tags Generations
+// @Security OAuth2[read]
+// @Accept json
+// @Produce json
+// @Param request body GenerateRequest true "Generation request"
+// @Success 200 {object} PreviewResponse "Assembled model request"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /generations/preview [post]
+func (h *Handler) PreviewGeneration(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req GenerateRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	req.UserID = userID
+
+	preview, err := h.service.Preview(r.Context(), req)
+	if err != nil {
+		render.Error(w, generationErrorStatus(err), generationErrorMessage(err))
+		return
+	}
+
+	render.JSON(w, http.StatusOK, preview)
+}
+
+// StreamGeneration runs a generation and streams the output as Server-Sent Events.
+// @Summary Stream a generation
+// @Description Validates the request before the stream opens, so invalid requests get a regular error response. The output is then streamed as "chunk" events, followed by "finish", "grounding" (when available) and "artifact" once the artifact is saved. Failures after the stream has opened are sent as an "error" event. Disconnecting cancels the generation and nothing is saved.
+// @Tags Generations
+// @Security OAuth2[write]
+// @Accept json
+// @Produce text/event-stream
+// @Param request body GenerateRequest true "Generation request"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /generations/stream [post]
+func (h *Handler) StreamGeneration(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req GenerateRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	req.UserID = userID
+
+	ctx := r.Context()
+	stream, err := h.service.PrepareStream(ctx, req)
+	if err != nil {
+		render.Error(w, generationErrorStatus(err), generationErrorMessage(err))
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, payload any) error {
+		if err := writeSSE(w, event, payload); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	resp, err := stream.Run(ctx, func(chunk string) error {
+		return send("chunk", map[string]string{"text": chunk})
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			send("error", map[string]string{"error": generationErrorMessage(err)})
+		}
+		return
+	}
+
+	send("finish", map[string]string{"finish_reason": resp.FinishReason, "model_used": resp.ModelUsed})
+	if len(resp.GroundingMetadata) > 0 {
+		send("grounding", resp.GroundingMetadata)
+	}
+
+	artifact, err := h.service.GetGeneration(ctx, resp.ArtifactID)
+	if err != nil {
+		send("error", map[string]string{"error": generationErrorMessage(err)})
+		return
+	}
+	send("artifact", artifacts.ConvertFromStore(*artifact))
+}
+
+// ListGenerations lists generation artifacts with pagination.
+// @Summary List generations
+// @Description Admins see every generation; teachers only see the generations they requested
+// @Tags Generations
+// @Security OAuth2[read]
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
+// @Success 200 {object} artifacts.ArtifactListResponse "Paginated list of generation artifacts"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /generations [get]
+func (h *Handler) ListGenerations(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	ctx := r.Context()
+	var userFilter *uuid.UUID
+	if !isAdmin(r) {
+		userID, err := uuid.Parse(authz.UserIDFromContext(ctx))
+		if err != nil {
+			render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+			return
+		}
+		userFilter = &userID
+	}
+
+	pagination := httpPkg.GetPaginationParams(r)
+	storeArtifacts, total, err := h.service.ListGenerations(ctx, userFilter, int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+
+	domainArtifacts := make([]artifacts.Artifact, 0, len(storeArtifacts))
+	for _, storeArtifact := range storeArtifacts {
+		domainArtifacts = append(domainArtifacts, artifacts.ConvertFromStore(storeArtifact))
+	}
+
+	render.JSON(w, http.StatusOK, httpPkg.NewPaginatedResponse(domainArtifacts, pagination, total))
+}
+
+// GetGeneration retrieves the artifact saved for a generation.
+// @Summary Get generation by artifact ID
+// @Description Retrieve a generation artifact. Teachers can only read generations they requested.
+// @Tags Generations
+// @Security OAuth2[read]
+// @Param artifact_id path string true "Artifact ID (UUID)"
+// @Success 200 {object} artifacts.Artifact "Generation artifact"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Generation not found"
+// @Router /generations/{artifact_id} [get]
+func (h *Handler) GetGeneration(w http.ResponseWriter, r *http.Request) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	artifactID, err := uuid.Parse(chi.URLParam(r, "artifact_id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	ctx := r.Context()
+	artifact, err := h.service.GetGeneration(ctx, artifactID)
+	if err != nil {
+		if errors.Is(err, ErrGenerationNotFound) {
+			render.Error(w, http.StatusNotFound, "Generation not found")
+			return
+		}
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+
+	if !isAdmin(r) && (!artifact.UserID.Valid || artifact.UserID.UUID.String() != authz.UserIDFromContext(ctx)) {
+		render.Error(w, http.StatusNotFound, "Generation not found")
+		return
+	}
+
+	render.JSON(w, http.StatusOK, artifacts.ConvertFromStore(*artifact))
+}
+
+// RegenerateArtifact runs a generation artifact's stored inputs again.
+// @Summary Regenerate an artifact
+// @Description Re-runs the stored prompt_render, model_params and schema template of a generation artifact and saves the result as a new artifact whose parent is the original (derivation REGENERATE). Templates are not resolved again. Overrides can replace the prompt, switch model config or change temperature, max_tokens and seed. The request body is optional. Teachers can only regenerate generations they requested.
+// @Tags Generations
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body RegenerateRequest false "Overrides"
+// @Success 201 {object} artifacts.Artifact "New artifact"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload or artifact cannot be regenerated"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Generation not found"
+// @Failure 502 {object} map[string]string "Model call failed"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /artifacts/{id}/regenerate [post]
+func (h *Handler) RegenerateArtifact(w http.ResponseWriter, r *http.Request) {
+	var req RegenerateRequest
+	if err := render.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	h.deriveArtifact(w, r, func(ctx context.Context, parent *store.Artifact, userID uuid.UUID) (*GenerateResponse, error) {
+		return h.service.Regenerate(ctx, parent, userID, req)
+	})
+}
+
+// RefineArtifact revises a generation artifact's output with teacher feedback.
+// @Summary Refine an artifact with feedback
+// @Description Sends the stored prompt_render, the artifact's output and the feedback back to the model with the stored model_params and schema template, and saves the revision as a new artifact whose parent is the original (derivation REFINE). The same overrides as regenerate apply. Teachers can only refine generations they requested.
+// @Tags Generations
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Artifact ID (UUID)"
+// @Param request body RefineRequest true "Feedback and overrides"
+// @Success 201 {object} artifacts.Artifact "Refined artifact"
+// @Failure 400 {object} map[string]string "Bad request - missing feedback or artifact has no output"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Generation not found"
+// @Failure 502 {object} map[string]string "Model call failed"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /artifacts/{id}/refine [post]
+func (h *Handler) RefineArtifact(w http.ResponseWriter, r *http.Request) {
+	var req RefineRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	h.deriveArtifact(w, r, func(ctx context.Context, parent *store.Artifact, userID uuid.UUID) (*GenerateResponse, error) {
+		return h.service.Refine(ctx, parent, userID, req)
+	})
+}
+
+// deriveArtifact loads the parent generation the caller may access, runs derive
+// on it and renders the saved child artifact.
+func (h *Handler) deriveArtifact(w http.ResponseWriter, r *http.Request, derive func(ctx context.Context, parent *store.Artifact, userID uuid.UUID) (*GenerateResponse, error)) {
+	if h.service == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	artifactID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid artifact ID")
+		return
+	}
+
+	ctx := r.Context()
+	userID, err := uuid.Parse(authz.UserIDFromContext(ctx))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	parent, err := h.service.GetGeneration(ctx, artifactID)
+	if err != nil {
+		if errors.Is(err, ErrGenerationNotFound) {
+			render.Error(w, http.StatusNotFound, "Generation not found")
+			return
+		}
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+	if !isAdmin(r) && (!parent.UserID.Valid || parent.UserID.UUID != userID) {
+		render.Error(w, http.StatusNotFound, "Generation not found")
+		return
+	}
+
+	resp, err := derive(ctx, parent, userID)
+	if err != nil {
+		render.Error(w, generationErrorStatus(err), generationErrorMessage(err))
+		return
+	}
+
+	artifact, err := h.service.GetGeneration(ctx, resp.ArtifactID)
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, artifacts.ConvertFromStore(*artifact))
+}
+
+// StartBatch starts a batch generation over many documents.
+// @Summary Start a batch generation
+// @Description Runs the same instructions, output and tools for every document in document_ids using a bounded worker pool. Returns immediately; follow progress on /ws/progress?jobId=<batch id> and fetch the summary from GET /generations/batches/{batch_id}.
+// @Tags Generations
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param request body BatchGenerateRequest true "Batch generation request"
+// @Success 202 {object} Batch "Started batch"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /generations/batches [post]
+func (h *Handler) StartBatch(w http.ResponseWriter, r *http.Request) {
+	if h.batches == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req BatchGenerateRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	req.UserID = userID
+
+	batch, err := h.batches.Start(r.Context(), req)
+	if err != nil {
+		render.Error(w, generationErrorStatus(err), generationErrorMessage(err))
+		return
+	}
+
+	render.JSON(w, http.StatusAccepted, batch)
+}
+
+// ListBatches lists batch generations with pagination.
+// @Summary List batch generations
+// @Description Admins see every batch; teachers only see the batches they started
+// @Tags Generations
+// @Security OAuth2[read]
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
+// @Success 200 {object} map[string]interface{} "Paginated list of batches"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /generations/batches [get]
+func (h *Handler) ListBatches(w http.ResponseWriter, r *http.Request) {
+	if h.batches == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	ctx := r.Context()
+	var userFilter *uuid.UUID
+	if !isAdmin(r) {
+		userID, err := uuid.Parse(authz.UserIDFromContext(ctx))
+		if err != nil {
+			render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+			return
+		}
+		userFilter = &userID
+	}
+
+	pagination := httpPkg.GetPaginationParams(r)
+	batches, total, err := h.batches.ListBatches(ctx, userFilter, int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+
+	render.JSON(w, http.StatusOK, httpPkg.NewPaginatedResponse(batches, pagination, total))
+}
+
+// GetBatch retrieves a batch generation and its per-document results.
+// @Summary Get batch generation
+// @Description Retrieve a batch with success/failure counts and the artifact ID or error of each document that has finished. Batches interrupted by a server restart are marked FAILED with the results saved so far. Teachers can only read batches they started.
+// @Tags Generations
+// @Security OAuth2[read]
+// @Param batch_id path string true "Batch ID (UUID)"
+// @Success 200 {object} Batch "Batch generation"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Batch not found"
+// @Router /generations/batches/{batch_id} [get]
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	if h.batches == nil {
+		render.Error(w, http.StatusServiceUnavailable, "Generation service unavailable")
+		return
+	}
+
+	batchID, err := uuid.Parse(chi.URLParam(r, "batch_id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid batch ID")
+		return
+	}
+
+	ctx := r.Context()
+	batch, err := h.batches.GetBatch(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, ErrBatchNotFound) {
+			render.Error(w, http.StatusNotFound, "Batch not found")
+			return
+		}
+		render.Error(w, http.StatusInternalServerError, generationErrorMessage(err))
+		return
+	}
+
+	if !isAdmin(r) && (batch.UserID == nil || batch.UserID.String() != authz.UserIDFromContext(ctx)) {
+		render.Error(w, http.StatusNotFound, "Batch not found")
+		return
+	}
+
+	render.JSON(w, http.StatusOK, batch)
+}
+
+func writeSSE(w http.ResponseWriter, event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+func generationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrGeneratorUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrGenerationFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// generationErrorMessage returns the client-facing message for err. Internal
+// and provider errors are logged and replaced so database and provider details
+// do not reach the client.
+func generationErrorMessage(err error) string {
+	switch generationErrorStatus(err) {
+	case http.StatusInternalServerError:
+		log.Printf("ERROR: generation failed: %v", err)
+		return "Internal server error"
+	case http.StatusBadGateway:
+		log.Printf("ERROR: generation failed: %v", err)
+		return "Model provider request failed"
+	default:
+		return err.Error()
+	}
+}
+
+func isAdmin(r *http.Request) bool {
+	for _, role := range authz.RolesFromContext(r.Context()) {
+		if role == authz.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package generation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/artifacts"
+	"learning-core-api/internal/http/authz"
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+// groundedGenerator adds grounding metadata to synthetic output.
+type groundedGenerator struct {
+	*SyntheticGenerator
+}
+
+func (g groundedGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, onChunk ChunkHandler) (*GeneratorResponse, error) {
+	resp, err := g.SyntheticGenerator.GenerateStream(ctx, req, onChunk)
+	if err != nil {
+		return nil, err
+	}
+	resp.GroundingMetadata = json.RawMessage(`{"groundingChunks": [{"retrievedContext": {"title": "Photosynthesis"}}]}`)
+	return resp, nil
+}
+
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+func readSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.Name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.Data = strings.TrimPrefix(line, "data: ")
+		case line == "" && current.Name != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func streamRequest(ctx context.Context, userID uuid.UUID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/generations/stream", strings.NewReader(body))
+	return req.WithContext(authz.WithAuth(ctx, userID.String(), []string{authz.RoleTeacher}, []string{"read", "write"}))
+}
+
+func TestStreamGeneration_RejectsInvalidRequestBeforeStreaming(t *testing.T) {
+	handler := NewHandler(&Service{generator: NewSyntheticGenerator()}, nil)
+
+	w := httptest.NewRecorder()
+	handler.StreamGeneration(w, streamRequest(context.Background(), uuid.New(), `{
+		"instructions": {"generation_type": "QUESTIONS"},
+		"output": {"generation_type": "SUMMARY"}
+	}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotEqual(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "generation_type mismatch")
+}
+
+func TestGenerationErrorMessage_HidesInternalAndProviderErrors(t *testing.T) {
+	invalid := fmt.Errorf("%w: model config %q not found", ErrInvalidRequest, uuid.Nil)
+	assert.Equal(t, invalid.Error(), generationErrorMessage(invalid))
+
+	internal := fmt.Errorf("failed to fetch active model config: %w", errors.New(`pq: relation "model_configs" does not exist`))
+	assert.Equal(t, "Internal server error", generationErrorMessage(internal))
+
+	provider := fmt.Errorf("%w: genai call failed: %w", ErrGenerationFailed, errors.New("401 API key sk-test is invalid"))
+	assert.Equal(t, "Model provider request failed", generationErrorMessage(provider))
+}
+
+// cancelOnChunk cancels the request once the first chunk has been written, as a
+// client disconnecting mid-stream would.
+type cancelOnChunk struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (w *cancelOnChunk) Write(p []byte) (int, error) {
+	n, err := w.ResponseRecorder.Write(p)
+	if strings.HasPrefix(string(p), "event: chunk") {
+		w.cancel()
+	}
+	return n, err
+}
+
+func TestStreamGeneration_StreamsChunksAndSavesArtifact(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	service, err := NewService(db, artifacts.NewService(db), groundedGenerator{NewSyntheticGenerator()}, nil)
+	require.NoError(t, err)
+	handler := NewHandler(service, nil)
+
+	userID := uuid.New()
+	_, err = queries.CreateUser(ctx, store.CreateUserParams{
+		ID:        userID,
+		Email:     fmt.Sprintf("stream-test-%s@example.com", userID),
+		Password:  "password123",
+		IsTeacher: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM artifacts WHERE user_id = $1", userID)
+		_ = queries.DeleteUser(ctx, userID)
+	})
+	body := `{"instructions": {"inline": "Explain photosynthesis"}, "output": {"format": "text"}}`
+
+	// A disconnect after the first chunk stops the stream without saving.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	disconnected := &cancelOnChunk{ResponseRecorder: httptest.NewRecorder(), cancel: cancel}
+	handler.StreamGeneration(disconnected, streamRequest(cancelCtx, userID, body))
+
+	events := readSSE(t, disconnected.Body.String())
+	require.Len(t, events, 1)
+	assert.Equal(t, "chunk", events[0].Name)
+
+	var saved int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM artifacts WHERE user_id = $1", userID).Scan(&saved))
+	assert.Zero(t, saved)
+
+	w := httptest.NewRecorder()
+	handler.StreamGeneration(w, streamRequest(ctx, userID, body))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	events = readSSE(t, w.Body.String())
+	var names []string
+	var streamed strings.Builder
+	for _, event := range events {
+		names = append(names, event.Name)
+		if event.Name == "chunk" {
+			var chunk map[string]string
+			require.NoError(t, json.Unmarshal([]byte(event.Data), &chunk))
+			streamed.WriteString(chunk["text"])
+		}
+	}
+	require.GreaterOrEqual(t, len(names), 4)
+	assert.Equal(t, []string{"finish", "grounding", "artifact"}, names[len(names)-3:])
+	for _, name := range names[:len(names)-3] {
+		assert.Equal(t, "chunk", name)
+	}
+
+	var finish map[string]string
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-3].Data), &finish))
+	assert.Equal(t, "STOP", finish["finish_reason"])
+	assert.Contains(t, events[len(events)-2].Data, "Photosynthesis")
+
+	var artifact artifacts.Artifact
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1].Data), &artifact))
+	require.NotNil(t, artifact.Text)
+	assert.Equal(t, streamed.String(), *artifact.Text)
+}
+
+func TestGenerate_InlineRequestIsAGeneration(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	service, err := NewService(db, artifacts.NewService(db), NewSyntheticGenerator(), nil)
+	require.NoError(t, err)
+	handler := NewHandler(service, nil)
+
+	userID := uuid.New()
+	_, err = queries.CreateUser(ctx, store.CreateUserParams{
+		ID:        userID,
+		Email:     fmt.Sprintf("inline-test-%s@example.com", userID),
+		Password:  "password123",
+		IsTeacher: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM artifacts WHERE user_id = $1", userID)
+		_ = queries.DeleteUser(ctx, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/generations", strings.NewReader(`{"instructions": {"inline": "Explain osmosis"}, "output": {"format": "text"}}`))
+	req = req.WithContext(authz.WithAuth(ctx, userID.String(), []string{authz.RoleTeacher}, []string{"read", "write"}))
+	w := httptest.NewRecorder()
+	handler.Generate(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp GenerateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.OutputText)
+
+	artifact, err := service.GetGeneration(ctx, resp.ArtifactID)
+	require.NoError(t, err)
+	assert.False(t, artifact.GenerationType.Valid)
+
+	listed, total, err := service.ListGenerations(ctx, &userID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, listed, 1)
+	assert.Equal(t, resp.ArtifactID, listed[0].ID)
+}
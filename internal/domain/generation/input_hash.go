@@ -0,0 +1,67 @@
+package generation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// inputHashPayload lists everything that influences model output. Changing any
+// field produces a different input_hash.
+type inputHashPayload struct {
+	Prompt            string           `json:"prompt"`
+	SystemInstruction string           `json:"system_instruction,omitempty"`
+	OutputSchema      json.RawMessage  `json:"output_schema,omitempty"`
+	Model             *ModelConfig     `json:"model,omitempty"`
+	Tools             []ToolConfig     `json:"tools,omitempty"`
+	History           []HistoryMessage `json:"history,omitempty"`
+}
+
+// computeInputHash returns a stable SHA-256 hex digest of the generator inputs.
+// Schema and tool configs are compacted so formatting differences do not matter.
+func computeInputHash(req GeneratorRequest, tools []ToolConfig) (string, error) {
+	payload := inputHashPayload{
+		Prompt:            req.Prompt,
+		SystemInstruction: req.SystemInstruction,
+		Model:             req.Model,
+		History:           req.History,
+	}
+
+	if len(req.OutputSchema) > 0 {
+		compacted, err := compactJSON(req.OutputSchema)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalise output schema: %w", err)
+		}
+		payload.OutputSchema = compacted
+	}
+
+	for _, tool := range tools {
+		normalised := ToolConfig{Type: tool.Type}
+		if len(tool.Config) > 0 {
+			compacted, err := compactJSON(tool.Config)
+			if err != nil {
+				return "", fmt.Errorf("failed to normalise %s tool config: %w", tool.Type, err)
+			}
+			normalised.Config = compacted
+		}
+		payload.Tools = append(payload.Tools, normalised)
+	}
+
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal hash input: %w", err)
+	}
+
+	sum := sha256.Sum256(serialized)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func compactJSON(raw json.RawMessage) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+package generation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeInputHash(t *testing.T) {
+	temperature := float32(0.2)
+	base := GeneratorRequest{
+		Prompt:            "Generate questions",
+		SystemInstruction: "Be precise",
+		OutputSchema:      json.RawMessage(`{"type": "OBJECT", "properties": {"a": {"type": "STRING"}}}`),
+		Model:             &ModelConfig{Name: "gemini-3-flash-preview", Temperature: &temperature},
+	}
+	tools := []ToolConfig{{Type: "file_search", Config: json.RawMessage(`{"store_names": ["s1"]}`)}}
+
+	hash, err := computeInputHash(base, tools)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	reformatted := base
+	reformatted.OutputSchema = json.RawMessage("{\n  \"type\":\"OBJECT\",\n  \"properties\":{\"a\":{\"type\":\"STRING\"}}\n}")
+	same, err := computeInputHash(reformatted, []ToolConfig{{Type: "file_search", Config: json.RawMessage(`{ "store_names" : [ "s1" ] }`)}})
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	otherTemperature := float32(0.9)
+	changed := base
+	changed.Model = &ModelConfig{Name: "gemini-3-flash-preview", Temperature: &otherTemperature}
+	different, err := computeInputHash(changed, tools)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, different)
+
+	withoutTools, err := computeInputHash(base, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, withoutTools)
+}
@@ -0,0 +1,234 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/domain/artifacts"
+	"learning-core-api/internal/persistance/store"
+)
+
+const maxRefineFeedbackLength = 4000
+
+// ArtifactOverrides replace parts of the stored inputs when an artifact is
+// regenerated or refined. Unset fields keep the stored value.
+type ArtifactOverrides struct {
+	// ModelConfigID replaces the stored model_params with another model config
+	ModelConfigID  *uuid.UUID `json:"model_config_id,omitempty"`
+	Temperature    *float32   `json:"temperature,omitempty"`
+	MaxTokens      *int32     `json:"max_tokens,omitempty"`
+	Seed           *int32     `json:"seed,omitempty"`
+	RepairAttempts int        `json:"repair_attempts,omitempty"`
+}
+
+// RegenerateRequest re-runs an artifact's stored prompt_render, model_params and
+// schema template.
+type RegenerateRequest struct {
+	ArtifactOverrides
+	// Prompt replaces the stored prompt_render
+	Prompt *string `json:"prompt,omitempty"`
+}
+
+// RefineRequest sends an artifact's output back to the model with feedback.
+type RefineRequest struct {
+	ArtifactOverrides
+	Feedback string `json:"feedback"`
+}
+
+// lineageRecord is stored in artifact meta as "lineage" and sets the parent
+// columns of the derived artifact.
+type lineageRecord struct {
+	ParentArtifactID uuid.UUID          `json:"parent_artifact_id"`
+	Derivation       string             `json:"derivation"`
+	Feedback         string             `json:"feedback,omitempty"`
+	Overrides        *ArtifactOverrides `json:"overrides,omitempty"`
+}
+
+// Regenerate runs the inputs stored on parent again and saves the result as a
+// child of parent.
+func (s *Service) Regenerate(ctx context.Context, parent *store.Artifact, userID uuid.UUID, req RegenerateRequest) (*GenerateResponse, error) {
+	prompt := parent.PromptRender.String
+	if req.Prompt != nil {
+		prompt = strings.TrimSpace(*req.Prompt)
+		if prompt == "" {
+			return nil, fmt.Errorf("%w: prompt override is empty", ErrInvalidRequest)
+		}
+	}
+
+	return s.derive(ctx, parent, userID, prompt, req.ArtifactOverrides, &lineageRecord{
+		ParentArtifactID: parent.ID,
+		Derivation:       artifacts.DerivationRegenerate,
+	})
+}
+
+// Refine asks the model to revise parent's output according to feedback and
+// saves the result as a child of parent.
+func (s *Service) Refine(ctx context.Context, parent *store.Artifact, userID uuid.UUID, req RefineRequest) (*GenerateResponse, error) {
+	feedback := strings.TrimSpace(req.Feedback)
+	if feedback == "" {
+		return nil, fmt.Errorf("%w: feedback is required", ErrInvalidRequest)
+	}
+	if len(feedback) > maxRefineFeedbackLength {
+		return nil, fmt.Errorf("%w: feedback must be at most %d characters", ErrInvalidRequest, maxRefineFeedbackLength)
+	}
+	if strings.TrimSpace(parent.Text.String) == "" {
+		return nil, fmt.Errorf("%w: artifact has no output to refine", ErrInvalidRequest)
+	}
+
+	return s.derive(ctx, parent, userID, buildRefinePrompt(parent.PromptRender.String, parent.Text.String, feedback), req.ArtifactOverrides, &lineageRecord{
+		ParentArtifactID: parent.ID,
+		Derivation:       artifacts.DerivationRefine,
+		Feedback:         feedback,
+	})
+}
+
+// derive rebuilds a generation from parent's stored inputs with prompt as the
+// prompt text and runs it. Templates are not resolved again: the stored render
+// already contains the variables, partials and graph context of the original.
+func (s *Service) derive(ctx context.Context, parent *store.Artifact, userID uuid.UUID, prompt string, overrides ArtifactOverrides, lineage *lineageRecord) (*GenerateResponse, error) {
+	if s.generator == nil {
+		return nil, ErrGeneratorUnavailable
+	}
+	if strings.TrimSpace(parent.PromptRender.String) == "" {
+		return nil, fmt.Errorf("%w: artifact has no stored prompt", ErrInvalidRequest)
+	}
+
+	var meta artifactMeta
+	if parent.Meta.Valid {
+		if err := json.Unmarshal(parent.Meta.RawMessage, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse artifact meta: %w", err)
+		}
+	}
+
+	model, err := s.derivedModelConfig(ctx, parent, meta.ModelConfigID, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &resolvedOutput{}
+	if parent.SchemaTemplateID.Valid {
+		schemaTmpl, err := s.schemaTemplates.GetByID(ctx, parent.SchemaTemplateID.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch schema template %q: %w", parent.SchemaTemplateID.UUID, err)
+		}
+		output = &resolvedOutput{
+			Schema:           schemaTmpl.SchemaJSON,
+			SchemaTemplateID: schemaTmpl.ID,
+			SchemaVersion:    schemaTmpl.Version,
+		}
+	}
+
+	generationType := ""
+	if parent.GenerationType.Valid {
+		generationType = string(parent.GenerationType.GenerationType)
+	}
+	modelConfigID := meta.ModelConfigID
+	if overrides.ModelConfigID != nil {
+		modelConfigID = *overrides.ModelConfigID
+	}
+	req := GenerateRequest{
+		UserID: userID,
+		Target: Target{
+			DocumentID: meta.DocumentID,
+			EvalID:     nullUUIDPtr(parent.EvalID),
+			EvalItemID: nullUUIDPtr(parent.EvalItemID),
+			AttemptID:  nullUUIDPtr(parent.AttemptID),
+			ThreadID:   nullUUIDPtr(parent.ThreadID),
+		},
+		Instructions: Instructions{
+			SystemInstructionID: meta.SystemInstructionID,
+			GenerationType:      generationType,
+		},
+		Output:        OutputConfig{Format: "text", RepairAttempts: overrides.RepairAttempts},
+		ModelConfigID: modelConfigID,
+	}
+	if len(output.Schema) > 0 || parent.OutputJson.Valid {
+		req.Output.Format = "json"
+		req.Output.GenerationType = generationType
+	}
+
+	generatorReq := GeneratorRequest{
+		Prompt:            prompt,
+		SystemInstruction: meta.SystemInstructionText,
+		OutputSchema:      output.Schema,
+		Model:             model,
+	}
+	inputHash, err := computeInputHash(generatorReq, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if overrides != (ArtifactOverrides{}) {
+		lineage.Overrides = &overrides
+	}
+	return s.run(ctx, req, &preparedGeneration{
+		Request: generatorReq,
+		Instructions: &resolvedInstructions{
+			Prompt:            prompt,
+			SystemInstruction: meta.SystemInstructionText,
+			PromptTemplateID:  parent.PromptTemplateID.UUID,
+		},
+		Output:    output,
+		InputHash: inputHash,
+		Lineage:   lineage,
+	}, nil)
+}
+
+// derivedModelConfig returns the model config a derived generation runs with:
+// the override config when one is given, otherwise the stored model_params.
+// Pricing and rate limits are not part of model_params, so they are taken from
+// the stored model config when it still exists.
+func (s *Service) derivedModelConfig(ctx context.Context, parent *store.Artifact, storedConfigID uuid.UUID, overrides ArtifactOverrides) (*ModelConfig, error) {
+	var model *ModelConfig
+	switch {
+	case overrides.ModelConfigID != nil:
+		resolved, err := s.resolveModelConfig(ctx, *overrides.ModelConfigID)
+		if err != nil {
+			return nil, err
+		}
+		model = resolved
+	case parent.ModelParams.Valid:
+		model = &ModelConfig{}
+		if err := json.Unmarshal(parent.ModelParams.RawMessage, model); err != nil {
+			return nil, fmt.Errorf("failed to parse artifact model params: %w", err)
+		}
+		if model.Name == "" {
+			return nil, fmt.Errorf("%w: artifact model params have no model name", ErrInvalidRequest)
+		}
+		if storedConfigID != uuid.Nil {
+			if stored, err := s.resolveModelConfig(ctx, storedConfigID); err == nil && stored.Name == model.Name {
+				model.Pricing = stored.Pricing
+				model.RequestsPerMinute = stored.RequestsPerMinute
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: artifact has no stored model params", ErrInvalidRequest)
+	}
+
+	if overrides.Temperature != nil {
+		model.Temperature = overrides.Temperature
+	}
+	if overrides.MaxTokens != nil {
+		model.MaxTokens = overrides.MaxTokens
+	}
+	if overrides.Seed != nil {
+		model.Seed = overrides.Seed
+	}
+	return model, nil
+}
+
+func buildRefinePrompt(prompt, previousOutput, feedback string) string {
+	return fmt.Sprintf("%s\n\n[Previous Output]\n%s\n\n[Teacher Feedback]\n%s\n\nRevise the previous output to address the feedback. Keep everything the feedback does not ask to change and respond in the same format.",
+		prompt, truncate(previousOutput, 8000), feedback)
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
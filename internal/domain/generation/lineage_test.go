@@ -0,0 +1,59 @@
+package generation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+)
+
+func TestDerivedModelConfig_AppliesOverrides(t *testing.T) {
+	parent := &store.Artifact{
+		ID:          uuid.New(),
+		ModelParams: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"name": "gemini-2.5-flash", "temperature": 0.2, "max_tokens": 1024, "seed": 1}`), Valid: true},
+	}
+	temperature, seed := float32(0.9), int32(7)
+
+	model, err := (&Service{}).derivedModelConfig(context.Background(), parent, uuid.Nil, ArtifactOverrides{Temperature: &temperature, Seed: &seed})
+	require.NoError(t, err)
+	assert.Equal(t, "gemini-2.5-flash", model.Name)
+	assert.Equal(t, temperature, *model.Temperature)
+	assert.EqualValues(t, 7, *model.Seed)
+	assert.EqualValues(t, 1024, *model.MaxTokens)
+
+	_, err = (&Service{}).derivedModelConfig(context.Background(), &store.Artifact{ID: uuid.New()}, uuid.Nil, ArtifactOverrides{})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestRefine_Validation(t *testing.T) {
+	service := &Service{generator: &scriptedGenerator{}}
+	parent := &store.Artifact{
+		ID:           uuid.New(),
+		PromptRender: sql.NullString{String: "Generate 3 questions", Valid: true},
+		Text:         sql.NullString{String: `{"questions": []}`, Valid: true},
+	}
+
+	_, err := service.Refine(context.Background(), parent, uuid.New(), RefineRequest{Feedback: "  "})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	failed := *parent
+	failed.Text = sql.NullString{}
+	_, err = service.Refine(context.Background(), &failed, uuid.New(), RefineRequest{Feedback: "Make them harder"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = service.Regenerate(context.Background(), &store.Artifact{ID: uuid.New()}, uuid.New(), RegenerateRequest{})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestBuildRefinePrompt(t *testing.T) {
+	prompt := buildRefinePrompt("Generate 3 questions", `{"questions": ["q1"]}`, "Make them harder")
+	assert.Contains(t, prompt, "Generate 3 questions\n\n[Previous Output]\n{\"questions\": [\"q1\"]}")
+	assert.Contains(t, prompt, "[Teacher Feedback]\nMake them harder")
+}
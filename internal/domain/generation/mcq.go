@@ -0,0 +1,205 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"learning-core-api/internal/utils"
+)
+
+// A distractor counts as supported by the source when a single source sentence
+// contains nearly all of its key terms and most of the question's key terms,
+// i.e. the sentence states the distractor as an answer to the question.
+// Sentences that also state the correct answer are skipped, since they usually
+// contrast the two ("Paris, not Lyon, is ...").
+const (
+	distractorTermCoverage = 0.8
+	questionTermCoverage   = 0.6
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "do": true, "does": true, "for": true, "from": true, "has": true, "have": true,
+	"how": true, "in": true, "into": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "their": true, "this": true, "to": true, "was": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true, "who": true, "why": true,
+	"will": true, "with": true,
+}
+
+type multipleChoiceOutput struct {
+	Questions []multipleChoiceQuestion `json:"questions"`
+}
+
+type multipleChoiceQuestion struct {
+	Question   string   `json:"question"`
+	Options    []string `json:"options"`
+	CorrectIdx int      `json:"correct_idx"`
+}
+
+// validateMultipleChoice checks what the MULTIPLE_CHOICE schema cannot express:
+// correct_idx points at an option, options are distinct and no distractor is
+// supported by the source passages. The distractor check is skipped when there
+// are no sources.
+func validateMultipleChoice(outputText string, sources []string) []ValidationError {
+	var output multipleChoiceOutput
+	if err := json.Unmarshal([]byte(outputText), &output); err != nil {
+		return []ValidationError{{Path: "$", Message: "output is not valid JSON"}}
+	}
+
+	sentences := sourceSentences(sources)
+	var errs []ValidationError
+	for i, question := range output.Questions {
+		path := fmt.Sprintf("$.questions[%d]", i)
+		if question.CorrectIdx < 0 || question.CorrectIdx >= len(question.Options) {
+			errs = append(errs, ValidationError{
+				Path:    joinPath(path, "correct_idx"),
+				Message: fmt.Sprintf("must index one of the %d options, got %d", len(question.Options), question.CorrectIdx),
+			})
+			continue
+		}
+
+		seen := map[string]int{}
+		for j, option := range question.Options {
+			key := strings.ToLower(strings.TrimSpace(option))
+			if first, ok := seen[key]; ok {
+				errs = append(errs, ValidationError{
+					Path:    fmt.Sprintf("%s.options[%d]", path, j),
+					Message: fmt.Sprintf("duplicates option %d", first),
+				})
+				continue
+			}
+			seen[key] = j
+		}
+
+		correct := keyTerms(question.Options[question.CorrectIdx])
+		stem := keyTerms(question.Question)
+		for j, option := range question.Options {
+			if j == question.CorrectIdx {
+				continue
+			}
+			if sentence, ok := supportingSentence(keyTerms(option), stem, correct, sentences); ok {
+				errs = append(errs, ValidationError{
+					Path:    fmt.Sprintf("%s.options[%d]", path, j),
+					Message: fmt.Sprintf("distractor is supported by the source text (%q); replace it with a plausible option the source does not support", truncate(sentence, 200)),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// supportingSentence returns the first sentence that states the distractor as
+// an answer to the question.
+func supportingSentence(distractor, stem, correct map[string]bool, sentences []string) (string, bool) {
+	if len(distractor) == 0 {
+		return "", false
+	}
+	for _, sentence := range sentences {
+		terms := keyTerms(sentence)
+		if termCoverage(distractor, terms) < distractorTermCoverage {
+			continue
+		}
+		if len(stem) > 0 && termCoverage(stem, terms) < questionTermCoverage {
+			continue
+		}
+		if len(correct) > 0 && termCoverage(correct, terms) >= distractorTermCoverage {
+			continue
+		}
+		return sentence, true
+	}
+	return "", false
+}
+
+// distractorSources collects the passages MULTIPLE_CHOICE distractors are
+// checked against: retrieved file_search chunks, the graph_rag context and the
+// pages of the target document.
+func (s *Service) distractorSources(ctx context.Context, req GenerateRequest, graphContext string, groundingMetadata json.RawMessage) []string {
+	var sources []string
+	if len(groundingMetadata) > 0 {
+		var grounding struct {
+			GroundingChunks []struct {
+				RetrievedContext struct {
+					Text string `json:"text"`
+				} `json:"retrievedContext"`
+			} `json:"groundingChunks"`
+		}
+		if err := json.Unmarshal(groundingMetadata, &grounding); err == nil {
+			for _, chunk := range grounding.GroundingChunks {
+				if text := strings.TrimSpace(chunk.RetrievedContext.Text); text != "" {
+					sources = append(sources, text)
+				}
+			}
+		}
+	}
+	if graphContext != "" {
+		sources = append(sources, graphContext)
+	}
+	if s.graphRepo != nil && req.Target.DocumentID != nil {
+		pages, err := s.graphRepo.ListPageNodes(ctx, *req.Target.DocumentID)
+		if err != nil {
+			log.Printf("[GENERATION] [Document:%s] failed to load pages for distractor check: %v", *req.Target.DocumentID, err)
+		}
+		for _, page := range pages {
+			sources = append(sources, page.Text)
+		}
+	}
+	return sources
+}
+
+// isMultipleChoice reports whether req produces MULTIPLE_CHOICE output.
+func isMultipleChoice(req GenerateRequest) bool {
+	generationType := req.Output.GenerationType
+	if generationType == "" {
+		generationType = req.Instructions.GenerationType
+	}
+	return utils.GenerationType(generationType) == utils.GenerationTypeMultipleChoice
+}
+
+func sourceSentences(sources []string) []string {
+	var sentences []string
+	for _, source := range sources {
+		for _, sentence := range strings.FieldsFunc(source, func(r rune) bool {
+			return r == '.' || r == '!' || r == '?' || r == '\n' || r == ';'
+		}) {
+			if sentence = strings.TrimSpace(sentence); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+		}
+	}
+	return sentences
+}
+
+// keyTerms returns the lower-cased content words of text with a crude plural
+// folding, so "enzymes" and "enzyme" are the same term.
+func keyTerms(text string) map[string]bool {
+	terms := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if stopWords[word] || (len(word) < 2 && !unicode.IsDigit(rune(word[0]))) {
+			continue
+		}
+		if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		terms[word] = true
+	}
+	return terms
+}
+
+func termCoverage(want, have map[string]bool) float64 {
+	if len(want) == 0 {
+		return 0
+	}
+	matched := 0
+	for term := range want {
+		if have[term] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(want))
+}
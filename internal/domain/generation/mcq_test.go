@@ -0,0 +1,68 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mcqSources = []string{
+	"Paris is the capital of France. Lyon is the third-largest city in France and sits where the Rhone meets the Saone.",
+	"The Loire is the longest river in France.",
+}
+
+func TestValidateMultipleChoice_FlagsSupportedDistractors(t *testing.T) {
+	output := `{"questions": [
+		{"question": "What is the capital of France?", "options": ["Lyon", "Paris", "Marseille", "Nice"], "correct_idx": 1},
+		{"question": "Which is the longest river in France?", "options": ["Seine", "Rhone", "Loire"], "correct_idx": 0}
+	]}`
+
+	errs := validateMultipleChoice(output, mcqSources)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$.questions[1].options[2]", errs[0].Path)
+	assert.Contains(t, errs[0].Message, "The Loire is the longest river in France")
+}
+
+func TestValidateMultipleChoice_SkipsContrastingSentences(t *testing.T) {
+	sources := []string{"The capital of France is Paris, not Lyon."}
+	output := `{"questions": [{"question": "What is the capital of France?", "options": ["Lyon", "Paris"], "correct_idx": 1}]}`
+
+	assert.Empty(t, validateMultipleChoice(output, sources))
+	assert.Empty(t, validateMultipleChoice(output, nil))
+}
+
+func TestValidateMultipleChoice_Structure(t *testing.T) {
+	output := `{"questions": [
+		{"question": "Q1", "options": ["A", "B"], "correct_idx": 2},
+		{"question": "Q2", "options": ["A", "a ", "B"], "correct_idx": 0}
+	]}`
+
+	errs := validateMultipleChoice(output, nil)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "$.questions[0].correct_idx", errs[0].Path)
+	assert.Equal(t, "$.questions[1].options[1]", errs[1].Path)
+	assert.Equal(t, "duplicates option 0", errs[1].Message)
+}
+
+func TestValidateAndRepairWith_RepairsSupportedDistractor(t *testing.T) {
+	schema := json.RawMessage(`{"type": "OBJECT", "properties": {"questions": {"type": "ARRAY"}}, "required": ["questions"]}`)
+	generator := &scriptedGenerator{outputs: []string{
+		`{"questions": [{"question": "What is the capital of France?", "options": ["Paris", "Nice"], "correct_idx": 0}]}`,
+	}}
+	service := &Service{generator: generator}
+	check := func(outputText string) []ValidationError {
+		return validateMultipleChoice(outputText, mcqSources)
+	}
+
+	resp, errs, attempts, err := service.validateAndRepairWith(context.Background(), GeneratorRequest{OutputSchema: schema},
+		&GeneratorResponse{OutputText: `{"questions": [{"question": "Which is the longest river in France?", "options": ["Seine", "Loire"], "correct_idx": 0}]}`}, 2, check)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, attempts)
+	assert.Contains(t, resp.OutputText, "Nice")
+	require.Len(t, generator.prompts, 1)
+	assert.Contains(t, generator.prompts[0], "distractor is supported by the source text")
+}
@@ -4,6 +4,16 @@ import (
 	"encoding/json"
 
 	"github.com/google/uuid"
+
+	"learning-core-api/internal/domain/model_configs"
+	"learning-core-api/internal/domain/prompt_templates"
+)
+
+// Artifact statuses written by the generation service.
+const (
+	ArtifactStatusReady   = "READY"
+	ArtifactStatusInvalid = "INVALID"
+	ArtifactStatusError   = "ERROR"
 )
 
 // GenerateRequest is the unified entry point for all AI generation tasks.
@@ -26,6 +36,17 @@ type GenerateRequest struct {
 
 	// Model Configuration
 	ModelConfigID uuid.UUID `json:"model_config_id"`
+
+	// FallbackModelConfigIDs are tried in order when the primary model keeps failing
+	FallbackModelConfigIDs []uuid.UUID `json:"fallback_model_config_ids,omitempty"`
+
+	// ReuseCached copies the output of the latest APPROVED or READY artifact
+	// with the same input hash into a new artifact for this request instead of
+	// calling the model again
+	ReuseCached bool `json:"reuse_cached,omitempty"`
+
+	// History is the earlier conversation, oldest first, sent before the prompt
+	History []HistoryMessage `json:"history,omitempty"`
 }
 
 type Target struct {
@@ -33,14 +54,19 @@ type Target struct {
 	EvalID     *uuid.UUID `json:"eval_id,omitempty"`
 	EvalItemID *uuid.UUID `json:"eval_item_id,omitempty"`
 	AttemptID  *uuid.UUID `json:"attempt_id,omitempty"`
+	ThreadID   *uuid.UUID `json:"thread_id,omitempty"`
 }
 
 type Instructions struct {
 	SystemInstructionID *uuid.UUID             `json:"system_instruction_id,omitempty"`
 	GenerationType      string                 `json:"generation_type,omitempty"` // Reference to DB template
-	PromptVersion       int32                  `json:"prompt_version,omitempty"`  // 0 for latest
+	PromptVersion       int32                  `json:"prompt_version,omitempty"`  // 0 for latest, or a variant of the running experiment
 	Variables           map[string]interface{} `json:"variables,omitempty"`       // Variables to inject into template
 	Inline              string                 `json:"inline,omitempty"`          // Raw prompt text (if not using generation type)
+
+	// PartialVersions pins partials included by the template to a version;
+	// partials that are not listed use their active version
+	PartialVersions map[string]int32 `json:"partial_versions,omitempty"`
 }
 
 type OutputConfig struct {
@@ -48,6 +74,7 @@ type OutputConfig struct {
 	SchemaVersion  int32           `json:"schema_version,omitempty"`  // 0 for latest
 	InlineSchema   json.RawMessage `json:"inline_schema,omitempty"`   // Raw JSON Schema
 	Format         string          `json:"format"`                    // "text" or "json"
+	RepairAttempts int             `json:"repair_attempts,omitempty"` // Re-prompts allowed when output fails schema validation
 }
 
 type ToolConfig struct {
@@ -62,13 +89,51 @@ type ModelConfig struct {
 	TopP        *float32 `json:"top_p,omitempty"`
 	TopK        *float32 `json:"top_k,omitempty"`
 	MimeType    string   `json:"mime_type,omitempty"` // e.g. "application/json"
+	Provider    string   `json:"provider,omitempty"`  // "gemini", "openai" or "synthetic"; empty means gemini
+	BaseURL     string   `json:"base_url,omitempty"`  // endpoint override for OpenAI-compatible providers
+
+	Seed             *int32                        `json:"seed,omitempty"`
+	StopSequences    []string                      `json:"stop_sequences,omitempty"`
+	CandidateCount   *int32                        `json:"candidate_count,omitempty"`
+	PresencePenalty  *float32                      `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32                      `json:"frequency_penalty,omitempty"`
+	ThinkingBudget   *int32                        `json:"thinking_budget,omitempty"`
+	SafetySettings   []model_configs.SafetySetting `json:"safety_settings,omitempty"`
+
+	// RequestsPerMinute is enforced by RateLimitedGenerator. It does not affect
+	// output, so it is left out of model_params and the input hash.
+	RequestsPerMinute *int32 `json:"-"`
+
+	// Pricing is used to compute the artifact cost; nil leaves the cost unset.
+	Pricing *ModelPricing `json:"-"`
 }
 
 type GenerateResponse struct {
-	ArtifactID       uuid.UUID       `json:"artifact_id"`
-	OutputText       string          `json:"output_text"`
-	OutputJSON       json.RawMessage `json:"output_json,omitempty"`
-	FinishReason     string          `json:"finish_reason"`
-	ModelUsed        string          `json:"model_used"`
-	GroundingMetadata json.RawMessage `json:"grounding_metadata,omitempty"`
+	ArtifactID        uuid.UUID         `json:"artifact_id"`
+	Status            string            `json:"status"`
+	OutputText        string            `json:"output_text"`
+	OutputJSON        json.RawMessage   `json:"output_json,omitempty"`
+	FinishReason      string            `json:"finish_reason"`
+	ModelUsed         string            `json:"model_used"`
+	GroundingMetadata json.RawMessage   `json:"grounding_metadata,omitempty"`
+	InputHash         string            `json:"input_hash,omitempty"`
+	Cached            bool              `json:"cached,omitempty"`
+	ValidationErrors  []ValidationError `json:"validation_errors,omitempty"`
+	Usage             *TokenUsage       `json:"usage,omitempty"`
+	CostUSD           *float64          `json:"cost_usd,omitempty"`
+}
+
+// PreviewResponse is the result of a dry run: the request that would be sent to
+// the model and the template versions it was built from.
+type PreviewResponse struct {
+	Request          GeneratorRequest                   `json:"request"`
+	PromptTemplateID *uuid.UUID                         `json:"prompt_template_id,omitempty"`
+	PromptVersion    int32                              `json:"prompt_version,omitempty"`
+	PromptPartials   []prompt_templates.PartialVersion  `json:"prompt_partials,omitempty"`
+	Experiment       *prompt_templates.PromptExperiment `json:"experiment,omitempty"` // running prompt experiment; generations pick one of its variants
+	SchemaTemplateID *uuid.UUID                         `json:"schema_template_id,omitempty"`
+	SchemaVersion    int32                              `json:"schema_version,omitempty"`
+	GraphContext     string                             `json:"graph_context,omitempty"` // the "[Graph Context]" block appended to the prompt
+	MaxToolRounds    int                                `json:"max_tool_rounds,omitempty"`
+	InputHash        string                             `json:"input_hash"`
 }
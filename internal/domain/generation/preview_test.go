@@ -0,0 +1,51 @@
+package generation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/model_configs"
+	"learning-core-api/internal/domain/prompt_templates"
+	"learning-core-api/internal/domain/schema_templates"
+	"learning-core-api/internal/domain/system_instructions"
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+	"learning-core-api/internal/utils"
+)
+
+func TestPreviewRendersSeededQuestionsTemplate(t *testing.T) {
+	tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	queries := store.New(tx)
+	// No generator or artifacts service: a preview must not need either.
+	service := &Service{
+		modelConfigs:       model_configs.NewRepository(queries),
+		promptTemplates:    prompt_templates.NewRepository(queries),
+		systemInstructions: system_instructions.NewRepository(queries),
+		schemaTemplates:    schema_templates.NewRepository(queries),
+	}
+	generationType := utils.GenerationTypeQuestions.String()
+
+	preview, err := service.Preview(ctx, GenerateRequest{
+		Instructions: Instructions{GenerationType: generationType, Variables: map[string]interface{}{"question_count": 3}},
+		Output:       OutputConfig{GenerationType: generationType, Format: "json"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, preview.Request.Prompt, "Generate 3 factual questions")
+	assert.Contains(t, preview.Request.Prompt, "Use ONLY the provided source documents.")
+	assert.NotEmpty(t, preview.Request.OutputSchema)
+	require.NotNil(t, preview.Request.Model)
+	require.NotNil(t, preview.PromptTemplateID)
+	require.NotNil(t, preview.SchemaTemplateID)
+	assert.Positive(t, preview.PromptVersion)
+	assert.Positive(t, preview.SchemaVersion)
+	require.Len(t, preview.PromptPartials, 1)
+	assert.Equal(t, "grounding_rules", preview.PromptPartials[0].Name)
+	assert.NotEmpty(t, preview.InputHash)
+}
@@ -42,3 +42,29 @@ func TestQuestionsTemplatesSeeded(t *testing.T) {
 	_, ok = props["questions"]
 	assert.True(t, ok, "schema should define questions")
 }
+
+func TestStudyAidTemplatesSeeded(t *testing.T) {
+	tx, cleanup := testutil.NewTestTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	queries := store.New(tx)
+	promptRepo := prompt_templates.NewRepository(queries)
+	schemaRepo := schema_templates.NewRepository(queries)
+
+	for _, generationType := range []utils.GenerationType{
+		utils.GenerationTypeFlashcards,
+		utils.GenerationTypeSummary,
+		utils.GenerationTypeOutline,
+		utils.GenerationTypeHint,
+	} {
+		prompt, err := promptRepo.GetActiveByGenerationType(ctx, generationType.String())
+		require.NoError(t, err, generationType)
+		assert.Contains(t, prompt.Template, `{{template "grounding_rules" .}}`)
+
+		schema, err := schemaRepo.GetActiveByGenerationType(ctx, generationType.String())
+		require.NoError(t, err, generationType)
+		assert.True(t, json.Valid(schema.SchemaJSON))
+		assert.NotEqual(t, "OTHER", string(generationType.ArtifactType()))
+	}
+}
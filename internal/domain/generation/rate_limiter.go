@@ -0,0 +1,88 @@
+package generation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitedGenerator enforces ModelConfig.RequestsPerMinute per model name.
+// Limits are shared by every caller in the process (single generations, streams
+// and batch workers). Place it inside ResilientGenerator so retries and
+// fallbacks are limited as well; ResilientGenerator then waits for the limit
+// before it starts the call's deadline, so queueing does not time calls out.
+type RateLimitedGenerator struct {
+	next     Generator
+	mu       sync.Mutex
+	limiters map[string]*modelLimiter
+}
+
+type modelLimiter struct {
+	rpm     int32
+	limiter *rate.Limiter
+}
+
+func NewRateLimitedGenerator(next Generator) *RateLimitedGenerator {
+	return &RateLimitedGenerator{next: next, limiters: make(map[string]*modelLimiter)}
+}
+
+func (g *RateLimitedGenerator) Generate(ctx context.Context, req GeneratorRequest) (*GeneratorResponse, error) {
+	if err := g.wait(ctx, req.Model); err != nil {
+		return nil, err
+	}
+	return g.next.Generate(ctx, req)
+}
+
+func (g *RateLimitedGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, onChunk ChunkHandler) (*GeneratorResponse, error) {
+	if err := g.wait(ctx, req.Model); err != nil {
+		return nil, err
+	}
+	return g.next.GenerateStream(ctx, req, onChunk)
+}
+
+func (g *RateLimitedGenerator) wait(ctx context.Context, model *ModelConfig) error {
+	if waited, _ := ctx.Value(rateLimitWaitedKey{}).(bool); waited {
+		return nil
+	}
+	limiter := g.limiterFor(model)
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// rateLimitWaitedKey marks a context whose call already waited for its turn.
+type rateLimitWaitedKey struct{}
+
+// waitTurn blocks until a call to model may start and returns a context on
+// which the call does not wait again.
+func (g *RateLimitedGenerator) waitTurn(ctx context.Context, model *ModelConfig) (context.Context, error) {
+	if err := g.wait(ctx, model); err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, rateLimitWaitedKey{}, true), nil
+}
+
+// limiterFor returns the limiter for a model, replacing it when the configured
+// rate changes (e.g. after a new model config version is activated).
+func (g *RateLimitedGenerator) limiterFor(model *ModelConfig) *rate.Limiter {
+	if model == nil || model.RequestsPerMinute == nil || *model.RequestsPerMinute <= 0 {
+		return nil
+	}
+	rpm := *model.RequestsPerMinute
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	current, ok := g.limiters[model.Name]
+	if !ok || current.rpm != rpm {
+		current = &modelLimiter{
+			rpm:     rpm,
+			limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(rpm)), 1),
+		}
+		g.limiters[model.Name] = current
+	}
+	return current.limiter
+}
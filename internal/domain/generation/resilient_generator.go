@@ -0,0 +1,279 @@
+package generation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures ResilientGenerator.
+type RetryPolicy struct {
+	MaxAttempts  int           // attempts per model before moving to the next fallback
+	InitialDelay time.Duration // delay before the first retry
+	MaxDelay     time.Duration // upper bound for a single backoff delay
+	Multiplier   float64       // backoff growth factor
+	CallTimeout  time.Duration // deadline applied to each individual call; 0 disables it
+}
+
+// DefaultRetryPolicy returns the policy used by the API server.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     8 * time.Second,
+		Multiplier:   2,
+		CallTimeout:  2 * time.Minute,
+	}
+}
+
+// GeneratorAttempt records a single call made by ResilientGenerator.
+type GeneratorAttempt struct {
+	Model      string `json:"model"`
+	Attempt    int    `json:"attempt"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	Retryable  bool   `json:"retryable,omitempty"`
+}
+
+// ProviderError carries the HTTP status returned by a model provider so errors
+// can be classified without depending on provider SDK types.
+type ProviderError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// AttemptsError is returned when every attempt failed; it keeps the attempt log
+// so callers can persist it.
+type AttemptsError struct {
+	Attempts []GeneratorAttempt
+	Err      error
+}
+
+func (e *AttemptsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AttemptsError) Unwrap() error {
+	return e.Err
+}
+
+// attemptsFromError extracts the attempt log from an AttemptsError, if any.
+func attemptsFromError(err error) []GeneratorAttempt {
+	var attemptsErr *AttemptsError
+	if errors.As(err, &attemptsErr) {
+		return attemptsErr.Attempts
+	}
+	return nil
+}
+
+// IsRetryableError reports whether a generator error is transient.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrGeneratorUnavailable) {
+		return false
+	}
+	var stopErr *nonRetryableError
+	if errors.As(err, &stopErr) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		switch providerErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+			return true
+		}
+		return providerErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// ResilientGenerator wraps a Generator with per-call deadlines, exponential
+// backoff with jitter for retryable errors and an ordered fallback model chain.
+type ResilientGenerator struct {
+	next   Generator
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func NewResilientGenerator(next Generator, policy RetryPolicy) *ResilientGenerator {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 1
+	}
+	return &ResilientGenerator{next: next, policy: policy, sleep: sleepContext}
+}
+
+func (g *ResilientGenerator) Generate(ctx context.Context, req GeneratorRequest) (*GeneratorResponse, error) {
+	return g.run(ctx, req, func(callCtx context.Context, callReq GeneratorRequest) (*GeneratorResponse, error) {
+		return g.next.Generate(callCtx, callReq)
+	})
+}
+
+// GenerateStream retries only while no chunk has been delivered; once output has
+// reached the caller a failure is returned as-is.
+func (g *ResilientGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, onChunk ChunkHandler) (*GeneratorResponse, error) {
+	streamed := false
+	forward := func(chunk string) error {
+		streamed = true
+		if onChunk == nil {
+			return nil
+		}
+		return onChunk(chunk)
+	}
+	return g.run(ctx, req, func(callCtx context.Context, callReq GeneratorRequest) (*GeneratorResponse, error) {
+		resp, err := g.next.GenerateStream(callCtx, callReq, forward)
+		if err != nil && streamed {
+			return nil, &nonRetryableError{err: err}
+		}
+		return resp, err
+	})
+}
+
+func (g *ResilientGenerator) run(ctx context.Context, req GeneratorRequest, call func(context.Context, GeneratorRequest) (*GeneratorResponse, error)) (*GeneratorResponse, error) {
+	models := append([]*ModelConfig{req.Model}, req.Fallbacks...)
+	var attempts []GeneratorAttempt
+	var lastErr error
+
+	for _, model := range models {
+		callReq := req
+		callReq.Model = model
+		callReq.Fallbacks = nil
+
+		delay := g.policy.InitialDelay
+		for attempt := 1; attempt <= g.policy.MaxAttempts; attempt++ {
+			started := time.Now()
+			resp, err := g.callWithDeadline(ctx, callReq, call)
+			record := GeneratorAttempt{
+				Model:      modelNameForArtifact(model),
+				Attempt:    attempt,
+				DurationMS: time.Since(started).Milliseconds(),
+			}
+
+			if err == nil {
+				attempts = append(attempts, record)
+				resp.Attempts = attempts
+				resp.Model = model
+				if resp.ModelUsed == "" {
+					resp.ModelUsed = record.Model
+				}
+				return resp, nil
+			}
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			retryable := IsRetryableError(err)
+			record.Error = err.Error()
+			record.Retryable = retryable
+			attempts = append(attempts, record)
+			lastErr = err
+
+			if !retryable {
+				return nil, &AttemptsError{Attempts: attempts, Err: err}
+			}
+			if attempt == g.policy.MaxAttempts {
+				break
+			}
+			if err := g.sleep(ctx, g.jitter(delay)); err != nil {
+				return nil, err
+			}
+			delay = g.nextDelay(delay)
+		}
+	}
+
+	return nil, &AttemptsError{
+		Attempts: attempts,
+		Err:      fmt.Errorf("all %d attempts failed: %w", len(attempts), lastErr),
+	}
+}
+
+// turnWaiter is implemented by generators that queue calls, such as
+// RateLimitedGenerator. The returned context lets the call skip the queue.
+type turnWaiter interface {
+	waitTurn(ctx context.Context, model *ModelConfig) (context.Context, error)
+}
+
+// callWithDeadline applies CallTimeout to a single call. Time spent queueing in
+// the wrapped generator does not count against it.
+func (g *ResilientGenerator) callWithDeadline(ctx context.Context, req GeneratorRequest, call func(context.Context, GeneratorRequest) (*GeneratorResponse, error)) (*GeneratorResponse, error) {
+	if waiter, ok := g.next.(turnWaiter); ok {
+		waited, err := waiter.waitTurn(ctx, req.Model)
+		if err != nil {
+			return nil, err
+		}
+		ctx = waited
+	}
+	if g.policy.CallTimeout <= 0 {
+		return call(ctx, req)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, g.policy.CallTimeout)
+	defer cancel()
+	return call(callCtx, req)
+}
+
+func (g *ResilientGenerator) nextDelay(delay time.Duration) time.Duration {
+	next := time.Duration(float64(delay) * g.policy.Multiplier)
+	if g.policy.MaxDelay > 0 && next > g.policy.MaxDelay {
+		return g.policy.MaxDelay
+	}
+	return next
+}
+
+// jitter returns a random delay between d/2 and d ("equal jitter").
+func (g *ResilientGenerator) jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// nonRetryableError marks an error that must not be retried even if its cause is transient.
+type nonRetryableError struct {
+	err error
+}
+
+func (e *nonRetryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *nonRetryableError) Unwrap() error {
+	return e.err
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package generation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyGenerator struct {
+	failures map[string][]error
+	calls    []string
+}
+
+func (g *flakyGenerator) Generate(_ context.Context, req GeneratorRequest) (*GeneratorResponse, error) {
+	g.calls = append(g.calls, req.Model.Name)
+	if queue := g.failures[req.Model.Name]; len(queue) > 0 {
+		g.failures[req.Model.Name] = queue[1:]
+		return nil, queue[0]
+	}
+	return &GeneratorResponse{OutputText: "ok", ModelUsed: req.Model.Name}, nil
+}
+
+func (g *flakyGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, _ ChunkHandler) (*GeneratorResponse, error) {
+	return g.Generate(ctx, req)
+}
+
+func newTestResilientGenerator(next Generator) *ResilientGenerator {
+	g := NewResilientGenerator(next, RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 2})
+	g.sleep = func(context.Context, time.Duration) error { return nil }
+	return g
+}
+
+func unavailable() error {
+	return &ProviderError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("503 UNAVAILABLE")}
+}
+
+func TestResilientGenerator_RetriesTransientErrors(t *testing.T) {
+	next := &flakyGenerator{failures: map[string][]error{"primary": {unavailable(), unavailable()}}}
+
+	resp, err := newTestResilientGenerator(next).Generate(context.Background(), GeneratorRequest{Model: &ModelConfig{Name: "primary"}})
+	require.NoError(t, err)
+	assert.Equal(t, "primary", resp.ModelUsed)
+	require.Len(t, resp.Attempts, 3)
+	assert.True(t, resp.Attempts[0].Retryable)
+	assert.Empty(t, resp.Attempts[2].Error)
+}
+
+func TestResilientGenerator_FallsBackAfterExhaustingRetries(t *testing.T) {
+	next := &flakyGenerator{failures: map[string][]error{"primary": {unavailable(), unavailable(), unavailable()}}}
+
+	resp, err := newTestResilientGenerator(next).Generate(context.Background(), GeneratorRequest{
+		Model:     &ModelConfig{Name: "primary"},
+		Fallbacks: []*ModelConfig{{Name: "backup"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "backup", resp.ModelUsed)
+	assert.Equal(t, []string{"primary", "primary", "primary", "backup"}, next.calls)
+	assert.Len(t, resp.Attempts, 4)
+}
+
+func TestResilientGenerator_ReportsFallbackConfigWithSameName(t *testing.T) {
+	next := &flakyGenerator{failures: map[string][]error{"gpt-4o": {unavailable(), unavailable(), unavailable()}}}
+	primary := &ModelConfig{Name: "gpt-4o", Pricing: &ModelPricing{}}
+	backup := &ModelConfig{Name: "gpt-4o", Pricing: &ModelPricing{}}
+	req := GeneratorRequest{Model: primary, Fallbacks: []*ModelConfig{backup}}
+
+	resp, err := newTestResilientGenerator(next).Generate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Same(t, backup, resp.Model)
+	assert.Same(t, backup, modelUsedConfig(req, resp))
+}
+
+func TestResilientGenerator_FatalErrorStops(t *testing.T) {
+	badRequest := &ProviderError{StatusCode: http.StatusBadRequest, Err: errors.New("400 INVALID_ARGUMENT")}
+	next := &flakyGenerator{failures: map[string][]error{"primary": {badRequest}}}
+
+	_, err := newTestResilientGenerator(next).Generate(context.Background(), GeneratorRequest{
+		Model:     &ModelConfig{Name: "primary"},
+		Fallbacks: []*ModelConfig{{Name: "backup"}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"primary"}, next.calls)
+
+	attempts := attemptsFromError(err)
+	require.Len(t, attempts, 1)
+	assert.False(t, attempts[0].Retryable)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	assert.True(t, IsRetryableError(&ProviderError{StatusCode: http.StatusTooManyRequests, Err: errors.New("429")}))
+	assert.True(t, IsRetryableError(context.DeadlineExceeded))
+	assert.False(t, IsRetryableError(context.Canceled))
+	assert.False(t, IsRetryableError(&ProviderError{StatusCode: http.StatusNotFound, Err: errors.New("404")}))
+	assert.False(t, IsRetryableError(ErrGeneratorUnavailable))
+}
@@ -0,0 +1,362 @@
+package generation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single mismatch between model output and its schema.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// maxSchemaRefDepth bounds $ref expansion for recursive schemas.
+const maxSchemaRefDepth = 32
+
+// ValidateOutput checks output against a schema template. Both Gemini-style
+// ("type": "OBJECT", "nullable") and draft-07 ("type": ["string", "null"],
+// "additionalProperties") schemas are supported. The returned error is only set
+// when the schema itself cannot be parsed.
+func ValidateOutput(schema json.RawMessage, output []byte) ([]ValidationError, error) {
+	var root any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(output, &value); err != nil {
+		return []ValidationError{{Path: "$", Message: fmt.Sprintf("output is not valid JSON: %v", err)}}, nil
+	}
+
+	rootMap, _ := root.(map[string]any)
+	v := &schemaValidator{root: rootMap}
+	v.validate(root, value, "$", 0)
+	return v.errors, nil
+}
+
+type schemaValidator struct {
+	root   map[string]any
+	errors []ValidationError
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errors = append(v.errors, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value satisfies schema without recording errors.
+func (v *schemaValidator) matches(schema any, value any, depth int) bool {
+	probe := &schemaValidator{root: v.root}
+	probe.validate(schema, value, "$", depth)
+	return len(probe.errors) == 0
+}
+
+func (v *schemaValidator) validate(schemaNode any, value any, path string, depth int) {
+	schema, ok := schemaNode.(map[string]any)
+	if !ok {
+		// `true`, `{}` or unknown schema shapes accept anything.
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if depth >= maxSchemaRefDepth {
+			return
+		}
+		resolved := resolveSchemaRef(v.root, ref)
+		if resolved == nil {
+			v.fail(path, "unresolvable $ref %q", ref)
+			return
+		}
+		v.validate(resolved, value, path, depth+1)
+		return
+	}
+
+	if value == nil && allowsNull(schema) {
+		return
+	}
+
+	if options, ok := schema["allOf"].([]any); ok {
+		for _, option := range options {
+			v.validate(option, value, path, depth)
+		}
+	}
+	if options, ok := schema["anyOf"].([]any); ok {
+		matched := false
+		for _, option := range options {
+			if v.matches(option, value, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "value does not match any allowed schema")
+		}
+	}
+	if options, ok := schema["oneOf"].([]any); ok {
+		count := 0
+		for _, option := range options {
+			if v.matches(option, value, depth) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.fail(path, "value must match exactly one schema, matched %d", count)
+		}
+	}
+
+	if expected, ok := schema["const"]; ok && !reflect.DeepEqual(expected, value) {
+		v.fail(path, "value must equal %v", expected)
+	}
+	if enumValues, ok := schema["enum"].([]any); ok && len(enumValues) > 0 {
+		found := false
+		for _, candidate := range enumValues {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "value %v is not one of %v", value, enumValues)
+		}
+	}
+
+	types := schemaTypes(schema)
+	if len(types) > 0 && !matchesAnyType(types, value) {
+		v.fail(path, "expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		v.validateObject(schema, typed, path, depth)
+	case []any:
+		v.validateArray(schema, typed, path, depth)
+	case string:
+		v.validateString(schema, typed, path)
+	case float64:
+		v.validateNumber(schema, typed, path)
+	}
+}
+
+func (v *schemaValidator) validateObject(schema map[string]any, value map[string]any, path string, depth int) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, present := value[key]; key != "" && !present {
+				v.fail(joinPath(path, key), "required property is missing")
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if propSchema, ok := properties[key]; ok {
+			v.validate(propSchema, value[key], joinPath(path, key), depth)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(joinPath(path, key), "additional property is not allowed")
+			}
+		case map[string]any:
+			v.validate(additional, value[key], joinPath(path, key), depth)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(schema map[string]any, value []any, path string, depth int) {
+	if minItems, ok := schemaNumber(schema["minItems"]); ok && float64(len(value)) < minItems {
+		v.fail(path, "expected at least %d items, got %d", int(minItems), len(value))
+	}
+	if maxItems, ok := schemaNumber(schema["maxItems"]); ok && float64(len(value)) > maxItems {
+		v.fail(path, "expected at most %d items, got %d", int(maxItems), len(value))
+	}
+
+	items, ok := schema["items"]
+	if !ok {
+		return
+	}
+	for i, item := range value {
+		v.validate(items, item, fmt.Sprintf("%s[%d]", path, i), depth)
+	}
+}
+
+func (v *schemaValidator) validateString(schema map[string]any, value string, path string) {
+	length := float64(utf8.RuneCountInString(value))
+	if minLength, ok := schemaNumber(schema["minLength"]); ok && length < minLength {
+		v.fail(path, "expected at least %d characters", int(minLength))
+	}
+	if maxLength, ok := schemaNumber(schema["maxLength"]); ok && length > maxLength {
+		v.fail(path, "expected at most %d characters", int(maxLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok && pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(value) {
+			v.fail(path, "value does not match pattern %q", pattern)
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(schema map[string]any, value float64, path string) {
+	if minimum, ok := schemaNumber(schema["minimum"]); ok && value < minimum {
+		v.fail(path, "value %v is below minimum %v", value, minimum)
+	}
+	if maximum, ok := schemaNumber(schema["maximum"]); ok && value > maximum {
+		v.fail(path, "value %v is above maximum %v", value, maximum)
+	}
+	if exclusiveMin, ok := schemaNumber(schema["exclusiveMinimum"]); ok && value <= exclusiveMin {
+		v.fail(path, "value %v must be greater than %v", value, exclusiveMin)
+	}
+	if exclusiveMax, ok := schemaNumber(schema["exclusiveMaximum"]); ok && value >= exclusiveMax {
+		v.fail(path, "value %v must be less than %v", value, exclusiveMax)
+	}
+}
+
+func resolveSchemaRef(root map[string]any, ref string) any {
+	if ref == "#" {
+		return root
+	}
+	if !strings.HasPrefix(ref, "#/") || root == nil {
+		return nil
+	}
+
+	var current any = root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		current, ok = node[part]
+		if !ok {
+			return nil
+		}
+	}
+	return current
+}
+
+// schemaTypes returns the lowercased, non-null types a schema accepts.
+func schemaTypes(schema map[string]any) []string {
+	var types []string
+	switch typed := schema["type"].(type) {
+	case string:
+		types = append(types, strings.ToLower(typed))
+	case []any:
+		for _, candidate := range typed {
+			if name, ok := candidate.(string); ok {
+				types = append(types, strings.ToLower(name))
+			}
+		}
+	}
+	return types
+}
+
+func allowsNull(schema map[string]any) bool {
+	if nullable, ok := schema["nullable"].(bool); ok && nullable {
+		return true
+	}
+	for _, t := range schemaTypes(schema) {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyType(types []string, value any) bool {
+	for _, t := range types {
+		switch t {
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(float64); ok && n == math.Trunc(n) {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "type_unspecified":
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// schemaNumber reads numeric keywords. Gemini schemas may encode int64 values
+// such as minItems as strings.
+func schemaNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case string:
+		parsed, err := strconv.ParseFloat(typed, 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func joinPath(path, key string) string {
+	return path + "." + key
+}
+
+// formatValidationErrors renders validation errors for prompts and artifact error text.
+func formatValidationErrors(errs []ValidationError) string {
+	var builder strings.Builder
+	for _, e := range errs {
+		builder.WriteString(fmt.Sprintf("- %s: %s\n", e.Path, e.Message))
+	}
+	return strings.TrimSpace(builder.String())
+}
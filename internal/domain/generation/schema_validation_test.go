@@ -0,0 +1,123 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOutput_GeminiSchema(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/questions_schema.json")
+	require.NoError(t, err)
+
+	errs, err := ValidateOutput(schema, []byte(`{"questions": [{"id": "q1", "question": "Why?", "expected_answer": "Because"}]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = ValidateOutput(schema, []byte(`{"questions": [{"id": 7, "question": "Why?"}]}`))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ValidationError{
+		{Path: "$.questions[0].expected_answer", Message: "required property is missing"},
+		{Path: "$.questions[0].id", Message: "expected string, got number"},
+	}, errs)
+}
+
+func TestValidateOutput_Draft07Schema(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/section_topics_schema.json")
+	require.NoError(t, err)
+
+	errs, err := ValidateOutput(schema, []byte(`{"sections": [{"title": "Limits", "topic": "Calculus", "difficulty": 2, "summary": "Intro"}]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = ValidateOutput(schema, []byte(`{"sections": [{"title": "Limits", "topic": "Calculus", "difficulty": 9.5, "summary": "Intro", "extra": true}]}`))
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "$.sections[0].difficulty", errs[0].Path)
+	assert.Equal(t, "$.sections[0].extra", errs[1].Path)
+
+	errs, err = ValidateOutput(schema, []byte(`{"sections": []}`))
+	require.NoError(t, err)
+	assert.Equal(t, []ValidationError{{Path: "$.sections", Message: "expected at least 1 items, got 0"}}, errs)
+}
+
+func TestValidateOutput_RecursiveRefAndNullable(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/taxonomy_schema.json")
+	require.NoError(t, err)
+
+	errs, err := ValidateOutput(schema, []byte(`{"proposed_taxonomy": [{"name": "Algebra", "children": [{"name": "Linear", "children": [{"name": 3}]}]}]}`))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ValidationError{
+		{Path: "$.proposed_taxonomy[0].children[0].children[0].children", Message: "required property is missing"},
+		{Path: "$.proposed_taxonomy[0].children[0].children[0].name", Message: "expected string, got number"},
+	}, errs)
+
+	nullable := json.RawMessage(`{"type": "OBJECT", "properties": {"hint": {"type": "STRING", "nullable": true}}}`)
+	errs, err = ValidateOutput(nullable, []byte(`{"hint": null}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestValidateOutput_InvalidJSON(t *testing.T) {
+	errs, err := ValidateOutput(json.RawMessage(`{"type": "object"}`), []byte(`{"unterminated"`))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "$", errs[0].Path)
+}
+
+type scriptedGenerator struct {
+	outputs []string
+	prompts []string
+}
+
+func (g *scriptedGenerator) Generate(_ context.Context, req GeneratorRequest) (*GeneratorResponse, error) {
+	g.prompts = append(g.prompts, req.Prompt)
+	output := g.outputs[0]
+	g.outputs = g.outputs[1:]
+	return &GeneratorResponse{OutputText: output, FinishReason: "STOP"}, nil
+}
+
+func (g *scriptedGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, _ ChunkHandler) (*GeneratorResponse, error) {
+	return g.Generate(ctx, req)
+}
+
+func TestValidateAndRepair(t *testing.T) {
+	schema := json.RawMessage(`{"type": "OBJECT", "properties": {"title": {"type": "STRING"}}, "required": ["title"]}`)
+	generator := &scriptedGenerator{outputs: []string{`{"title": 1}`, `{"title": "Fixed"}`}}
+	service := &Service{generator: generator}
+
+	resp, errs, attempts, err := service.validateAndRepairWith(context.Background(), GeneratorRequest{Prompt: "Make a title", OutputSchema: schema}, &GeneratorResponse{OutputText: `{}`}, 3, nil)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, `{"title": "Fixed"}`, resp.OutputText)
+	require.Len(t, generator.prompts, 2)
+	assert.Contains(t, generator.prompts[0], "$.title: required property is missing")
+	assert.Contains(t, generator.prompts[1], "$.title: expected string, got number")
+
+	resp, errs, attempts, err = service.validateAndRepairWith(context.Background(), GeneratorRequest{OutputSchema: schema}, &GeneratorResponse{OutputText: `{}`}, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Zero(t, attempts)
+	assert.Equal(t, `{}`, resp.OutputText)
+}
+
+func TestValidateAndRepair_PrefersValidCandidate(t *testing.T) {
+	schema := json.RawMessage(`{"type": "OBJECT", "properties": {"title": {"type": "STRING"}}, "required": ["title"]}`)
+	generator := &scriptedGenerator{}
+	service := &Service{generator: generator}
+
+	resp, errs, attempts, err := service.validateAndRepairWith(context.Background(), GeneratorRequest{OutputSchema: schema}, &GeneratorResponse{
+		OutputText: `{}`,
+		Candidates: []string{`{}`, `{"title": 2}`, `{"title": "Second"}`},
+	}, 3, nil)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Zero(t, attempts)
+	assert.Equal(t, `{"title": "Second"}`, resp.OutputText)
+	assert.Empty(t, generator.prompts)
+}
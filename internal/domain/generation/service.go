@@ -5,9 +5,10 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"strings"
-	"text/template"
 
 	"github.com/google/uuid"
 
@@ -17,7 +18,9 @@ import (
 	"learning-core-api/internal/domain/prompt_templates"
 	"learning-core-api/internal/domain/schema_templates"
 	"learning-core-api/internal/domain/system_instructions"
+	"learning-core-api/internal/domain/taxonomy"
 	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
 )
 
 type Service struct {
@@ -28,6 +31,7 @@ type Service struct {
 	artifactsService   *artifacts.Service
 	generator          Generator
 	graphRepo          *document_graph.Repository
+	tools              *ToolRegistry
 }
 
 func NewService(db *sql.DB, artifactsService *artifacts.Service, generator Generator, graphRepo *document_graph.Repository) (*Service, error) {
@@ -36,6 +40,11 @@ func NewService(db *sql.DB, artifactsService *artifacts.Service, generator Gener
 	}
 
 	queries := store.New(db)
+	tools := NewToolRegistry()
+	if err := registerBuiltinTools(tools, taxonomy.NewRepository(queries), graphRepo); err != nil {
+		return nil, fmt.Errorf("failed to register built-in tools: %w", err)
+	}
+
 	return &Service{
 		modelConfigs:       model_configs.NewRepository(queries),
 		promptTemplates:    prompt_templates.NewRepository(queries),
@@ -44,12 +53,74 @@ func NewService(db *sql.DB, artifactsService *artifacts.Service, generator Gener
 		artifactsService:   artifactsService,
 		generator:          generator,
 		graphRepo:          graphRepo,
+		tools:              tools,
 	}, nil
 }
 
+// RegisterTool makes an additional function available to function_calling generations.
+func (s *Service) RegisterTool(tool FunctionTool) error {
+	return s.tools.Register(tool)
+}
+
 func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	return s.generate(ctx, req, nil)
+}
+
+// PreparedStream is a generation that has been validated and resolved but not
+// yet sent to the model.
+type PreparedStream struct {
+	service  *Service
+	req      GenerateRequest
+	prepared *preparedGeneration
+}
+
+// PrepareStream resolves req exactly as Generate would without calling the model,
+// so request errors surface before a stream is opened.
+func (s *Service) PrepareStream(ctx context.Context, req GenerateRequest) (*PreparedStream, error) {
+	if s.generator == nil {
+		return nil, ErrGeneratorUnavailable
+	}
+
+	prepared, err := s.prepare(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedStream{service: s, req: req, prepared: prepared}, nil
+}
+
+// Run behaves like Generate but forwards partial output to onChunk as it arrives.
+// The artifact is saved only after the stream completes; a cancelled context aborts the
+// stream without saving anything.
+func (p *PreparedStream) Run(ctx context.Context, onChunk ChunkHandler) (*GenerateResponse, error) {
+	if onChunk == nil {
+		return nil, fmt.Errorf("%w: stream handler is required", ErrInvalidRequest)
+	}
+	return p.service.run(ctx, p.req, p.prepared, onChunk)
+}
+
+// preparedGeneration is everything generate resolves before calling the model.
+type preparedGeneration struct {
+	Request       GeneratorRequest
+	Instructions  *resolvedInstructions
+	Output        *resolvedOutput
+	GraphContext  string
+	MaxToolRounds int
+	InputHash     string
+
+	// Lineage links the saved artifact to the one it was derived from.
+	Lineage *lineageRecord
+}
+
+// prepare runs every resolution step up to the Generator call. A preview
+// reports a running prompt experiment instead of picking one of its variants.
+func (s *Service) prepare(ctx context.Context, req GenerateRequest, preview bool) (*preparedGeneration, error) {
 	if req.Instructions.GenerationType != "" && req.Output.GenerationType != "" && req.Instructions.GenerationType != req.Output.GenerationType {
-		return nil, fmt.Errorf("generation_type mismatch between instructions and output")
+		return nil, fmt.Errorf("%w: generation_type mismatch between instructions and output", ErrInvalidRequest)
+	}
+	for _, generationType := range []string{req.Instructions.GenerationType, req.Output.GenerationType} {
+		if generationType != "" && !utils.GenerationType(generationType).IsValid() {
+			return nil, fmt.Errorf("%w: unknown generation_type %q", ErrInvalidRequest, generationType)
+		}
 	}
 
 	// 1. Resolve Model Configuration
@@ -58,76 +129,467 @@ func (s *Service) Generate(ctx context.Context, req GenerateRequest) (*GenerateR
 		return nil, err
 	}
 
+	fallbackModels := make([]*ModelConfig, 0, len(req.FallbackModelConfigIDs))
+	for _, fallbackID := range req.FallbackModelConfigIDs {
+		if fallbackID == uuid.Nil {
+			return nil, fmt.Errorf("%w: fallback model config id is required", ErrInvalidRequest)
+		}
+		fallbackModel, err := s.resolveModelConfig(ctx, fallbackID)
+		if err != nil {
+			return nil, err
+		}
+		fallbackModels = append(fallbackModels, fallbackModel)
+	}
+
 	// 2. Fetch/Resolve Instructions (Prompt + System Instructions)
-	promptText, systemInstr, promptTmplID, err := s.resolveInstructions(ctx, req.Instructions)
+	instructions, err := s.resolveInstructions(ctx, req.Instructions, preview)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. Fetch/Resolve Output Schema
-	responseSchema, schemaTmplID, err := s.resolveOutputConfig(ctx, req.Output)
+	output, err := s.resolveOutputConfig(ctx, req.Output)
 	if err != nil {
 		return nil, err
 	}
 
 	// 4. Apply Graph RAG tool context
-	tools, promptText, err := s.applyGraphTools(ctx, req, promptText)
+	tools, graphContext, err := s.applyGraphTools(ctx, req, instructions.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	promptText := instructions.Prompt
+	if graphContext != "" {
+		promptText = fmt.Sprintf("%s\n\n%s", promptText, graphContext)
+	}
+	tools, functions, maxToolRounds, err := s.resolveFunctionTools(tools)
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. Call the generator implementation
-	resp, err := s.generator.Generate(ctx, GeneratorRequest{
+	generatorReq := GeneratorRequest{
 		Prompt:            promptText,
-		SystemInstruction: systemInstr,
-		OutputSchema:      responseSchema,
+		SystemInstruction: instructions.SystemInstruction,
+		OutputSchema:      output.Schema,
 		Tools:             tools,
 		Model:             resolvedModel,
-	})
+		Fallbacks:         fallbackModels,
+		Functions:         functions,
+		History:           req.History,
+	}
+	inputHash, err := computeInputHash(generatorReq, req.Tools)
 	if err != nil {
-		modelName := modelNameForArtifact(resolvedModel)
+		return nil, err
+	}
+
+	return &preparedGeneration{
+		Request:       generatorReq,
+		Instructions:  instructions,
+		Output:        output,
+		GraphContext:  graphContext,
+		MaxToolRounds: maxToolRounds,
+		InputHash:     inputHash,
+	}, nil
+}
+
+// Preview resolves a request exactly as Generate would and returns what would be
+// sent to the model. The model is not called and no artifact is saved.
+func (s *Service) Preview(ctx context.Context, req GenerateRequest) (*PreviewResponse, error) {
+	prepared, err := s.prepare(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PreviewResponse{
+		Request:        prepared.Request,
+		PromptVersion:  prepared.Instructions.PromptVersion,
+		PromptPartials: prepared.Instructions.Partials,
+		Experiment:     prepared.Instructions.RunningExperiment,
+		SchemaVersion:  prepared.Output.SchemaVersion,
+		GraphContext:   prepared.GraphContext,
+		MaxToolRounds:  prepared.MaxToolRounds,
+		InputHash:      prepared.InputHash,
+	}
+	if prepared.Instructions.PromptTemplateID != uuid.Nil {
+		preview.PromptTemplateID = &prepared.Instructions.PromptTemplateID
+	}
+	if prepared.Output.SchemaTemplateID != uuid.Nil {
+		preview.SchemaTemplateID = &prepared.Output.SchemaTemplateID
+	}
+	return preview, nil
+}
+
+func (s *Service) generate(ctx context.Context, req GenerateRequest, onChunk ChunkHandler) (*GenerateResponse, error) {
+	if s.generator == nil {
+		return nil, ErrGeneratorUnavailable
+	}
+
+	prepared, err := s.prepare(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, req, prepared, onChunk)
+}
+
+// run calls the model for a prepared generation, validates the output and
+// saves the artifact.
+func (s *Service) run(ctx context.Context, req GenerateRequest, prepared *preparedGeneration, onChunk ChunkHandler) (*GenerateResponse, error) {
+	generatorReq := prepared.Request
+	resolvedModel := generatorReq.Model
+	instructions := prepared.Instructions
+	schemaTmplID := prepared.Output.SchemaTemplateID
+	promptText, systemInstr := generatorReq.Prompt, generatorReq.SystemInstruction
+	maxToolRounds, inputHash := prepared.MaxToolRounds, prepared.InputHash
+
+	// 5. Call the generator implementation
+	if req.ReuseCached {
+		source, err := s.reusableArtifact(ctx, inputHash)
+		if err != nil {
+			return nil, err
+		}
+		if source != nil {
+			if onChunk != nil && source.Text.String != "" {
+				if err := onChunk(source.Text.String); err != nil {
+					return nil, err
+				}
+			}
+			return s.copyCachedArtifact(ctx, req, prepared, source)
+		}
+	}
+
+	// Function calling runs a non-streaming loop; streamed requests receive the
+	// final answer as a single chunk once the loop has finished.
+	var (
+		resp      *GeneratorResponse
+		toolCalls []ToolCallRecord
+		err       error
+	)
+	switch {
+	case maxToolRounds > 0:
+		resp, toolCalls, err = s.generateWithTools(ctx, generatorReq, maxToolRounds, ToolCallContext{UserID: req.UserID, DocumentID: req.Target.DocumentID})
+		if err == nil && onChunk != nil && resp.OutputText != "" {
+			if chunkErr := onChunk(resp.OutputText); chunkErr != nil {
+				return nil, chunkErr
+			}
+		}
+	case onChunk != nil:
+		resp, err = s.generator.GenerateStream(ctx, generatorReq, onChunk)
+	default:
+		resp, err = s.generator.Generate(ctx, generatorReq)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		modelParams, meta, metaErr := buildArtifactMetadata(req, resolvedModel, systemInstr)
 		if metaErr != nil {
 			return nil, metaErr
 		}
-		s.saveArtifact(ctx, req, promptText, promptTmplID, schemaTmplID, modelName, modelParams, meta, "", nil, err.Error(), nil)
-		return nil, fmt.Errorf("genai call failed: %w", err)
+		if attempts := attemptsFromError(err); len(attempts) > 0 {
+			meta = mergeMeta(meta, map[string]any{"attempts": attempts})
+		}
+		if len(toolCalls) > 0 {
+			meta = mergeMeta(meta, map[string]any{"tool_calls": toolCalls})
+		}
+		meta = mergePreparedMeta(meta, prepared)
+		s.saveArtifact(ctx, req, artifactRecord{
+			InputHash:        inputHash,
+			PromptText:       promptText,
+			PromptTemplateID: instructions.PromptTemplateID,
+			SchemaTemplateID: schemaTmplID,
+			ModelName:        modelNameForArtifact(resolvedModel),
+			ModelParams:      modelParams,
+			Meta:             meta,
+			Error:            err.Error(),
+			Lineage:          prepared.Lineage,
+		})
+		return nil, fmt.Errorf("%w: genai call failed: %w", ErrGenerationFailed, err)
 	}
 
-	// 5. Extract Output
+	// 6. Validate structured output, re-prompting with the errors when repair is enabled
+	wantsJSON := req.Output.Format == "json" || req.Output.GenerationType != "" || req.Output.InlineSchema != nil
+	var validationErrors []ValidationError
+	repairAttempts := 0
+	if wantsJSON {
+		var check outputCheck
+		if isMultipleChoice(req) {
+			sources := s.distractorSources(ctx, req, prepared.GraphContext, resp.GroundingMetadata)
+			check = func(outputText string) []ValidationError {
+				return validateMultipleChoice(outputText, sources)
+			}
+		}
+		resp, validationErrors, repairAttempts, err = s.validateAndRepairWith(ctx, generatorReq, resp, req.Output.RepairAttempts, check)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 7. Extract Output
 	outputText := resp.OutputText
 
 	var outputJSON json.RawMessage
-	if req.Output.Format == "json" || req.Output.GenerationType != "" || req.Output.InlineSchema != nil {
+	if wantsJSON && json.Valid([]byte(outputText)) {
 		outputJSON = json.RawMessage(outputText)
 	}
 
-	// 6. Save Artifact
+	// 8. Save Artifact
 	modelName := modelNameForArtifact(resolvedModel)
 	if resp.ModelUsed != "" {
 		modelName = resp.ModelUsed
 	}
 
-	modelParams, meta, metaErr := buildArtifactMetadata(req, resolvedModel, systemInstr)
+	usedModel := modelUsedConfig(generatorReq, resp)
+	modelParams, meta, metaErr := buildArtifactMetadata(req, usedModel, systemInstr)
 	if metaErr != nil {
 		return nil, metaErr
 	}
+	var cost *float64
+	if usedModel != nil {
+		cost = usedModel.Pricing.Cost(resp.Usage)
+	}
+	if len(resp.Attempts) > 0 {
+		meta = mergeMeta(meta, map[string]any{"attempts": resp.Attempts})
+	}
+	if len(resp.Candidates) > 1 {
+		meta = mergeMeta(meta, map[string]any{"candidates": resp.Candidates})
+	}
+	if len(toolCalls) > 0 {
+		meta = mergeMeta(meta, map[string]any{"tool_calls": toolCalls})
+	}
+	meta = mergePreparedMeta(meta, prepared)
+
+	status := ArtifactStatusReady
+	errorMsg := ""
+	if len(validationErrors) > 0 {
+		status = ArtifactStatusInvalid
+		errorMsg = "output failed schema validation:\n" + formatValidationErrors(validationErrors)
+		meta = mergeMeta(meta, map[string]any{"validation_errors": validationErrors})
+	}
+	if repairAttempts > 0 {
+		meta = mergeMeta(meta, map[string]any{"repair_attempts": repairAttempts})
+	}
 
-	artifactID, saveErr := s.saveArtifact(ctx, req, promptText, promptTmplID, schemaTmplID, modelName, modelParams, meta, outputText, outputJSON, "", resp.GroundingMetadata)
+	artifactID, saveErr := s.saveArtifact(ctx, req, artifactRecord{
+		Status:            status,
+		InputHash:         inputHash,
+		PromptText:        promptText,
+		PromptTemplateID:  instructions.PromptTemplateID,
+		SchemaTemplateID:  schemaTmplID,
+		ModelName:         modelName,
+		ModelParams:       modelParams,
+		Meta:              meta,
+		OutputText:        outputText,
+		OutputJSON:        outputJSON,
+		Error:             errorMsg,
+		GroundingMetadata: resp.GroundingMetadata,
+		Usage:             resp.Usage,
+		CostUSD:           cost,
+		Lineage:           prepared.Lineage,
+	})
 	if saveErr != nil {
 		return nil, fmt.Errorf("failed to save artifact: %w", saveErr)
 	}
 
 	return &GenerateResponse{
 		ArtifactID:        artifactID,
+		Status:            status,
 		OutputText:        outputText,
 		OutputJSON:        outputJSON,
 		FinishReason:      resp.FinishReason,
 		ModelUsed:         modelName,
 		GroundingMetadata: resp.GroundingMetadata,
+		InputHash:         inputHash,
+		ValidationErrors:  validationErrors,
+		Usage:             resp.Usage,
+		CostUSD:           cost,
+	}, nil
+}
+
+// outputCheck reports problems the response schema cannot express. It only runs
+// on output that already satisfies the schema.
+type outputCheck func(outputText string) []ValidationError
+
+// validateAndRepairWith validates resp against the request schema and, when set,
+// check. When it fails and maxRepairs > 0 the model is re-prompted with the errors
+// until the output passes or the attempts run out. It returns the last response
+// with its remaining errors.
+func (s *Service) validateAndRepairWith(ctx context.Context, genReq GeneratorRequest, resp *GeneratorResponse, maxRepairs int, check outputCheck) (*GeneratorResponse, []ValidationError, int, error) {
+	validate := func(outputText string) ([]ValidationError, error) {
+		validationErrors, err := validateGeneratorOutput(genReq.OutputSchema, outputText)
+		if err != nil || len(validationErrors) > 0 || check == nil {
+			return validationErrors, err
+		}
+		return check(outputText), nil
+	}
+
+	// With several candidates the first one that passes becomes the output.
+	validateCandidates := func(resp *GeneratorResponse) ([]ValidationError, error) {
+		validationErrors, err := validate(resp.OutputText)
+		if err != nil || len(validationErrors) == 0 {
+			return validationErrors, err
+		}
+		for _, candidate := range resp.Candidates {
+			if candidate == resp.OutputText {
+				continue
+			}
+			candidateErrors, err := validate(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if len(candidateErrors) == 0 {
+				resp.OutputText = candidate
+				return nil, nil
+			}
+		}
+		return validationErrors, nil
+	}
+
+	validationErrors, err := validateCandidates(resp)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	attempts := 0
+	for len(validationErrors) > 0 && attempts < maxRepairs {
+		attempts++
+		repairReq := genReq
+		repairReq.Prompt = buildRepairPrompt(genReq.Prompt, resp.OutputText, validationErrors)
+		// Repairs only reformat the previous output; tool results are already in it.
+		repairReq.Functions = nil
+
+		repaired, err := s.generator.Generate(ctx, repairReq)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, nil, attempts, ctxErr
+			}
+			// Keep the last output and its errors; the artifact is saved as INVALID.
+			break
+		}
+		repaired.Attempts = append(resp.Attempts, repaired.Attempts...)
+		repaired.Usage = resp.Usage.Add(repaired.Usage)
+		resp = repaired
+
+		validationErrors, err = validateCandidates(resp)
+		if err != nil {
+			return nil, nil, attempts, err
+		}
+	}
+
+	return resp, validationErrors, attempts, nil
+}
+
+func validateGeneratorOutput(schema json.RawMessage, outputText string) ([]ValidationError, error) {
+	if len(schema) == 0 {
+		if json.Valid([]byte(outputText)) {
+			return nil, nil
+		}
+		return []ValidationError{{Path: "$", Message: "output is not valid JSON"}}, nil
+	}
+	return ValidateOutput(schema, []byte(outputText))
+}
+
+func buildRepairPrompt(prompt, previousOutput string, errs []ValidationError) string {
+	return fmt.Sprintf("%s\n\n[Previous Output]\n%s\n\n[Validation Errors]\n%s\n\nReturn corrected JSON that satisfies the response schema. Respond with JSON only.",
+		prompt, truncate(previousOutput, 8000), formatValidationErrors(errs))
+}
+
+// reusableArtifact returns the artifact whose output a generation with
+// inputHash can reuse, or nil when there is none.
+func (s *Service) reusableArtifact(ctx context.Context, inputHash string) (*store.Artifact, error) {
+	artifact, err := s.artifactsService.GetLatestReusableArtifactByInputHash(ctx, inputHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// copyCachedArtifact saves the output of source as a new artifact for the
+// requester and target of req, so that a cache hit never hands out another
+// user's artifact. The model is not called, so the copy has no usage or cost;
+// meta.cached_from points at source.
+func (s *Service) copyCachedArtifact(ctx context.Context, req GenerateRequest, prepared *preparedGeneration, source *store.Artifact) (*GenerateResponse, error) {
+	modelParams, meta, err := buildArtifactMetadata(req, prepared.Request.Model, prepared.Request.SystemInstruction)
+	if err != nil {
+		return nil, err
+	}
+	meta = mergePreparedMeta(meta, prepared)
+	meta = mergeMeta(meta, map[string]any{"cached_from": source.ID})
+
+	var groundingMetadata json.RawMessage
+	if source.Meta.Valid {
+		var sourceMeta map[string]json.RawMessage
+		if err := json.Unmarshal(source.Meta.RawMessage, &sourceMeta); err == nil {
+			groundingMetadata = sourceMeta["grounding"]
+		}
+	}
+
+	var outputJSON json.RawMessage
+	if source.OutputJson.Valid {
+		outputJSON = source.OutputJson.RawMessage
+	}
+
+	modelName := source.Model.String
+	if modelName == "" {
+		modelName = modelNameForArtifact(prepared.Request.Model)
+	}
+
+	artifactID, err := s.saveArtifact(ctx, req, artifactRecord{
+		Status:            ArtifactStatusReady,
+		InputHash:         prepared.InputHash,
+		PromptText:        prepared.Request.Prompt,
+		PromptTemplateID:  prepared.Instructions.PromptTemplateID,
+		SchemaTemplateID:  prepared.Output.SchemaTemplateID,
+		ModelName:         modelName,
+		ModelParams:       modelParams,
+		Meta:              meta,
+		OutputText:        source.Text.String,
+		OutputJSON:        outputJSON,
+		GroundingMetadata: groundingMetadata,
+		Lineage:           prepared.Lineage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save cached artifact: %w", err)
+	}
+
+	return &GenerateResponse{
+		ArtifactID:        artifactID,
+		Status:            ArtifactStatusReady,
+		OutputText:        source.Text.String,
+		OutputJSON:        outputJSON,
+		ModelUsed:         modelName,
+		GroundingMetadata: groundingMetadata,
+		InputHash:         prepared.InputHash,
+		Cached:            true,
 	}, nil
 }
 
+// GetGeneration returns the artifact saved for a generation. Inline generations
+// have no generation type, so an artifact with a requesting user counts too.
+func (s *Service) GetGeneration(ctx context.Context, artifactID uuid.UUID) (*store.Artifact, error) {
+	artifact, err := s.artifactsService.GetArtifactByID(ctx, artifactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGenerationNotFound
+		}
+		return nil, err
+	}
+	if !artifact.GenerationType.Valid && !artifact.UserID.Valid {
+		return nil, ErrGenerationNotFound
+	}
+	return artifact, nil
+}
+
+// ListGenerations returns generation artifacts, optionally limited to a single requesting user.
+func (s *Service) ListGenerations(ctx context.Context, userID *uuid.UUID, limit, offset int32) ([]store.Artifact, int64, error) {
+	if userID != nil {
+		return s.artifactsService.ListGenerationArtifactsByUser(ctx, *userID, limit, offset)
+	}
+	return s.artifactsService.ListGenerationArtifacts(ctx, limit, offset)
+}
+
 func (s *Service) resolveModelConfig(ctx context.Context, id uuid.UUID) (*ModelConfig, error) {
 	var dbConfig *model_configs.ModelConfig
 	var err error
@@ -139,6 +601,9 @@ func (s *Service) resolveModelConfig(ctx context.Context, id uuid.UUID) (*ModelC
 		}
 	} else {
 		dbConfig, err = s.modelConfigs.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: model config %q not found", ErrInvalidRequest, id)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch model config %q: %w", id, err)
 		}
@@ -158,6 +623,23 @@ func (s *Service) resolveModelConfig(ctx context.Context, id uuid.UUID) (*ModelC
 	baseConfig.TopP = &topP
 	baseConfig.TopK = &topK
 	baseConfig.MimeType = dbConfig.MimeType
+	baseConfig.Provider = dbConfig.Provider
+	baseConfig.BaseURL = dbConfig.BaseURL
+	baseConfig.RequestsPerMinute = dbConfig.RequestsPerMinute
+	baseConfig.Seed = dbConfig.Seed
+	baseConfig.StopSequences = dbConfig.StopSequences
+	baseConfig.CandidateCount = dbConfig.CandidateCount
+	baseConfig.PresencePenalty = float32Ptr(dbConfig.PresencePenalty)
+	baseConfig.FrequencyPenalty = float32Ptr(dbConfig.FrequencyPenalty)
+	baseConfig.ThinkingBudget = dbConfig.ThinkingBudget
+	baseConfig.SafetySettings = dbConfig.SafetySettings
+	if dbConfig.InputPricePerMillion != nil || dbConfig.OutputPricePerMillion != nil {
+		baseConfig.Pricing = &ModelPricing{
+			InputPerMillion:       derefFloat64(dbConfig.InputPricePerMillion),
+			OutputPerMillion:      derefFloat64(dbConfig.OutputPricePerMillion),
+			CachedInputPerMillion: dbConfig.CachedInputPricePerMillion,
+		}
+	}
 
 	if baseConfig.Name == "" {
 		return nil, fmt.Errorf("resolved model config is incomplete: missing name")
@@ -166,25 +648,40 @@ func (s *Service) resolveModelConfig(ctx context.Context, id uuid.UUID) (*ModelC
 	return baseConfig, nil
 }
 
-func (s *Service) resolveInstructions(ctx context.Context, inst Instructions) (string, string, uuid.UUID, error) {
+// resolvedInstructions is a rendered prompt together with the template rows
+// used to build it.
+type resolvedInstructions struct {
+	Prompt            string
+	SystemInstruction string
+	PromptTemplateID  uuid.UUID
+	PromptVersion     int32
+	Partials          []prompt_templates.PartialVersion
+	Experiment        *prompt_templates.ExperimentAssignment
+	// RunningExperiment is set instead of Experiment when a preview skipped the
+	// variant pick and rendered the active template.
+	RunningExperiment *prompt_templates.PromptExperiment
+}
+
+func (s *Service) resolveInstructions(ctx context.Context, inst Instructions, preview bool) (*resolvedInstructions, error) {
 	// 1. Resolve System Instruction
-	var systemInstr string
+	resolved := &resolvedInstructions{}
 	if inst.SystemInstructionID != nil {
 		var sys *system_instructions.SystemInstruction
 		var err error
 		sys, err = s.systemInstructions.GetByID(ctx, *inst.SystemInstructionID)
 
 		if err != nil {
-			return "", "", uuid.Nil, fmt.Errorf("failed to fetch system instruction %q: %w", *inst.SystemInstructionID, err)
+			return nil, fmt.Errorf("failed to fetch system instruction %q: %w", *inst.SystemInstructionID, err)
 		}
-		systemInstr = sys.Text
+		resolved.SystemInstruction = sys.Text
 	}
 
 	if inst.Inline != "" {
-		return inst.Inline, systemInstr, uuid.Nil, nil
+		resolved.Prompt = inst.Inline
+		return resolved, nil
 	}
 	if inst.GenerationType == "" {
-		return "", "", uuid.Nil, fmt.Errorf("generation type is required")
+		return nil, fmt.Errorf("%w: generation type is required", ErrInvalidRequest)
 	}
 
 	var promptTmpl *prompt_templates.PromptTemplate
@@ -192,61 +689,161 @@ func (s *Service) resolveInstructions(ctx context.Context, inst Instructions) (s
 	if inst.PromptVersion > 0 {
 		promptTmpl, err = s.promptTemplates.GetByGenerationTypeAndVersion(ctx, inst.GenerationType, inst.PromptVersion)
 	} else {
-		promptTmpl, err = s.promptTemplates.GetActiveByGenerationType(ctx, inst.GenerationType)
+		var experiment *prompt_templates.PromptExperiment
+		experiment, err = s.runningExperiment(ctx, inst.GenerationType)
+		if err == nil && experiment != nil {
+			if preview {
+				resolved.RunningExperiment = experiment
+			} else {
+				promptTmpl, resolved.Experiment, err = s.experimentTemplate(ctx, experiment)
+			}
+		}
+		if err == nil && promptTmpl == nil {
+			promptTmpl, err = s.promptTemplates.GetActiveByGenerationType(ctx, inst.GenerationType)
+		}
+	}
+
+	if err != nil {
+		if inst.PromptVersion > 0 && errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: prompt template %q version %d not found", ErrInvalidRequest, inst.GenerationType, inst.PromptVersion)
+		}
+		return nil, fmt.Errorf("failed to fetch prompt template %q: %w", inst.GenerationType, err)
 	}
+	resolved.PromptTemplateID = promptTmpl.ID
+	resolved.PromptVersion = promptTmpl.Version
 
+	variables, err := prompt_templates.ResolveVariables(promptTmpl.Variables, inst.Variables)
 	if err != nil {
-		return "", "", uuid.Nil, fmt.Errorf("failed to fetch prompt template %q: %w", inst.GenerationType, err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 
-	tmpl, err := template.New("prompt").Parse(promptTmpl.Template)
+	// missingkey=error turns references to variables nobody supplied into a
+	// request error instead of rendering "<no value>" into the prompt.
+	tmpl, partials, err := prompt_templates.Compose("prompt", promptTmpl.Template, func(name string) (*prompt_templates.PromptPartial, error) {
+		return s.loadPromptPartial(ctx, name, inst.PartialVersions[name])
+	})
 	if err != nil {
-		return "", "", promptTmpl.ID, fmt.Errorf("failed to parse prompt template: %w", err)
+		if errors.Is(err, prompt_templates.ErrInvalidPartial) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+		return nil, err
 	}
+	resolved.Partials = partials
 
 	var renderedPrompt bytes.Buffer
-	if err := tmpl.Execute(&renderedPrompt, inst.Variables); err != nil {
-		return "", "", promptTmpl.ID, fmt.Errorf("failed to render prompt: %w", err)
+	if err := tmpl.Execute(&renderedPrompt, variables); err != nil {
+		return nil, fmt.Errorf("%w: failed to render prompt: %w", ErrInvalidRequest, err)
 	}
+	resolved.Prompt = renderedPrompt.String()
 
-	return renderedPrompt.String(), systemInstr, promptTmpl.ID, nil
+	return resolved, nil
 }
 
-func (s *Service) resolveOutputConfig(ctx context.Context, out OutputConfig) (json.RawMessage, uuid.UUID, error) {
+// runningExperiment returns the experiment running for generationType, or nil
+// when there is none or it has no weighted variants.
+func (s *Service) runningExperiment(ctx context.Context, generationType string) (*prompt_templates.PromptExperiment, error) {
+	experiment, err := s.promptTemplates.GetRunningExperiment(ctx, generationType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch prompt experiment %q: %w", generationType, err)
+	}
+	if experiment.TotalWeight() <= 0 {
+		return nil, nil
+	}
+	return experiment, nil
+}
+
+// experimentTemplate picks a variant of experiment at random in proportion to
+// the variant weights.
+func (s *Service) experimentTemplate(ctx context.Context, experiment *prompt_templates.PromptExperiment) (*prompt_templates.PromptTemplate, *prompt_templates.ExperimentAssignment, error) {
+	variant := experiment.PickVariant(rand.IntN(experiment.TotalWeight()))
+	promptTmpl, err := s.promptTemplates.GetByID(ctx, variant.PromptTemplateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch prompt template for experiment %s: %w", experiment.ID, err)
+	}
+	return promptTmpl, experiment.Assignment(variant), nil
+}
+
+// loadPromptPartial fetches a partial by name, pinned to version when it is
+// positive and the active version otherwise.
+func (s *Service) loadPromptPartial(ctx context.Context, name string, version int32) (*prompt_templates.PromptPartial, error) {
+	var partial *prompt_templates.PromptPartial
+	var err error
+	if version > 0 {
+		partial, err = s.promptTemplates.GetPartialByNameAndVersion(ctx, name, version)
+	} else {
+		partial, err = s.promptTemplates.GetActivePartialByName(ctx, name)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if version > 0 {
+				return nil, fmt.Errorf("%w: prompt partial %q version %d not found", ErrInvalidRequest, name, version)
+			}
+			return nil, fmt.Errorf("%w: prompt partial %q not found", ErrInvalidRequest, name)
+		}
+		return nil, fmt.Errorf("failed to fetch prompt partial %q: %w", name, err)
+	}
+	return partial, nil
+}
+
+// resolvedOutput is the response schema together with the template row it came from.
+type resolvedOutput struct {
+	Schema           json.RawMessage
+	SchemaTemplateID uuid.UUID
+	SchemaVersion    int32
+}
+
+func (s *Service) resolveOutputConfig(ctx context.Context, out OutputConfig) (*resolvedOutput, error) {
 	if out.InlineSchema != nil {
 		var parsed interface{}
 		if err := json.Unmarshal(out.InlineSchema, &parsed); err != nil {
-			return nil, uuid.Nil, fmt.Errorf("failed to parse inline schema: %w", err)
+			return nil, fmt.Errorf("failed to parse inline schema: %w", err)
 		}
-		return out.InlineSchema, uuid.Nil, nil
+		return &resolvedOutput{Schema: out.InlineSchema}, nil
 	}
 
 	if out.GenerationType == "" {
-		return nil, uuid.Nil, nil
+		return &resolvedOutput{}, nil
 	}
 
 	var schemaTmpl *schema_templates.SchemaTemplate
 	var err error
 	if out.SchemaVersion > 0 {
-		return nil, uuid.Nil, fmt.Errorf("schema_version is not supported; use the active schema")
+		schemaTmpl, err = s.schemaTemplates.GetByGenerationTypeAndVersion(ctx, out.GenerationType, out.SchemaVersion)
+	} else {
+		schemaTmpl, err = s.schemaTemplates.GetActiveByGenerationType(ctx, out.GenerationType)
 	}
-	schemaTmpl, err = s.schemaTemplates.GetActiveByGenerationType(ctx, out.GenerationType)
 
 	if err != nil {
-		return nil, uuid.Nil, fmt.Errorf("failed to fetch schema template %q: %w", out.GenerationType, err)
+		if out.SchemaVersion > 0 && errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: schema template %q version %d not found", ErrInvalidRequest, out.GenerationType, out.SchemaVersion)
+		}
+		return nil, fmt.Errorf("failed to fetch schema template %q: %w", out.GenerationType, err)
 	}
 
-	return schemaTmpl.SchemaJSON, schemaTmpl.ID, nil
+	return &resolvedOutput{
+		Schema:           schemaTmpl.SchemaJSON,
+		SchemaTemplateID: schemaTmpl.ID,
+		SchemaVersion:    schemaTmpl.Version,
+	}, nil
 }
 
+// graphToolConfig configures a graph_rag tool. The embedded section filter
+// restricts or ranks matches by the SECTION_TOPICS sections containing them.
 type graphToolConfig struct {
 	Query string `json:"query"`
 	Limit int    `json:"limit,omitempty"`
+	document_graph.SectionFilter
 }
 
+// applyGraphTools removes graph_rag tools from the request and returns the
+// "[Graph Context]" block to append to the prompt, or "" when there is none.
 func (s *Service) applyGraphTools(ctx context.Context, req GenerateRequest, prompt string) ([]ToolConfig, string, error) {
 	if len(req.Tools) == 0 {
-		return req.Tools, prompt, nil
+		return req.Tools, "", nil
 	}
 
 	filtered := make([]ToolConfig, 0, len(req.Tools))
@@ -285,7 +882,16 @@ func (s *Service) applyGraphTools(ctx context.Context, req GenerateRequest, prom
 			limit = 8
 		}
 
-		matched, err := s.graphRepo.SearchNodes(ctx, *req.Target.DocumentID, query, limit)
+		var matched []document_graph.Node
+		var err error
+		if cfg.SectionFilter.IsZero() {
+			matched, err = s.graphRepo.SearchNodes(ctx, *req.Target.DocumentID, query, limit)
+		} else {
+			if err := cfg.SectionFilter.Validate(); err != nil {
+				return nil, "", fmt.Errorf("%w: graph_rag: %v", ErrInvalidRequest, err)
+			}
+			matched, err = s.graphRepo.SearchNodesInSections(ctx, *req.Target.DocumentID, query, cfg.SectionFilter, limit)
+		}
 		if err != nil {
 			return nil, "", err
 		}
@@ -312,11 +918,10 @@ func (s *Service) applyGraphTools(ctx context.Context, req GenerateRequest, prom
 	}
 
 	if graphContext == "" {
-		return filtered, prompt, nil
+		return filtered, "", nil
 	}
 
-	augmented := fmt.Sprintf("%s\n\n[Graph Context]\n%s", prompt, graphContext)
-	return filtered, augmented, nil
+	return filtered, "[Graph Context]\n" + graphContext, nil
 }
 
 func buildGraphContext(nodes map[uuid.UUID]document_graph.Node, edges []document_graph.Edge) string {
@@ -327,6 +932,9 @@ func buildGraphContext(nodes map[uuid.UUID]document_graph.Node, edges []document
 	var builder strings.Builder
 	for _, node := range nodes {
 		label := truncate(node.Text, 240)
+		if node.NodeType == document_graph.NodeTypeSection {
+			label = sectionLabel(node)
+		}
 		if label == "" {
 			label = node.NodeType
 		}
@@ -350,6 +958,26 @@ func buildGraphContext(nodes map[uuid.UUID]document_graph.Node, edges []document
 	return strings.TrimSpace(builder.String())
 }
 
+// sectionLabel describes a section node by its title, topic, difficulty and
+// summary so the model sees where a passage sits in the document.
+func sectionLabel(node document_graph.Node) string {
+	var meta document_graph.SectionMetadata
+	if err := json.Unmarshal(node.Metadata, &meta); err != nil {
+		return truncate(node.Text, 240)
+	}
+	label := truncate(node.Text, 120)
+	if meta.Topic != "" {
+		label += fmt.Sprintf(" [topic: %s]", truncate(meta.Topic, 80))
+	}
+	if meta.Difficulty > 0 {
+		label += fmt.Sprintf(" [difficulty %d/5]", meta.Difficulty)
+	}
+	if meta.Summary != "" {
+		label += " - " + truncate(meta.Summary, 240)
+	}
+	return label
+}
+
 func truncate(text string, max int) string {
 	value := strings.TrimSpace(text)
 	if max <= 0 || len(value) <= max {
@@ -361,10 +989,32 @@ func truncate(text string, max int) string {
 	return value[:max-3] + "..."
 }
 
-func (s *Service) saveArtifact(ctx context.Context, req GenerateRequest, promptText string, promptTmplID, schemaTmplID uuid.UUID, modelName string, modelParams json.RawMessage, meta json.RawMessage, outputText string, outputJSON json.RawMessage, errorMsg string, groundingMetadata json.RawMessage) (uuid.UUID, error) {
-	status := "READY"
-	if errorMsg != "" {
-		status = "ERROR"
+// artifactRecord carries everything generate knows about a run when persisting it.
+type artifactRecord struct {
+	Status            string
+	InputHash         string
+	PromptText        string
+	PromptTemplateID  uuid.UUID
+	SchemaTemplateID  uuid.UUID
+	ModelName         string
+	ModelParams       json.RawMessage
+	Meta              json.RawMessage
+	OutputText        string
+	OutputJSON        json.RawMessage
+	Error             string
+	GroundingMetadata json.RawMessage
+	Usage             *TokenUsage
+	CostUSD           *float64
+	Lineage           *lineageRecord
+}
+
+func (s *Service) saveArtifact(ctx context.Context, req GenerateRequest, record artifactRecord) (uuid.UUID, error) {
+	status := record.Status
+	if status == "" {
+		status = ArtifactStatusReady
+		if record.Error != "" {
+			status = ArtifactStatusError
+		}
 	}
 
 	generationType := req.Instructions.GenerationType
@@ -373,23 +1023,24 @@ func (s *Service) saveArtifact(ctx context.Context, req GenerateRequest, promptT
 	}
 
 	// Merge grounding metadata into meta if available
-	if len(groundingMetadata) > 0 && len(meta) > 0 {
+	meta := record.Meta
+	if len(record.GroundingMetadata) > 0 && len(meta) > 0 {
 		var metaObj map[string]interface{}
 		var groundingObj map[string]interface{}
 		if err := json.Unmarshal(meta, &metaObj); err == nil {
-			if err := json.Unmarshal(groundingMetadata, &groundingObj); err == nil {
+			if err := json.Unmarshal(record.GroundingMetadata, &groundingObj); err == nil {
 				metaObj["grounding"] = groundingObj
 				if merged, err := json.Marshal(metaObj); err == nil {
 					meta = merged
 				}
 			}
 		}
-	} else if len(groundingMetadata) > 0 {
-		meta = groundingMetadata
+	} else if len(record.GroundingMetadata) > 0 {
+		meta = record.GroundingMetadata
 	}
 
 	params := artifacts.CreateArtifactParams{
-		Type:             "OTHER",
+		Type:             string(utils.GenerationType(generationType).ArtifactType()),
 		GenerationType:   generationType,
 		Status:           status,
 		UserID:           req.UserID,
@@ -397,16 +1048,29 @@ func (s *Service) saveArtifact(ctx context.Context, req GenerateRequest, promptT
 		EvalID:           uuid.NullUUID{UUID: ptrToUUID(req.Target.EvalID), Valid: req.Target.EvalID != nil},
 		EvalItemID:       uuid.NullUUID{UUID: ptrToUUID(req.Target.EvalItemID), Valid: req.Target.EvalItemID != nil},
 		AttemptID:        uuid.NullUUID{UUID: ptrToUUID(req.Target.AttemptID), Valid: req.Target.AttemptID != nil},
-		Text:             outputText,
-		OutputJSON:       outputJSON,
-		Model:            modelName,
-		Prompt:           promptText,
-		PromptRender:     promptText,
-		PromptTemplateID: uuid.NullUUID{UUID: promptTmplID, Valid: promptTmplID != uuid.Nil},
-		SchemaTemplateID: uuid.NullUUID{UUID: schemaTmplID, Valid: schemaTmplID != uuid.Nil},
-		ModelParams:      modelParams,
+		ThreadID:         uuid.NullUUID{UUID: ptrToUUID(req.Target.ThreadID), Valid: req.Target.ThreadID != nil},
+		Text:             record.OutputText,
+		OutputJSON:       record.OutputJSON,
+		Model:            record.ModelName,
+		Prompt:           record.PromptText,
+		InputHash:        record.InputHash,
+		PromptRender:     record.PromptText,
+		PromptTemplateID: uuid.NullUUID{UUID: record.PromptTemplateID, Valid: record.PromptTemplateID != uuid.Nil},
+		SchemaTemplateID: uuid.NullUUID{UUID: record.SchemaTemplateID, Valid: record.SchemaTemplateID != uuid.Nil},
+		ModelParams:      record.ModelParams,
 		Meta:             meta,
-		Error:            errorMsg,
+		Error:            record.Error,
+		CostUSD:          record.CostUSD,
+	}
+	if record.Lineage != nil {
+		params.ParentArtifactID = uuid.NullUUID{UUID: record.Lineage.ParentArtifactID, Valid: true}
+		params.Derivation = record.Lineage.Derivation
+	}
+	if record.Usage != nil {
+		params.PromptTokens = &record.Usage.PromptTokens
+		params.CandidateTokens = &record.Usage.CandidateTokens
+		params.CachedTokens = &record.Usage.CachedTokens
+		params.TotalTokens = &record.Usage.TotalTokens
 	}
 
 	art, err := s.artifactsService.CreateArtifact(ctx, params)
@@ -416,6 +1080,39 @@ func (s *Service) saveArtifact(ctx context.Context, req GenerateRequest, promptT
 	return art.ID, nil
 }
 
+// mergePreparedMeta adds the prompt partials, experiment assignment and lineage
+// of prepared to meta.
+func mergePreparedMeta(meta json.RawMessage, prepared *preparedGeneration) json.RawMessage {
+	if len(prepared.Instructions.Partials) > 0 {
+		meta = mergeMeta(meta, map[string]any{"prompt_partials": prepared.Instructions.Partials})
+	}
+	if prepared.Instructions.Experiment != nil {
+		meta = mergeMeta(meta, map[string]any{"experiment": prepared.Instructions.Experiment})
+	}
+	if prepared.Lineage != nil {
+		meta = mergeMeta(meta, map[string]any{"lineage": prepared.Lineage})
+	}
+	return meta
+}
+
+// mergeMeta adds fields to a JSON object, starting a new object when meta is empty.
+func mergeMeta(meta json.RawMessage, fields map[string]any) json.RawMessage {
+	metaObj := map[string]any{}
+	if len(meta) > 0 {
+		if err := json.Unmarshal(meta, &metaObj); err != nil {
+			return meta
+		}
+	}
+	for key, value := range fields {
+		metaObj[key] = value
+	}
+	merged, err := json.Marshal(metaObj)
+	if err != nil {
+		return meta
+	}
+	return merged
+}
+
 func ptrToUUID(p *uuid.UUID) uuid.UUID {
 	if p == nil {
 		return uuid.Nil
@@ -423,6 +1120,14 @@ func ptrToUUID(p *uuid.UUID) uuid.UUID {
 	return *p
 }
 
+// modelUsedConfig returns the config of the model that produced the output, which may be a fallback.
+func modelUsedConfig(req GeneratorRequest, resp *GeneratorResponse) *ModelConfig {
+	if resp.Model != nil {
+		return resp.Model
+	}
+	return req.Model
+}
+
 func modelNameForArtifact(config *ModelConfig) string {
 	if config != nil && config.Name != "" {
 		return config.Name
@@ -430,6 +1135,21 @@ func modelNameForArtifact(config *ModelConfig) string {
 	return ""
 }
 
+func derefFloat64(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func float32Ptr(v *float64) *float32 {
+	if v == nil {
+		return nil
+	}
+	converted := float32(*v)
+	return &converted
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -438,6 +1158,7 @@ type artifactMeta struct {
 	SystemInstructionID   *uuid.UUID `json:"system_instruction_id,omitempty"`
 	SystemInstructionText string     `json:"system_instruction_text,omitempty"`
 	ModelConfigID         uuid.UUID  `json:"model_config_id,omitempty"`
+	DocumentID            *uuid.UUID `json:"document_id,omitempty"` // meta.document_id is the lookup key for usage reports and export filters
 }
 
 func buildArtifactMetadata(req GenerateRequest, model *ModelConfig, systemInstruction string) (json.RawMessage, json.RawMessage, error) {
@@ -454,9 +1175,10 @@ func buildArtifactMetadata(req GenerateRequest, model *ModelConfig, systemInstru
 		SystemInstructionID:   req.Instructions.SystemInstructionID,
 		SystemInstructionText: systemInstruction,
 		ModelConfigID:         req.ModelConfigID,
+		DocumentID:            req.Target.DocumentID,
 	}
 
-	if meta.SystemInstructionID == nil && meta.SystemInstructionText == "" && meta.ModelConfigID == uuid.Nil {
+	if meta.SystemInstructionID == nil && meta.SystemInstructionText == "" && meta.ModelConfigID == uuid.Nil && meta.DocumentID == nil {
 		return modelParams, nil, nil
 	}
 
@@ -0,0 +1,137 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/model_configs"
+)
+
+func TestSyntheticGenerator_GeminiSchemaIsDeterministic(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/questions_schema.json")
+	require.NoError(t, err)
+
+	generator := NewSyntheticGenerator()
+	req := GeneratorRequest{
+		Prompt:       "Generate 3 questions",
+		OutputSchema: schema,
+		Model:        &ModelConfig{Name: "synthetic:7"},
+	}
+
+	first, err := generator.Generate(context.Background(), req)
+	require.NoError(t, err)
+	second, err := generator.Generate(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.OutputText, second.OutputText)
+	assert.Equal(t, "synthetic:7", first.ModelUsed)
+
+	var payload struct {
+		Questions []struct {
+			ID             string `json:"id"`
+			Question       string `json:"question"`
+			ExpectedAnswer string `json:"expected_answer"`
+		} `json:"questions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(first.OutputText), &payload))
+	require.NotEmpty(t, payload.Questions)
+	assert.NotEmpty(t, payload.Questions[0].Question)
+}
+
+func TestSyntheticGenerator_RecursiveRefTerminates(t *testing.T) {
+	schema, err := os.ReadFile("../../persistance/seeds/taxonomy_schema.json")
+	require.NoError(t, err)
+
+	resp, err := NewSyntheticGenerator().Generate(context.Background(), GeneratorRequest{
+		OutputSchema: schema,
+		Model:        &ModelConfig{Name: "synthetic:"},
+	})
+	require.NoError(t, err)
+	assert.True(t, json.Valid([]byte(resp.OutputText)))
+}
+
+func TestSyntheticGenerator_TextOutput(t *testing.T) {
+	resp, err := NewSyntheticGenerator().Generate(context.Background(), GeneratorRequest{
+		Prompt: "Say something",
+		Model:  &ModelConfig{Name: "synthetic:1"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.OutputText)
+	assert.False(t, json.Valid([]byte(resp.OutputText)))
+}
+
+func TestSyntheticGenerator_SamplingParams(t *testing.T) {
+	generator := NewSyntheticGenerator()
+	req := GeneratorRequest{Prompt: "Say something", Model: &ModelConfig{Name: "synthetic:"}}
+	plain, err := generator.Generate(context.Background(), req)
+	require.NoError(t, err)
+
+	seed := int32(99)
+	seeded, err := generator.Generate(context.Background(), GeneratorRequest{Prompt: req.Prompt, Model: &ModelConfig{Name: "synthetic:", Seed: &seed}})
+	require.NoError(t, err)
+	assert.NotEqual(t, plain.OutputText, seeded.OutputText)
+
+	words := strings.Fields(plain.OutputText)
+	require.Greater(t, len(words), 2)
+	stopped, err := generator.Generate(context.Background(), GeneratorRequest{Prompt: req.Prompt, Model: &ModelConfig{Name: "synthetic:", StopSequences: []string{" " + words[2]}}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain.OutputText, stopped.OutputText))
+	assert.Less(t, len(stopped.OutputText), len(plain.OutputText))
+
+	count := int32(3)
+	multi, err := generator.Generate(context.Background(), GeneratorRequest{Prompt: req.Prompt, Model: &ModelConfig{Name: "synthetic:", CandidateCount: &count}})
+	require.NoError(t, err)
+	require.Len(t, multi.Candidates, 3)
+	assert.Equal(t, plain.OutputText, multi.OutputText)
+	assert.Equal(t, multi.OutputText, multi.Candidates[0])
+	assert.NotEqual(t, multi.Candidates[0], multi.Candidates[1])
+}
+
+func TestRoutingGenerator_Route(t *testing.T) {
+	router := NewRoutingGenerator(map[string]Generator{
+		model_configs.ProviderSynthetic: NewSyntheticGenerator(),
+	})
+
+	_, err := router.Generate(context.Background(), GeneratorRequest{Model: &ModelConfig{Name: "gemini-3-flash-preview"}})
+	assert.ErrorIs(t, err, ErrGeneratorUnavailable)
+
+	_, err = router.Generate(context.Background(), GeneratorRequest{Model: &ModelConfig{Name: "gpt-4o-mini", Provider: model_configs.ProviderOpenAI}})
+	assert.ErrorIs(t, err, ErrGeneratorUnavailable)
+
+	resp, err := router.Generate(context.Background(), GeneratorRequest{Model: &ModelConfig{Name: "synthetic:3"}})
+	require.NoError(t, err)
+	assert.Equal(t, "STOP", resp.FinishReason)
+
+	resp, err = router.Generate(context.Background(), GeneratorRequest{Model: &ModelConfig{Name: "offline", Provider: model_configs.ProviderSynthetic}})
+	require.NoError(t, err)
+	assert.Equal(t, "STOP", resp.FinishReason)
+}
+
+func TestSyntheticGenerator_StreamMatchesGenerate(t *testing.T) {
+	req := GeneratorRequest{
+		Prompt:       "Stream please",
+		OutputSchema: json.RawMessage(`{"type": "OBJECT", "properties": {"summary": {"type": "STRING", "minLength": 80}}}`),
+		Model:        &ModelConfig{Name: "synthetic:11"},
+	}
+	generator := NewSyntheticGenerator()
+
+	var chunks []string
+	streamed, err := generator.GenerateStream(context.Background(), req, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, len(chunks), 1)
+	assert.Equal(t, streamed.OutputText, strings.Join(chunks, ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = generator.GenerateStream(ctx, req, func(string) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,240 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ToolTypeFunctionCalling enables server-side functions for a generation. Its
+// config selects the functions and bounds the number of tool rounds:
+//
+//	{"type": "function_calling", "config": {"functions": ["search_graph_nodes"], "max_rounds": 3}}
+//
+// An empty function list offers every registered function.
+const ToolTypeFunctionCalling = "function_calling"
+
+const (
+	defaultToolRounds = 4
+	maxToolRounds     = 8
+)
+
+// ToolCallContext identifies the generation a function is called for.
+type ToolCallContext struct {
+	UserID     uuid.UUID
+	DocumentID *uuid.UUID
+}
+
+// ToolHandler executes a function call. The returned value is marshalled to JSON
+// and sent back to the model; an error is reported to the model as {"error": ...}.
+type ToolHandler func(ctx context.Context, call ToolCallContext, args json.RawMessage) (any, error)
+
+// FunctionTool is a function the model can call during generation.
+type FunctionTool struct {
+	Declaration FunctionDeclaration
+	Handler     ToolHandler
+}
+
+// ToolRegistry holds the functions available to function_calling generations.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]FunctionTool
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]FunctionTool)}
+}
+
+// Register adds a function; names must be unique.
+func (r *ToolRegistry) Register(tool FunctionTool) error {
+	if tool.Declaration.Name == "" {
+		return fmt.Errorf("function name is required")
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("function %q has no handler", tool.Declaration.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[tool.Declaration.Name]; exists {
+		return fmt.Errorf("function %q is already registered", tool.Declaration.Name)
+	}
+	r.tools[tool.Declaration.Name] = tool
+	return nil
+}
+
+// Declarations returns the declarations for names, or for every registered
+// function when names is empty.
+func (r *ToolRegistry) Declarations(names []string) ([]FunctionDeclaration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(names) == 0 {
+		for name := range r.tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	declarations := make([]FunctionDeclaration, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tool, ok := r.tools[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		declarations = append(declarations, tool.Declaration)
+	}
+	return declarations, nil
+}
+
+// ToolCallRecord is the audit entry stored in artifact meta for each function call.
+type ToolCallRecord struct {
+	Round      int             `json:"round"`
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMS int64           `json:"duration_ms"`
+}
+
+// invoke runs a function call. Failures are returned to the model rather than
+// aborting the generation, so it can recover or answer without the tool.
+func (r *ToolRegistry) invoke(ctx context.Context, callCtx ToolCallContext, call FunctionCall) (ToolCallRecord, FunctionResult) {
+	record := ToolCallRecord{ID: call.ID, Name: call.Name, Args: call.Args}
+	started := time.Now()
+
+	r.mu.RLock()
+	tool, ok := r.tools[call.Name]
+	r.mu.RUnlock()
+
+	var output any
+	var err error
+	if !ok {
+		err = fmt.Errorf("unknown function %q", call.Name)
+	} else {
+		args := call.Args
+		if len(args) == 0 {
+			args = json.RawMessage(`{}`)
+		}
+		output, err = tool.Handler(ctx, callCtx, args)
+	}
+
+	var response json.RawMessage
+	if err == nil {
+		response, err = json.Marshal(output)
+	}
+	if err != nil {
+		record.Error = err.Error()
+		response, _ = json.Marshal(map[string]string{"error": err.Error()})
+	} else {
+		record.Result = response
+	}
+	record.DurationMS = time.Since(started).Milliseconds()
+
+	return record, FunctionResult{ID: call.ID, Name: call.Name, Response: response}
+}
+
+type functionCallingConfig struct {
+	Functions []string `json:"functions,omitempty"`
+	MaxRounds int      `json:"max_rounds,omitempty"`
+}
+
+// resolveFunctionTools removes the function_calling tool from tools and returns
+// the declarations it selects and the round limit. maxRounds is 0 when
+// function calling is not requested.
+func (s *Service) resolveFunctionTools(tools []ToolConfig) ([]ToolConfig, []FunctionDeclaration, int, error) {
+	filtered := make([]ToolConfig, 0, len(tools))
+	var declarations []FunctionDeclaration
+	maxRounds := 0
+
+	for _, tool := range tools {
+		if tool.Type != ToolTypeFunctionCalling {
+			filtered = append(filtered, tool)
+			continue
+		}
+		if maxRounds > 0 {
+			return nil, nil, 0, fmt.Errorf("%w: function_calling tool given more than once", ErrInvalidRequest)
+		}
+
+		var cfg functionCallingConfig
+		if len(tool.Config) > 0 {
+			if err := json.Unmarshal(tool.Config, &cfg); err != nil {
+				return nil, nil, 0, fmt.Errorf("%w: failed to parse function_calling config: %v", ErrInvalidRequest, err)
+			}
+		}
+		if cfg.MaxRounds < 0 || cfg.MaxRounds > maxToolRounds {
+			return nil, nil, 0, fmt.Errorf("%w: function_calling max_rounds must be between 1 and %d", ErrInvalidRequest, maxToolRounds)
+		}
+
+		selected, err := s.tools.Declarations(cfg.Functions)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+		if len(selected) == 0 {
+			return nil, nil, 0, fmt.Errorf("%w: no functions are registered", ErrInvalidRequest)
+		}
+
+		declarations = selected
+		maxRounds = cfg.MaxRounds
+		if maxRounds == 0 {
+			maxRounds = defaultToolRounds
+		}
+	}
+
+	return filtered, declarations, maxRounds, nil
+}
+
+// generateWithTools calls the model until it answers without requesting a
+// function, executing requested functions between calls. It fails when the
+// model still requests functions after maxRounds tool rounds. Usage and
+// attempts are summed over all calls; the call log is returned even on error.
+func (s *Service) generateWithTools(ctx context.Context, req GeneratorRequest, maxRounds int, callCtx ToolCallContext) (*GeneratorResponse, []ToolCallRecord, error) {
+	var records []ToolCallRecord
+	var usage *TokenUsage
+	var attempts []GeneratorAttempt
+
+	for round := 1; ; round++ {
+		resp, err := s.generator.Generate(ctx, req)
+		if err != nil {
+			return nil, records, err
+		}
+		usage = usage.Add(resp.Usage)
+		attempts = append(attempts, resp.Attempts...)
+
+		if len(resp.FunctionCalls) == 0 {
+			resp.Usage = usage
+			resp.Attempts = attempts
+			return resp, records, nil
+		}
+		if round > maxRounds {
+			return nil, records, fmt.Errorf("model still requested functions after %d tool rounds", maxRounds)
+		}
+
+		results := make([]FunctionResult, 0, len(resp.FunctionCalls))
+		for _, call := range resp.FunctionCalls {
+			record, result := s.tools.invoke(ctx, callCtx, call)
+			record.Round = round
+			records = append(records, record)
+			results = append(results, result)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, records, err
+		}
+
+		req.Turns = append(req.Turns,
+			ConversationTurn{Text: resp.OutputText, Calls: resp.FunctionCalls},
+			ConversationTurn{Results: results},
+		)
+	}
+}
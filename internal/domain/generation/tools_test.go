@@ -0,0 +1,137 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type toolLoopGenerator struct {
+	responses []*GeneratorResponse
+	requests  []GeneratorRequest
+}
+
+func (g *toolLoopGenerator) Generate(_ context.Context, req GeneratorRequest) (*GeneratorResponse, error) {
+	g.requests = append(g.requests, req)
+	resp := g.responses[0]
+	g.responses = g.responses[1:]
+	return resp, nil
+}
+
+func (g *toolLoopGenerator) GenerateStream(ctx context.Context, req GeneratorRequest, _ ChunkHandler) (*GeneratorResponse, error) {
+	return g.Generate(ctx, req)
+}
+
+func echoTool(name string) FunctionTool {
+	return FunctionTool{
+		Declaration: FunctionDeclaration{Name: name, Description: "echo"},
+		Handler: func(_ context.Context, _ ToolCallContext, args json.RawMessage) (any, error) {
+			var params map[string]any
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, err
+			}
+			if params["fail"] == true {
+				return nil, errors.New("tool failed")
+			}
+			return map[string]any{"echo": params["value"]}, nil
+		},
+	}
+}
+
+func TestGenerateWithTools_RunsCallsAndRecordsThem(t *testing.T) {
+	registry := NewToolRegistry()
+	require.NoError(t, registry.Register(echoTool("echo")))
+
+	generator := &toolLoopGenerator{responses: []*GeneratorResponse{
+		{
+			FunctionCalls: []FunctionCall{
+				{ID: "call-1", Name: "echo", Args: json.RawMessage(`{"value": "a"}`)},
+				{ID: "call-2", Name: "echo", Args: json.RawMessage(`{"fail": true}`)},
+			},
+			Usage: &TokenUsage{PromptTokens: 10, TotalTokens: 12},
+		},
+		{
+			FunctionCalls: []FunctionCall{{ID: "call-3", Name: "missing"}},
+			Usage:         &TokenUsage{PromptTokens: 20, TotalTokens: 25},
+		},
+		{OutputText: "done", FinishReason: "STOP", Usage: &TokenUsage{PromptTokens: 30, TotalTokens: 40}},
+	}}
+	service := &Service{generator: generator, tools: registry}
+
+	resp, records, err := service.generateWithTools(context.Background(), GeneratorRequest{Prompt: "go"}, 3, ToolCallContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.OutputText)
+	assert.Equal(t, &TokenUsage{PromptTokens: 60, TotalTokens: 77}, resp.Usage)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, 1, records[0].Round)
+	assert.JSONEq(t, `{"echo": "a"}`, string(records[0].Result))
+	assert.Equal(t, "tool failed", records[1].Error)
+	assert.Equal(t, 2, records[2].Round)
+	assert.Contains(t, records[2].Error, `unknown function "missing"`)
+
+	require.Len(t, generator.requests, 3)
+	assert.Empty(t, generator.requests[0].Turns)
+	require.Len(t, generator.requests[2].Turns, 4)
+	results := generator.requests[1].Turns[1].Results
+	require.Len(t, results, 2)
+	assert.Equal(t, "call-2", results[1].ID)
+	assert.JSONEq(t, `{"error": "tool failed"}`, string(results[1].Response))
+}
+
+func TestGenerateWithTools_StopsAfterMaxRounds(t *testing.T) {
+	registry := NewToolRegistry()
+	require.NoError(t, registry.Register(echoTool("echo")))
+
+	call := &GeneratorResponse{FunctionCalls: []FunctionCall{{Name: "echo", Args: json.RawMessage(`{}`)}}}
+	generator := &toolLoopGenerator{responses: []*GeneratorResponse{call, call, call}}
+	service := &Service{generator: generator, tools: registry}
+
+	_, records, err := service.generateWithTools(context.Background(), GeneratorRequest{Prompt: "go"}, 2, ToolCallContext{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 2 tool rounds")
+	assert.Len(t, records, 2)
+	assert.Len(t, generator.requests, 3)
+}
+
+func TestResolveFunctionTools(t *testing.T) {
+	registry := NewToolRegistry()
+	require.NoError(t, registry.Register(echoTool("b")))
+	require.NoError(t, registry.Register(echoTool("a")))
+	assert.Error(t, registry.Register(echoTool("a")))
+	service := &Service{tools: registry}
+
+	tools, declarations, rounds, err := service.resolveFunctionTools([]ToolConfig{
+		{Type: "file_search", Config: json.RawMessage(`{"store_names": ["s"]}`)},
+		{Type: ToolTypeFunctionCalling},
+	})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "file_search", tools[0].Type)
+	require.Len(t, declarations, 2)
+	assert.Equal(t, "a", declarations[0].Name)
+	assert.Equal(t, defaultToolRounds, rounds)
+
+	_, declarations, rounds, err = service.resolveFunctionTools([]ToolConfig{
+		{Type: ToolTypeFunctionCalling, Config: json.RawMessage(`{"functions": ["b"], "max_rounds": 2}`)},
+	})
+	require.NoError(t, err)
+	require.Len(t, declarations, 1)
+	assert.Equal(t, 2, rounds)
+
+	_, _, rounds, err = service.resolveFunctionTools(nil)
+	require.NoError(t, err)
+	assert.Zero(t, rounds)
+
+	for name, config := range map[string]string{
+		"unknown function": `{"functions": ["nope"]}`,
+		"too many rounds":  `{"max_rounds": 99}`,
+	} {
+		_, _, _, err := service.resolveFunctionTools([]ToolConfig{{Type: ToolTypeFunctionCalling, Config: json.RawMessage(config)}})
+		assert.ErrorIs(t, err, ErrInvalidRequest, name)
+	}
+}
@@ -0,0 +1,53 @@
+package generation
+
+// TokenUsage is the token accounting reported by a provider for one or more calls.
+type TokenUsage struct {
+	PromptTokens    int32 `json:"prompt_tokens"`
+	CandidateTokens int32 `json:"candidate_tokens"`
+	CachedTokens    int32 `json:"cached_tokens"` // portion of PromptTokens served from the provider cache
+	TotalTokens     int32 `json:"total_tokens"`
+}
+
+// Add returns the sum of two usages; a nil operand counts as zero.
+func (u *TokenUsage) Add(other *TokenUsage) *TokenUsage {
+	if u == nil {
+		return other
+	}
+	if other == nil {
+		return u
+	}
+	return &TokenUsage{
+		PromptTokens:    u.PromptTokens + other.PromptTokens,
+		CandidateTokens: u.CandidateTokens + other.CandidateTokens,
+		CachedTokens:    u.CachedTokens + other.CachedTokens,
+		TotalTokens:     u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// ModelPricing is the price table of a model config in USD per million tokens.
+type ModelPricing struct {
+	InputPerMillion       float64
+	OutputPerMillion      float64
+	CachedInputPerMillion *float64 // nil charges cached tokens at the input price
+}
+
+// Cost returns the USD cost of usage. Output is billed on whichever is larger of
+// the candidate tokens and the tokens in the total that are not prompt tokens, so
+// thinking tokens that providers only include in the total are still charged.
+func (p *ModelPricing) Cost(usage *TokenUsage) *float64 {
+	if p == nil || usage == nil {
+		return nil
+	}
+
+	cached := min(max(usage.CachedTokens, 0), max(usage.PromptTokens, 0))
+	uncached := max(usage.PromptTokens, 0) - cached
+	output := max(usage.CandidateTokens, usage.TotalTokens-usage.PromptTokens, 0)
+
+	cachedPrice := p.InputPerMillion
+	if p.CachedInputPerMillion != nil {
+		cachedPrice = *p.CachedInputPerMillion
+	}
+
+	cost := (float64(uncached)*p.InputPerMillion + float64(cached)*cachedPrice + float64(output)*p.OutputPerMillion) / 1_000_000
+	return &cost
+}
@@ -0,0 +1,42 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelPricing_Cost(t *testing.T) {
+	cachedPrice := 0.25
+	pricing := &ModelPricing{InputPerMillion: 1, OutputPerMillion: 4, CachedInputPerMillion: &cachedPrice}
+
+	cost := pricing.Cost(&TokenUsage{PromptTokens: 1_000_000, CandidateTokens: 500_000, CachedTokens: 400_000, TotalTokens: 1_500_000})
+	require.NotNil(t, cost)
+	// 600k uncached * $1 + 400k cached * $0.25 + 500k output * $4
+	assert.InDelta(t, 0.6+0.1+2.0, *cost, 1e-9)
+}
+
+func TestModelPricing_CostBillsThinkingTokensFromTotal(t *testing.T) {
+	pricing := &ModelPricing{InputPerMillion: 1, OutputPerMillion: 10}
+
+	cost := pricing.Cost(&TokenUsage{PromptTokens: 1000, CandidateTokens: 100, CachedTokens: 200, TotalTokens: 1500})
+	require.NotNil(t, cost)
+	// Cached tokens fall back to the input price; output is total - prompt = 500.
+	assert.InDelta(t, (1000*1.0+500*10.0)/1e6, *cost, 1e-12)
+}
+
+func TestModelPricing_CostWithoutPricingOrUsage(t *testing.T) {
+	var pricing *ModelPricing
+	assert.Nil(t, pricing.Cost(&TokenUsage{PromptTokens: 10}))
+	assert.Nil(t, (&ModelPricing{InputPerMillion: 1}).Cost(nil))
+}
+
+func TestTokenUsage_Add(t *testing.T) {
+	var usage *TokenUsage
+	usage = usage.Add(&TokenUsage{PromptTokens: 10, CandidateTokens: 5, TotalTokens: 15})
+	usage = usage.Add(nil)
+	usage = usage.Add(&TokenUsage{PromptTokens: 12, CandidateTokens: 4, CachedTokens: 8, TotalTokens: 16})
+
+	assert.Equal(t, &TokenUsage{PromptTokens: 22, CandidateTokens: 9, CachedTokens: 8, TotalTokens: 31}, usage)
+}
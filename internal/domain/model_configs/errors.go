@@ -0,0 +1,8 @@
+package model_configs
+
+import "errors"
+
+// Domain-specific errors for model configs
+var (
+	ErrInvalidModelConfig = errors.New("invalid model config")
+)
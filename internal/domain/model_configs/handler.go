@@ -1,6 +1,7 @@
 package model_configs
 
 import (
+	"errors"
 	"net/http"
 
 	"learning-core-api/internal/http/authz"
@@ -106,12 +107,12 @@ func (h *Handler) GetActive(w http.ResponseWriter, r *http.Request) {
 
 // Create creates a new model config.
 // @Summary Create model config
-// @Description Create a new model configuration
+// @Description Create a new model configuration. Optional sampling parameters are seed, stop_sequences (up to 5), candidate_count (1-8), presence_penalty and frequency_penalty (-2 to 2), thinking_budget (0 disables thinking, -1 lets the model decide) and safety_settings as [{"category", "threshold"}] with Gemini harm categories and thresholds. OpenAI configs cannot set safety_settings or thinking_budget.
 // @Tags Model Configs
 // @Security OAuth2[write]
 // @Param request body CreateModelConfigRequest true "Model config data"
 // @Success 201 {object} ModelConfig "Created model config"
-// @Failure 400 {object} map[string]string "Bad request"
+// @Failure 400 {object} map[string]string "Bad request - invalid body, provider or sampling parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /model-configs [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -124,6 +125,10 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	config, err := h.service.Create(ctx, req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidModelConfig) {
+			render.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		render.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -76,6 +76,22 @@ func TestHandler_Create(t *testing.T) {
 	assert.True(t, result.IsActive)
 }
 
+func TestHandler_CreateRejectsUnknownProvider(t *testing.T) {
+	handler := model_configs.NewHandler(model_configs.NewService(nil))
+
+	body, err := json.Marshal(model_configs.CreateModelConfigRequest{ModelName: "claude-3", Provider: "anthropic"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/model-configs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown provider \"anthropic\"`)
+}
+
 func TestHandler_ListAll(t *testing.T) {
 	handler, _, cleanup := setupHandler(t)
 	defer cleanup()
@@ -8,29 +8,75 @@ import (
 
 // ModelConfig represents a versioned model configuration stored in the database.
 type ModelConfig struct {
-	ID          uuid.UUID `json:"id"`
-	Version     int32     `json:"version"`
-	ModelName   string    `json:"model_name"`
-	DisplayName string    `json:"display_name"`
-	Temperature float64   `json:"temperature"`
-	MaxTokens   int32     `json:"max_tokens"`
-	TopP        float64   `json:"top_p"`
-	TopK        float64   `json:"top_k"`
-	MimeType    string    `json:"mime_type"`
-	IsActive    bool      `json:"is_active"`
-	CreatedBy   uuid.UUID `json:"created_by"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                uuid.UUID `json:"id"`
+	Version           int32     `json:"version"`
+	ModelName         string    `json:"model_name"`
+	DisplayName       string    `json:"display_name"`
+	Temperature       float64   `json:"temperature"`
+	MaxTokens         int32     `json:"max_tokens"`
+	TopP              float64   `json:"top_p"`
+	TopK              float64   `json:"top_k"`
+	MimeType          string    `json:"mime_type"`
+	Provider          string    `json:"provider"`
+	BaseURL           string    `json:"base_url,omitempty"`
+	RequestsPerMinute *int32    `json:"requests_per_minute,omitempty"` // process-wide cap; nil means unlimited
+	IsActive          bool      `json:"is_active"`
+	CreatedBy         uuid.UUID `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// Price table in USD per million tokens; nil prices leave artifact cost unset.
+	InputPricePerMillion       *float64 `json:"input_price_per_million,omitempty"`
+	OutputPricePerMillion      *float64 `json:"output_price_per_million,omitempty"`
+	CachedInputPricePerMillion *float64 `json:"cached_input_price_per_million,omitempty"` // defaults to the input price
+
+	SamplingParams
+}
+
+// SamplingParams are optional generation parameters beyond temperature and
+// top-p/top-k. Nil and empty values leave the provider default in place.
+type SamplingParams struct {
+	Seed             *int32          `json:"seed,omitempty"`
+	StopSequences    []string        `json:"stop_sequences,omitempty"`
+	CandidateCount   *int32          `json:"candidate_count,omitempty"`   // 1-8; the first schema-valid candidate is kept
+	PresencePenalty  *float64        `json:"presence_penalty,omitempty"`  // -2 to 2
+	FrequencyPenalty *float64        `json:"frequency_penalty,omitempty"` // -2 to 2
+	ThinkingBudget   *int32          `json:"thinking_budget,omitempty"`   // 0 disables thinking, -1 lets the model decide
+	SafetySettings   []SafetySetting `json:"safety_settings,omitempty"`
+}
+
+// SafetySetting sets the block threshold for one harm category, using the
+// Gemini names, e.g. HARM_CATEGORY_HARASSMENT and BLOCK_LOW_AND_ABOVE.
+type SafetySetting struct {
+	Category  string `json:"category" example:"HARM_CATEGORY_DANGEROUS_CONTENT"`
+	Threshold string `json:"threshold" example:"BLOCK_LOW_AND_ABOVE"`
 }
 
+// Supported model providers.
+const (
+	ProviderGemini    = "gemini"
+	ProviderOpenAI    = "openai"
+	ProviderSynthetic = "synthetic"
+)
+
 // CreateModelConfigRequest represents the data needed to create a model config.
 type CreateModelConfigRequest struct {
-	ModelName   string    `json:"model_name"`
-	DisplayName string    `json:"display_name"`
-	Temperature float64   `json:"temperature"`
-	MaxTokens   int32     `json:"max_tokens"`
-	TopP        float64   `json:"top_p"`
-	TopK        float64   `json:"top_k"`
-	MimeType    string    `json:"mime_type"`
-	IsActive    bool      `json:"is_active"`
-	CreatedBy   uuid.UUID `json:"created_by"`
+	ModelName         string    `json:"model_name"`
+	DisplayName       string    `json:"display_name"`
+	Temperature       float64   `json:"temperature"`
+	MaxTokens         int32     `json:"max_tokens"`
+	TopP              float64   `json:"top_p"`
+	TopK              float64   `json:"top_k"`
+	MimeType          string    `json:"mime_type"`
+	Provider          string    `json:"provider,omitempty"`            // gemini (default), openai or synthetic
+	BaseURL           string    `json:"base_url,omitempty"`            // OpenAI-compatible endpoint override
+	RequestsPerMinute *int32    `json:"requests_per_minute,omitempty"` // process-wide cap; nil means unlimited
+	IsActive          bool      `json:"is_active"`
+	CreatedBy         uuid.UUID `json:"created_by"`
+
+	// Price table in USD per million tokens.
+	InputPricePerMillion       *float64 `json:"input_price_per_million,omitempty"`
+	OutputPricePerMillion      *float64 `json:"output_price_per_million,omitempty"`
+	CachedInputPricePerMillion *float64 `json:"cached_input_price_per_million,omitempty"` // defaults to the input price
+
+	SamplingParams
 }
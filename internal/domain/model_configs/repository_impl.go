@@ -2,9 +2,12 @@ package model_configs
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 
 	"learning-core-api/internal/persistance/store"
 	"learning-core-api/internal/utils"
@@ -22,16 +25,49 @@ func NewRepository(queries *store.Queries) Repository {
 
 // Create creates a model config with an explicit version.
 func (r *RepositoryImpl) Create(ctx context.Context, req CreateModelConfigRequest) (*ModelConfig, error) {
+	provider := req.Provider
+	if provider == "" {
+		provider = ProviderGemini
+	}
+	var baseURL *string
+	if req.BaseURL != "" {
+		baseURL = &req.BaseURL
+	}
+
+	var safetySettings json.RawMessage
+	if len(req.SafetySettings) > 0 {
+		encoded, err := json.Marshal(req.SafetySettings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal safety settings: %w", err)
+		}
+		safetySettings = encoded
+	}
+
 	storeConfig, err := r.queries.CreateModelConfig(ctx, store.CreateModelConfigParams{
-		ModelName:   req.ModelName,
-		DisplayName: req.DisplayName,
-		Temperature: utils.SqlNullFloat64(&req.Temperature),
-		MaxTokens:   utils.SqlNullInt32(&req.MaxTokens),
-		TopP:        utils.SqlNullFloat64(&req.TopP),
-		TopK:        utils.SqlNullFloat64(&req.TopK),
-		MimeType:    utils.SqlNullString(&req.MimeType),
-		IsActive:    req.IsActive,
-		CreatedBy:   req.CreatedBy,
+		ModelName:         req.ModelName,
+		DisplayName:       req.DisplayName,
+		Temperature:       utils.SqlNullFloat64(&req.Temperature),
+		MaxTokens:         utils.SqlNullInt32(&req.MaxTokens),
+		TopP:              utils.SqlNullFloat64(&req.TopP),
+		TopK:              utils.SqlNullFloat64(&req.TopK),
+		MimeType:          utils.SqlNullString(&req.MimeType),
+		IsActive:          req.IsActive,
+		CreatedBy:         req.CreatedBy,
+		Provider:          provider,
+		BaseUrl:           utils.SqlNullString(baseURL),
+		RequestsPerMinute: utils.SqlNullInt32(req.RequestsPerMinute),
+
+		InputPricePerMillion:       utils.SqlNullFloat64(req.InputPricePerMillion),
+		OutputPricePerMillion:      utils.SqlNullFloat64(req.OutputPricePerMillion),
+		CachedInputPricePerMillion: utils.SqlNullFloat64(req.CachedInputPricePerMillion),
+
+		Seed:             utils.SqlNullInt32(req.Seed),
+		StopSequences:    req.StopSequences,
+		CandidateCount:   utils.SqlNullInt32(req.CandidateCount),
+		PresencePenalty:  utils.SqlNullFloat64(req.PresencePenalty),
+		FrequencyPenalty: utils.SqlNullFloat64(req.FrequencyPenalty),
+		ThinkingBudget:   utils.SqlNullInt32(req.ThinkingBudget),
+		SafetySettings:   utils.ToNullRawMessage(safetySettings),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create model config: %w", err)
@@ -84,34 +120,97 @@ func (r *RepositoryImpl) Activate(ctx context.Context, id uuid.UUID) error {
 
 func toDomainModelConfig(storeConfig *store.ModelConfig) *ModelConfig {
 	return &ModelConfig{
-		ID:          storeConfig.ID,
-		Version:     storeConfig.Version,
-		ModelName:   storeConfig.ModelName,
-		DisplayName: storeConfig.DisplayName,
-		Temperature: utils.NullFloat64ToFloat64(storeConfig.Temperature),
-		MaxTokens:   utils.NullInt32ToInt32(storeConfig.MaxTokens),
-		TopP:        utils.NullFloat64ToFloat64(storeConfig.TopP),
-		TopK:        utils.NullFloat64ToFloat64(storeConfig.TopK),
-		MimeType:    utils.NullStringToString(storeConfig.MimeType),
-		IsActive:    storeConfig.IsActive,
-		CreatedBy:   storeConfig.CreatedBy,
-		CreatedAt:   storeConfig.CreatedAt,
+		ID:                storeConfig.ID,
+		Version:           storeConfig.Version,
+		ModelName:         storeConfig.ModelName,
+		DisplayName:       storeConfig.DisplayName,
+		Temperature:       utils.NullFloat64ToFloat64(storeConfig.Temperature),
+		MaxTokens:         utils.NullInt32ToInt32(storeConfig.MaxTokens),
+		TopP:              utils.NullFloat64ToFloat64(storeConfig.TopP),
+		TopK:              utils.NullFloat64ToFloat64(storeConfig.TopK),
+		MimeType:          utils.NullStringToString(storeConfig.MimeType),
+		Provider:          storeConfig.Provider,
+		BaseURL:           utils.NullStringToString(storeConfig.BaseUrl),
+		RequestsPerMinute: utils.NullInt32ToPtr(storeConfig.RequestsPerMinute),
+		IsActive:          storeConfig.IsActive,
+		CreatedBy:         storeConfig.CreatedBy,
+		CreatedAt:         storeConfig.CreatedAt,
+
+		InputPricePerMillion:       utils.NullFloat64ToPtr(storeConfig.InputPricePerMillion),
+		OutputPricePerMillion:      utils.NullFloat64ToPtr(storeConfig.OutputPricePerMillion),
+		CachedInputPricePerMillion: utils.NullFloat64ToPtr(storeConfig.CachedInputPricePerMillion),
+
+		SamplingParams: toDomainSamplingParams(
+			storeConfig.Seed,
+			storeConfig.StopSequences,
+			storeConfig.CandidateCount,
+			storeConfig.PresencePenalty,
+			storeConfig.FrequencyPenalty,
+			storeConfig.ThinkingBudget,
+			storeConfig.SafetySettings,
+		),
 	}
 }
 
 func toDomainModelConfigRow(storeConfig *store.CreateModelConfigRow) *ModelConfig {
 	return &ModelConfig{
-		ID:          storeConfig.ID,
-		Version:     storeConfig.Version,
-		ModelName:   storeConfig.ModelName,
-		DisplayName: storeConfig.DisplayName,
-		Temperature: utils.NullFloat64ToFloat64(storeConfig.Temperature),
-		MaxTokens:   utils.NullInt32ToInt32(storeConfig.MaxTokens),
-		TopP:        utils.NullFloat64ToFloat64(storeConfig.TopP),
-		TopK:        utils.NullFloat64ToFloat64(storeConfig.TopK),
-		MimeType:    utils.NullStringToString(storeConfig.MimeType),
-		IsActive:    storeConfig.IsActive,
-		CreatedBy:   storeConfig.CreatedBy,
-		CreatedAt:   storeConfig.CreatedAt,
+		ID:                storeConfig.ID,
+		Version:           storeConfig.Version,
+		ModelName:         storeConfig.ModelName,
+		DisplayName:       storeConfig.DisplayName,
+		Temperature:       utils.NullFloat64ToFloat64(storeConfig.Temperature),
+		MaxTokens:         utils.NullInt32ToInt32(storeConfig.MaxTokens),
+		TopP:              utils.NullFloat64ToFloat64(storeConfig.TopP),
+		TopK:              utils.NullFloat64ToFloat64(storeConfig.TopK),
+		MimeType:          utils.NullStringToString(storeConfig.MimeType),
+		Provider:          storeConfig.Provider,
+		BaseURL:           utils.NullStringToString(storeConfig.BaseUrl),
+		RequestsPerMinute: utils.NullInt32ToPtr(storeConfig.RequestsPerMinute),
+		IsActive:          storeConfig.IsActive,
+		CreatedBy:         storeConfig.CreatedBy,
+		CreatedAt:         storeConfig.CreatedAt,
+
+		InputPricePerMillion:       utils.NullFloat64ToPtr(storeConfig.InputPricePerMillion),
+		OutputPricePerMillion:      utils.NullFloat64ToPtr(storeConfig.OutputPricePerMillion),
+		CachedInputPricePerMillion: utils.NullFloat64ToPtr(storeConfig.CachedInputPricePerMillion),
+
+		SamplingParams: toDomainSamplingParams(
+			storeConfig.Seed,
+			storeConfig.StopSequences,
+			storeConfig.CandidateCount,
+			storeConfig.PresencePenalty,
+			storeConfig.FrequencyPenalty,
+			storeConfig.ThinkingBudget,
+			storeConfig.SafetySettings,
+		),
+	}
+}
+
+// toDomainSamplingParams converts the optional sampling columns. Safety
+// settings are validated on write, so rows that fail to parse expose none.
+func toDomainSamplingParams(
+	seed sql.NullInt32,
+	stopSequences []string,
+	candidateCount sql.NullInt32,
+	presencePenalty sql.NullFloat64,
+	frequencyPenalty sql.NullFloat64,
+	thinkingBudget sql.NullInt32,
+	safetySettingsRaw pqtype.NullRawMessage,
+) SamplingParams {
+	var safetySettings []SafetySetting
+	if safetySettingsRaw.Valid {
+		if err := json.Unmarshal(safetySettingsRaw.RawMessage, &safetySettings); err != nil {
+			safetySettings = nil
+		}
+	}
+
+	return SamplingParams{
+		Seed:             utils.NullInt32ToPtr(seed),
+		StopSequences:    stopSequences,
+		CandidateCount:   utils.NullInt32ToPtr(candidateCount),
+		PresencePenalty:  utils.NullFloat64ToPtr(presencePenalty),
+		FrequencyPenalty: utils.NullFloat64ToPtr(frequencyPenalty),
+		ThinkingBudget:   utils.NullInt32ToPtr(thinkingBudget),
+		SafetySettings:   safetySettings,
 	}
 }
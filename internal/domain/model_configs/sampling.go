@@ -0,0 +1,81 @@
+package model_configs
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	maxStopSequences  = 5
+	maxCandidateCount = 8
+	maxPenalty        = 2.0
+)
+
+// SafetyCategories are the harm categories a safety setting may configure.
+var SafetyCategories = []string{
+	"HARM_CATEGORY_HARASSMENT",
+	"HARM_CATEGORY_HATE_SPEECH",
+	"HARM_CATEGORY_SEXUALLY_EXPLICIT",
+	"HARM_CATEGORY_DANGEROUS_CONTENT",
+	"HARM_CATEGORY_CIVIC_INTEGRITY",
+}
+
+// SafetyThresholds are the block thresholds a safety setting may use, from
+// strictest to most permissive.
+var SafetyThresholds = []string{
+	"BLOCK_LOW_AND_ABOVE",
+	"BLOCK_MEDIUM_AND_ABOVE",
+	"BLOCK_ONLY_HIGH",
+	"BLOCK_NONE",
+	"OFF",
+}
+
+// Validate checks the sampling parameters for a provider. OpenAI-compatible
+// models have no safety thresholds or thinking budget, so configs for them
+// may not set either.
+func (p SamplingParams) Validate(provider string) error {
+	if len(p.StopSequences) > maxStopSequences {
+		return fmt.Errorf("%w: at most %d stop_sequences are allowed", ErrInvalidModelConfig, maxStopSequences)
+	}
+	for _, sequence := range p.StopSequences {
+		if sequence == "" {
+			return fmt.Errorf("%w: stop_sequences may not be empty", ErrInvalidModelConfig)
+		}
+	}
+	if p.CandidateCount != nil && (*p.CandidateCount < 1 || *p.CandidateCount > maxCandidateCount) {
+		return fmt.Errorf("%w: candidate_count must be between 1 and %d", ErrInvalidModelConfig, maxCandidateCount)
+	}
+	for name, penalty := range map[string]*float64{"presence_penalty": p.PresencePenalty, "frequency_penalty": p.FrequencyPenalty} {
+		if penalty != nil && (*penalty < -maxPenalty || *penalty > maxPenalty) {
+			return fmt.Errorf("%w: %s must be between %g and %g", ErrInvalidModelConfig, name, -maxPenalty, maxPenalty)
+		}
+	}
+	if p.ThinkingBudget != nil && *p.ThinkingBudget < -1 {
+		return fmt.Errorf("%w: thinking_budget must be -1, 0 or a positive token count", ErrInvalidModelConfig)
+	}
+
+	seen := make(map[string]bool, len(p.SafetySettings))
+	for _, setting := range p.SafetySettings {
+		category := setting.Category
+		if !slices.Contains(SafetyCategories, category) {
+			return fmt.Errorf("%w: unknown safety category %q", ErrInvalidModelConfig, setting.Category)
+		}
+		if !slices.Contains(SafetyThresholds, setting.Threshold) {
+			return fmt.Errorf("%w: unknown safety threshold %q for %s", ErrInvalidModelConfig, setting.Threshold, category)
+		}
+		if seen[category] {
+			return fmt.Errorf("%w: safety category %s is set twice", ErrInvalidModelConfig, category)
+		}
+		seen[category] = true
+	}
+
+	if provider == ProviderOpenAI {
+		if len(p.SafetySettings) > 0 {
+			return fmt.Errorf("%w: safety_settings are not supported by the %s provider", ErrInvalidModelConfig, provider)
+		}
+		if p.ThinkingBudget != nil {
+			return fmt.Errorf("%w: thinking_budget is not supported by the %s provider", ErrInvalidModelConfig, provider)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package model_configs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingParams_Validate(t *testing.T) {
+	count, budget, penalty := int32(3), int32(1024), 1.5
+	valid := SamplingParams{
+		StopSequences:   []string{"###"},
+		CandidateCount:  &count,
+		PresencePenalty: &penalty,
+		ThinkingBudget:  &budget,
+		SafetySettings: []SafetySetting{
+			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_LOW_AND_ABOVE"},
+			{Category: "HARM_CATEGORY_DANGEROUS_CONTENT", Threshold: "BLOCK_LOW_AND_ABOVE"},
+		},
+	}
+	assert.NoError(t, valid.Validate(ProviderGemini))
+	assert.NoError(t, SamplingParams{}.Validate(ProviderOpenAI))
+	assert.ErrorIs(t, valid.Validate(ProviderOpenAI), ErrInvalidModelConfig)
+
+	tooMany, negativeBudget, highPenalty := int32(9), int32(-2), 2.5
+	for name, params := range map[string]SamplingParams{
+		"empty stop sequence": {StopSequences: []string{""}},
+		"too many stops":      {StopSequences: []string{"a", "b", "c", "d", "e", "f"}},
+		"candidate count":     {CandidateCount: &tooMany},
+		"penalty":             {FrequencyPenalty: &highPenalty},
+		"thinking budget":     {ThinkingBudget: &negativeBudget},
+		"unknown category":    {SafetySettings: []SafetySetting{{Category: "HARM_CATEGORY_SPAM", Threshold: "BLOCK_NONE"}}},
+		"unknown threshold":   {SafetySettings: []SafetySetting{{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_SOME"}}},
+		"duplicate category": {SafetySettings: []SafetySetting{
+			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_NONE"},
+			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "OFF"},
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, params.Validate(ProviderGemini), ErrInvalidModelConfig)
+		})
+	}
+}
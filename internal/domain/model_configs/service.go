@@ -2,6 +2,7 @@ package model_configs
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -40,8 +41,21 @@ func (s *ServiceImpl) ListAll(ctx context.Context) ([]*ModelConfig, error) {
 	return s.repo.ListAll(ctx)
 }
 
-// Create creates a new model config.
+// Create creates a new model config. The provider and the sampling parameters
+// are validated first.
 func (s *ServiceImpl) Create(ctx context.Context, req CreateModelConfigRequest) (*ModelConfig, error) {
+	provider := req.Provider
+	if provider == "" {
+		provider = ProviderGemini
+	}
+	switch provider {
+	case ProviderGemini, ProviderOpenAI, ProviderSynthetic:
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrInvalidModelConfig, provider)
+	}
+	if err := req.SamplingParams.Validate(provider); err != nil {
+		return nil, err
+	}
 	return s.repo.Create(ctx, req)
 }
 
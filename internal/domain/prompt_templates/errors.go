@@ -0,0 +1,15 @@
+package prompt_templates
+
+import "errors"
+
+// Domain-specific errors for prompt templates
+var (
+	ErrInvalidVariableDeclaration = errors.New("invalid template variable declaration")
+	ErrInvalidVariables           = errors.New("invalid template variables")
+	ErrInvalidGenerationType      = errors.New("invalid generation type")
+	ErrInvalidPartial             = errors.New("invalid prompt partial")
+	ErrPartialNotFound            = errors.New("prompt partial not found")
+	ErrInvalidExperiment          = errors.New("invalid prompt experiment")
+	ErrExperimentNotFound         = errors.New("prompt experiment not found")
+	ErrExperimentConflict         = errors.New("prompt experiment conflict")
+)
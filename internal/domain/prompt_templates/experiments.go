@@ -0,0 +1,178 @@
+package prompt_templates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Prompt experiment statuses. An experiment is created as a draft, splits
+// traffic while running and can no longer be changed once stopped.
+const (
+	ExperimentStatusDraft   = "DRAFT"
+	ExperimentStatusRunning = "RUNNING"
+	ExperimentStatusStopped = "STOPPED"
+)
+
+// DefaultReportEvalType is the eval type experiment reports compare by default.
+const DefaultReportEvalType = "groundedness"
+
+const (
+	minExperimentVariants = 2
+	maxExperimentVariants = 10
+	maxExperimentWeight   = 10000
+)
+
+// PromptExperiment splits generations of one generation type across several
+// prompt template versions by weight.
+// @Description A/B experiment over prompt template versions
+type PromptExperiment struct {
+	ID             uuid.UUID           `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string              `json:"name" example:"Questions v3 vs v4"`
+	Description    *string             `json:"description,omitempty" example:"Does the stricter grounding wording help?"`
+	GenerationType string              `json:"generation_type" example:"QUESTIONS"`
+	Status         string              `json:"status" example:"RUNNING"`
+	Variants       []ExperimentVariant `json:"variants"`
+	CreatedBy      *string             `json:"created_by,omitempty" example:"admin@example.com"`
+	StartedAt      *time.Time          `json:"started_at,omitempty" example:"2026-01-19T03:40:00Z"`
+	StoppedAt      *time.Time          `json:"stopped_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at" example:"2026-01-19T03:40:00Z"`
+	UpdatedAt      time.Time           `json:"updated_at" example:"2026-01-19T03:40:00Z"`
+}
+
+// ExperimentVariant is one prompt template version taking part in an experiment.
+type ExperimentVariant struct {
+	PromptTemplateID uuid.UUID `json:"prompt_template_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PromptVersion    int32     `json:"prompt_version" example:"3"`
+	Weight           int32     `json:"weight" example:"50"`
+}
+
+// CreatePromptExperimentRequest represents data needed to create a prompt experiment.
+type CreatePromptExperimentRequest struct {
+	Name           string                     `json:"name"`
+	Description    *string                    `json:"description,omitempty"`
+	GenerationType string                     `json:"generation_type"`
+	Variants       []ExperimentVariantRequest `json:"variants"`
+	CreatedBy      *string                    `json:"created_by,omitempty"`
+}
+
+// ExperimentVariantRequest names a prompt template version and its traffic weight.
+type ExperimentVariantRequest struct {
+	PromptVersion int32 `json:"prompt_version" example:"3"`
+	Weight        int32 `json:"weight" example:"50"`
+}
+
+// ExperimentAssignment records which variant of a running experiment rendered
+// a prompt. The generation service stores it in artifact meta as "experiment".
+type ExperimentAssignment struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	PromptTemplateID uuid.UUID `json:"prompt_template_id"`
+	PromptVersion    int32     `json:"prompt_version"`
+}
+
+// ExperimentReport compares eval verdicts of the artifacts generated by each
+// variant of an experiment.
+// @Description Per-variant eval verdicts of an experiment's artifacts
+type ExperimentReport struct {
+	Experiment *PromptExperiment `json:"experiment"`
+	EvalType   string            `json:"eval_type" example:"groundedness"`
+	Variants   []VariantReport   `json:"variants"`
+}
+
+// VariantReport counts the artifacts of one variant and the latest verdicts of
+// the eval items generated from them. PassRate is passed / evaluated and is
+// omitted until at least one item has been evaluated.
+type VariantReport struct {
+	PromptTemplateID uuid.UUID `json:"prompt_template_id"`
+	PromptVersion    int32     `json:"prompt_version" example:"3"`
+	Weight           int32     `json:"weight" example:"50"`
+	Artifacts        int64     `json:"artifacts" example:"120"`
+	UsableArtifacts  int64     `json:"usable_artifacts" example:"118"` // every status but ERROR and INVALID
+	Evaluated        int64     `json:"evaluated" example:"40"`
+	Passed           int64     `json:"passed" example:"34"`
+	Failed           int64     `json:"failed" example:"4"`
+	Warned           int64     `json:"warned" example:"2"`
+	PassRate         *float64  `json:"pass_rate,omitempty" example:"0.85"`
+	AvgScore         *float64  `json:"avg_score,omitempty" example:"0.91"`
+}
+
+// ValidateExperiment checks an experiment request before its prompt versions
+// are resolved.
+func ValidateExperiment(req CreatePromptExperimentRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidExperiment)
+	}
+	if len(req.Variants) < minExperimentVariants || len(req.Variants) > maxExperimentVariants {
+		return fmt.Errorf("%w: between %d and %d variants are required", ErrInvalidExperiment, minExperimentVariants, maxExperimentVariants)
+	}
+	seen := make(map[int32]bool, len(req.Variants))
+	for _, variant := range req.Variants {
+		if variant.PromptVersion <= 0 {
+			return fmt.Errorf("%w: prompt_version must be positive", ErrInvalidExperiment)
+		}
+		if seen[variant.PromptVersion] {
+			return fmt.Errorf("%w: prompt_version %d is listed twice", ErrInvalidExperiment, variant.PromptVersion)
+		}
+		seen[variant.PromptVersion] = true
+		if variant.Weight <= 0 || variant.Weight > maxExperimentWeight {
+			return fmt.Errorf("%w: weight of prompt_version %d must be between 1 and %d", ErrInvalidExperiment, variant.PromptVersion, maxExperimentWeight)
+		}
+	}
+	return nil
+}
+
+// TotalWeight returns the sum of the variant weights.
+func (e *PromptExperiment) TotalWeight() int {
+	total := 0
+	for _, variant := range e.Variants {
+		total += int(variant.Weight)
+	}
+	return total
+}
+
+// PickVariant returns the variant owning roll, a number in [0, TotalWeight()).
+// Each variant owns a range of rolls as wide as its weight, in variant order.
+func (e *PromptExperiment) PickVariant(roll int) ExperimentVariant {
+	for _, variant := range e.Variants {
+		if roll < int(variant.Weight) {
+			return variant
+		}
+		roll -= int(variant.Weight)
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+// Assignment records variant as the one chosen from this experiment.
+func (e *PromptExperiment) Assignment(variant ExperimentVariant) *ExperimentAssignment {
+	return &ExperimentAssignment{
+		ID:               e.ID,
+		Name:             e.Name,
+		PromptTemplateID: variant.PromptTemplateID,
+		PromptVersion:    variant.PromptVersion,
+	}
+}
+
+// newVariantReport derives the pass rate and drops the average score of
+// variants without evaluated items.
+func newVariantReport(variant ExperimentVariant, artifacts, usable, evaluated, passed, failed, warned int64, avgScore float64) VariantReport {
+	report := VariantReport{
+		PromptTemplateID: variant.PromptTemplateID,
+		PromptVersion:    variant.PromptVersion,
+		Weight:           variant.Weight,
+		Artifacts:        artifacts,
+		UsableArtifacts:  usable,
+		Evaluated:        evaluated,
+		Passed:           passed,
+		Failed:           failed,
+		Warned:           warned,
+	}
+	if evaluated > 0 {
+		passRate := float64(passed) / float64(evaluated)
+		report.PassRate = &passRate
+		report.AvgScore = &avgScore
+	}
+	return report
+}
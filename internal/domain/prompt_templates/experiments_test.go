@@ -0,0 +1,74 @@
+package prompt_templates
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExperiment(t *testing.T) {
+	valid := CreatePromptExperimentRequest{
+		Name:           "v1 vs v2",
+		GenerationType: "QUESTIONS",
+		Variants:       []ExperimentVariantRequest{{PromptVersion: 1, Weight: 50}, {PromptVersion: 2, Weight: 50}},
+	}
+	assert.NoError(t, ValidateExperiment(valid))
+
+	for name, mutate := range map[string]func(*CreatePromptExperimentRequest){
+		"missing name":      func(req *CreatePromptExperimentRequest) { req.Name = " " },
+		"single variant":    func(req *CreatePromptExperimentRequest) { req.Variants = req.Variants[:1] },
+		"duplicate version": func(req *CreatePromptExperimentRequest) { req.Variants[1].PromptVersion = 1 },
+		"zero version":      func(req *CreatePromptExperimentRequest) { req.Variants[0].PromptVersion = 0 },
+		"zero weight":       func(req *CreatePromptExperimentRequest) { req.Variants[0].Weight = 0 },
+		"weight too large":  func(req *CreatePromptExperimentRequest) { req.Variants[0].Weight = maxExperimentWeight + 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := valid
+			req.Variants = append([]ExperimentVariantRequest(nil), valid.Variants...)
+			mutate(&req)
+			assert.ErrorIs(t, ValidateExperiment(req), ErrInvalidExperiment)
+		})
+	}
+}
+
+func TestPromptExperiment_PickVariant(t *testing.T) {
+	experiment := &PromptExperiment{
+		ID:   uuid.New(),
+		Name: "split",
+		Variants: []ExperimentVariant{
+			{PromptTemplateID: uuid.New(), PromptVersion: 1, Weight: 1},
+			{PromptTemplateID: uuid.New(), PromptVersion: 2, Weight: 3},
+		},
+	}
+	require.Equal(t, 4, experiment.TotalWeight())
+
+	counts := map[int32]int{}
+	for roll := 0; roll < experiment.TotalWeight(); roll++ {
+		counts[experiment.PickVariant(roll).PromptVersion]++
+	}
+	assert.Equal(t, map[int32]int{1: 1, 2: 3}, counts)
+
+	assignment := experiment.Assignment(experiment.PickVariant(3))
+	assert.Equal(t, &ExperimentAssignment{
+		ID:               experiment.ID,
+		Name:             "split",
+		PromptTemplateID: experiment.Variants[1].PromptTemplateID,
+		PromptVersion:    2,
+	}, assignment)
+}
+
+func TestNewVariantReport(t *testing.T) {
+	variant := ExperimentVariant{PromptTemplateID: uuid.New(), PromptVersion: 2, Weight: 50}
+
+	report := newVariantReport(variant, 10, 9, 8, 6, 1, 1, 0.75)
+	require.NotNil(t, report.PassRate)
+	assert.InDelta(t, 0.75, *report.PassRate, 1e-9)
+	require.NotNil(t, report.AvgScore)
+	assert.EqualValues(t, 9, report.UsableArtifacts)
+
+	empty := newVariantReport(variant, 3, 3, 0, 0, 0, 0, 0)
+	assert.Nil(t, empty.PassRate)
+	assert.Nil(t, empty.AvgScore)
+}
@@ -1,6 +1,7 @@
 package prompt_templates
 
 import (
+	"errors"
 	"net/http"
 
 	"learning-core-api/internal/http/authz"
@@ -24,11 +25,21 @@ func (h *Handler) RegisterPublicRoutes(r chi.Router) {
 
 func (h *Handler) RegisterAdminRoutes(r chi.Router) {
 	r.With(authz.RequireScope("read")).Get("/prompt-templates", h.ListByGenerationType)
+	r.With(authz.RequireScope("read")).Get("/prompt-templates/partials", h.ListActivePartials)
+	r.With(authz.RequireScope("read")).Get("/prompt-templates/partials/{name}", h.ListPartialVersions)
+	r.With(authz.RequireScope("write")).Post("/prompt-templates/partials", h.CreatePartial)
+	r.With(authz.RequireScope("write")).Post("/prompt-templates/partials/{id}/activate", h.ActivatePartial)
 	r.With(authz.RequireScope("read")).Get("/prompt-templates/{id}", h.GetByID)
 	r.With(authz.RequireScope("read")).Get("/prompt-templates/generation-type/{generationType}", h.GetActiveByGenerationType)
 	r.With(authz.RequireScope("write")).Post("/prompt-templates", h.Create)
 	r.With(authz.RequireScope("write")).Post("/prompt-templates/{id}/activate", h.Activate)
 	r.With(authz.RequireScope("write")).Post("/prompt-templates/{id}/deactivate", h.Deactivate)
+	r.With(authz.RequireScope("read")).Get("/prompt-experiments", h.ListExperiments)
+	r.With(authz.RequireScope("write")).Post("/prompt-experiments", h.CreateExperiment)
+	r.With(authz.RequireScope("read")).Get("/prompt-experiments/{id}", h.GetExperiment)
+	r.With(authz.RequireScope("read")).Get("/prompt-experiments/{id}/report", h.GetExperimentReport)
+	r.With(authz.RequireScope("write")).Post("/prompt-experiments/{id}/start", h.StartExperiment)
+	r.With(authz.RequireScope("write")).Post("/prompt-experiments/{id}/stop", h.StopExperiment)
 }
 
 func (h *Handler) RegisterTeacherRoutes(r chi.Router) {
@@ -46,7 +57,7 @@ func (h *Handler) RegisterLearnerRoutes(r chi.Router) {
 // @Description Get all prompt templates for a specific generation type
 // @Tags Prompt Templates
 // @Security OAuth2[read]
-// @Param generation_type query string true "Generation type (CLASSIFICATION, QUESTIONS, or SECTION_TOPICS)"
+// @Param generation_type query string true "Generation type (CLASSIFICATION, QUESTIONS, SECTION_TOPICS, FLASHCARDS, SUMMARY, OUTLINE, HINT or MULTIPLE_CHOICE)"
 // @Success 200 {array} PromptTemplate "List of prompt templates"
 // @Failure 400 {object} map[string]string "Bad request - missing generation_type"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -101,7 +112,7 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve the currently active prompt template for a specific generation type
 // @Tags Prompt Templates
 // @Security OAuth2[read]
-// @Param generationType path string true "Generation type (CLASSIFICATION, QUESTIONS, or SECTION_TOPICS)"
+// @Param generationType path string true "Generation type (CLASSIFICATION, QUESTIONS, SECTION_TOPICS, FLASHCARDS, SUMMARY, OUTLINE, HINT or MULTIPLE_CHOICE)"
 // @Success 200 {object} PromptTemplate "Active prompt template"
 // @Failure 400 {object} map[string]string "Bad request - missing generation_type"
 // @Failure 404 {object} map[string]string "Active template not found"
@@ -125,13 +136,13 @@ func (h *Handler) GetActiveByGenerationType(w http.ResponseWriter, r *http.Reque
 
 // Create creates a new version of a prompt template.
 // @Summary Create new prompt template version
-// @Description Create a new version of a prompt template (immutable - cannot edit existing)
+// @Description Create a new version of a prompt template (immutable - cannot edit existing). Variables are declared in metadata as {"variables": [{"name", "type", "required", "default", "description"}]}; type is one of string, integer, number, boolean, array or object.
 // @Tags Prompt Templates
 // @Security OAuth2[write]
 // @Accept json
 // @Param request body CreatePromptTemplateVersionRequest true "Template version request"
 // @Success 201 {object} PromptTemplate "Created prompt template"
-// @Failure 400 {object} map[string]string "Bad request - invalid request body"
+// @Failure 400 {object} map[string]string "Bad request - invalid request body, generation type or variable declarations"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /prompt-templates [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -144,6 +155,10 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	template, err := h.service.CreateVersion(ctx, req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidVariableDeclaration) || errors.Is(err, ErrInvalidGenerationType) {
+			render.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		render.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -206,3 +221,286 @@ func (h *Handler) Deactivate(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, http.StatusOK, template)
 }
+
+// ListActivePartials lists the active version of every prompt partial.
+// @Summary List active prompt partials
+// @Description Get the active version of every named prompt partial
+// @Tags Prompt Templates
+// @Security OAuth2[read]
+// @Success 200 {array} PromptPartial "Active prompt partials"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-templates/partials [get]
+func (h *Handler) ListActivePartials(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	partials, err := h.service.ListActivePartials(ctx)
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, partials)
+}
+
+// ListPartialVersions lists every version of a prompt partial.
+// @Summary List prompt partial versions
+// @Description Get every version of a named prompt partial, newest first
+// @Tags Prompt Templates
+// @Security OAuth2[read]
+// @Param name path string true "Partial name"
+// @Success 200 {array} PromptPartial "Prompt partial versions"
+// @Failure 404 {object} map[string]string "Partial not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-templates/partials/{name} [get]
+func (h *Handler) ListPartialVersions(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	ctx := r.Context()
+	partials, err := h.service.ListPartialVersions(ctx, name)
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(partials) == 0 {
+		render.Error(w, http.StatusNotFound, "Partial not found")
+		return
+	}
+
+	render.JSON(w, http.StatusOK, partials)
+}
+
+// CreatePartial creates a new version of a prompt partial.
+// @Summary Create new prompt partial version
+// @Description Create a new version of a named prompt partial (immutable - cannot edit existing). Templates include partials with {{template "name" .}}; the name must be lowercase letters, digits and underscores.
+// @Tags Prompt Templates
+// @Security OAuth2[write]
+// @Accept json
+// @Param request body CreatePromptPartialVersionRequest true "Partial version request"
+// @Success 201 {object} PromptPartial "Created prompt partial"
+// @Failure 400 {object} map[string]string "Bad request - invalid request body, name or template"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-templates/partials [post]
+func (h *Handler) CreatePartial(w http.ResponseWriter, r *http.Request) {
+	var req CreatePromptPartialVersionRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ctx := r.Context()
+	partial, err := h.service.CreatePartialVersion(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPartial) {
+			render.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, partial)
+}
+
+// ActivatePartial marks a prompt partial version as active.
+// @Summary Activate prompt partial
+// @Description Mark a prompt partial version as active (deactivates other versions of the same name)
+// @Tags Prompt Templates
+// @Security OAuth2[write]
+// @Param id path string true "Partial ID (UUID)"
+// @Success 200 {object} PromptPartial "Activated prompt partial"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-templates/partials/{id}/activate [post]
+func (h *Handler) ActivatePartial(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid partial ID")
+		return
+	}
+
+	ctx := r.Context()
+	partial, err := h.service.ActivatePartial(ctx, id)
+	if err != nil {
+		render.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, partial)
+}
+
+// ListExperiments lists prompt experiments.
+// @Summary List prompt experiments
+// @Description Get prompt A/B experiments with their variants, newest first
+// @Tags Prompt Templates
+// @Security OAuth2[read]
+// @Param generation_type query string false "Only experiments for this generation type"
+// @Success 200 {array} PromptExperiment "Prompt experiments"
+// @Failure 400 {object} map[string]string "Bad request - invalid generation type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments [get]
+func (h *Handler) ListExperiments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	experiments, err := h.service.ListExperiments(ctx, r.URL.Query().Get("generation_type"))
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, experiments)
+}
+
+// CreateExperiment creates a draft prompt experiment.
+// @Summary Create prompt experiment
+// @Description Create a draft A/B experiment that splits generations of a generation type across prompt template versions by weight. Once started, every generation that does not pin a prompt_version renders a variant picked at random in proportion to its weight, and the choice is recorded in the artifact meta as "experiment".
+// @Tags Prompt Templates
+// @Security OAuth2[write]
+// @Accept json
+// @Param request body CreatePromptExperimentRequest true "Experiment request"
+// @Success 201 {object} PromptExperiment "Created prompt experiment"
+// @Failure 400 {object} map[string]string "Bad request - invalid request body, generation type, versions or weights"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments [post]
+func (h *Handler) CreateExperiment(w http.ResponseWriter, r *http.Request) {
+	var req CreatePromptExperimentRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.service.CreateExperiment(ctx, req)
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, experiment)
+}
+
+// GetExperiment retrieves a prompt experiment by ID.
+// @Summary Get prompt experiment
+// @Description Retrieve a prompt experiment with its variants
+// @Tags Prompt Templates
+// @Security OAuth2[read]
+// @Param id path string true "Experiment ID (UUID)"
+// @Success 200 {object} PromptExperiment "Prompt experiment"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments/{id} [get]
+func (h *Handler) GetExperiment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid experiment ID")
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.service.GetExperiment(ctx, id)
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, experiment)
+}
+
+// GetExperimentReport compares eval verdicts across an experiment's variants.
+// @Summary Get prompt experiment report
+// @Description Per variant, count the experiment's artifacts and the latest eval verdicts of the eval items generated from them (artifacts ingested into evals), with the pass rate and average score
+// @Tags Prompt Templates
+// @Security OAuth2[read]
+// @Param id path string true "Experiment ID (UUID)"
+// @Param eval_type query string false "Eval type to compare (default: groundedness)"
+// @Success 200 {object} ExperimentReport "Experiment report"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments/{id}/report [get]
+func (h *Handler) GetExperimentReport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid experiment ID")
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.service.GetExperimentReport(ctx, id, r.URL.Query().Get("eval_type"))
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, report)
+}
+
+// StartExperiment starts splitting traffic for a draft experiment.
+// @Summary Start prompt experiment
+// @Description Start a draft experiment. Only one experiment per generation type can run at a time.
+// @Tags Prompt Templates
+// @Security OAuth2[write]
+// @Param id path string true "Experiment ID (UUID)"
+// @Success 200 {object} PromptExperiment "Started prompt experiment"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 409 {object} map[string]string "Experiment is not a draft or another experiment is running"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments/{id}/start [post]
+func (h *Handler) StartExperiment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid experiment ID")
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.service.StartExperiment(ctx, id)
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, experiment)
+}
+
+// StopExperiment stops a running experiment.
+// @Summary Stop prompt experiment
+// @Description Stop a running experiment. Generations go back to the active prompt template.
+// @Tags Prompt Templates
+// @Security OAuth2[write]
+// @Param id path string true "Experiment ID (UUID)"
+// @Success 200 {object} PromptExperiment "Stopped prompt experiment"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID format"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 409 {object} map[string]string "Experiment is not running"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /prompt-experiments/{id}/stop [post]
+func (h *Handler) StopExperiment(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid experiment ID")
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.service.StopExperiment(ctx, id)
+	if err != nil {
+		render.Error(w, experimentErrorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, experiment)
+}
+
+func experimentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrExperimentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidExperiment), errors.Is(err, ErrInvalidGenerationType):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrExperimentConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -10,17 +10,18 @@ import (
 // PromptTemplate represents a prompt template stored in the database.
 // @Description Prompt template with versioning and activation support
 type PromptTemplate struct {
-	ID             uuid.UUID       `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	GenerationType string          `json:"generation_type" example:"CLASSIFICATION"`
-	Version        int32           `json:"version" example:"1"`
-	IsActive       bool            `json:"is_active" example:"true"`
-	Title          string          `json:"title" example:"Classification Prompt v1"`
-	Description    *string         `json:"description,omitempty" example:"Classifies documents"`
-	Template       string          `json:"template" example:"Classify the following text..."`
-	Metadata       json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
-	CreatedBy      *string         `json:"created_by,omitempty" example:"admin@example.com"`
-	CreatedAt      time.Time       `json:"created_at" example:"2026-01-19T03:40:00Z"`
-	UpdatedAt      time.Time       `json:"updated_at" example:"2026-01-19T03:40:00Z"`
+	ID             uuid.UUID          `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	GenerationType string             `json:"generation_type" example:"CLASSIFICATION"`
+	Version        int32              `json:"version" example:"1"`
+	IsActive       bool               `json:"is_active" example:"true"`
+	Title          string             `json:"title" example:"Classification Prompt v1"`
+	Description    *string            `json:"description,omitempty" example:"Classifies documents"`
+	Template       string             `json:"template" example:"Classify the following text..."`
+	Metadata       json.RawMessage    `json:"metadata,omitempty" swaggertype:"object"`
+	Variables      []TemplateVariable `json:"variables"` // parsed from metadata.variables
+	CreatedBy      *string            `json:"created_by,omitempty" example:"admin@example.com"`
+	CreatedAt      time.Time          `json:"created_at" example:"2026-01-19T03:40:00Z"`
+	UpdatedAt      time.Time          `json:"updated_at" example:"2026-01-19T03:40:00Z"`
 }
 
 // CreatePromptTemplateRequest represents data needed to create a prompt template.
@@ -45,3 +46,34 @@ type CreatePromptTemplateVersionRequest struct {
 	Metadata       json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
 	CreatedBy      *string         `json:"created_by,omitempty"`
 }
+
+// PromptPartial is a named, versioned prompt fragment that templates include
+// with {{template "name" .}}.
+// @Description Versioned prompt partial included by prompt templates
+type PromptPartial struct {
+	ID          uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string    `json:"name" example:"grounding_rules"`
+	Version     int32     `json:"version" example:"1"`
+	IsActive    bool      `json:"is_active" example:"true"`
+	Description *string   `json:"description,omitempty" example:"Shared grounding rules"`
+	Template    string    `json:"template" example:"- Use ONLY the provided source documents."`
+	CreatedBy   *string   `json:"created_by,omitempty" example:"admin@example.com"`
+	CreatedAt   time.Time `json:"created_at" example:"2026-01-19T03:40:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2026-01-19T03:40:00Z"`
+}
+
+// CreatePromptPartialVersionRequest represents data needed to create a new partial version.
+type CreatePromptPartialVersionRequest struct {
+	Name        string  `json:"name"`
+	IsActive    bool    `json:"is_active"`
+	Description *string `json:"description,omitempty"`
+	Template    string  `json:"template"`
+	CreatedBy   *string `json:"created_by,omitempty"`
+}
+
+// PartialVersion identifies the exact partial version used to render a prompt.
+type PartialVersion struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Version int32     `json:"version"`
+}
@@ -0,0 +1,121 @@
+package prompt_templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+	"text/template/parse"
+)
+
+// maxPartialDepth bounds how deeply partials may include other partials.
+const maxPartialDepth = 8
+
+var partialNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// PartialLoader returns the partial to use for a name referenced by a template.
+type PartialLoader func(name string) (*PromptPartial, error)
+
+// ValidatePartial checks that a partial has a usable name and parses as a template.
+func ValidatePartial(name, text string) error {
+	if !partialNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: name %q must match %s", ErrInvalidPartial, name, partialNamePattern.String())
+	}
+	if text == "" {
+		return fmt.Errorf("%w: template is required", ErrInvalidPartial)
+	}
+	if _, err := template.New(name).Parse(text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPartial, err)
+	}
+	return nil
+}
+
+// Compose parses text as the named root template and attaches every partial it
+// includes, directly or through other partials. Templates defined inline with
+// {{define}} take precedence over stored partials. It returns the composed
+// template and the partial versions that were attached, sorted by name.
+func Compose(name, text string, load PartialLoader) (*template.Template, []PartialVersion, error) {
+	root, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	type pending struct {
+		name  string
+		depth int
+	}
+	var queue []pending
+	for _, ref := range templateReferences(root) {
+		queue = append(queue, pending{name: ref, depth: 1})
+	}
+
+	var used []PartialVersion
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if root.Lookup(next.name) != nil {
+			continue
+		}
+		if next.depth > maxPartialDepth {
+			return nil, nil, fmt.Errorf("%w: partials nested deeper than %d levels at %q", ErrInvalidPartial, maxPartialDepth, next.name)
+		}
+
+		partial, err := load(next.name)
+		if err != nil {
+			return nil, nil, err
+		}
+		included, err := root.New(next.name).Parse(partial.Template)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: failed to parse partial %q: %v", ErrInvalidPartial, next.name, err)
+		}
+		used = append(used, PartialVersion{ID: partial.ID, Name: partial.Name, Version: partial.Version})
+
+		for _, ref := range templateReferences(included) {
+			queue = append(queue, pending{name: ref, depth: next.depth + 1})
+		}
+	}
+
+	sort.Slice(used, func(i, j int) bool { return used[i].Name < used[j].Name })
+	return root, used, nil
+}
+
+// templateReferences returns the names included with {{template}} by t and by
+// any templates it defines inline.
+func templateReferences(t *template.Template) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, defined := range t.Templates() {
+		if defined.Tree == nil || defined.Tree.Root == nil {
+			continue
+		}
+		collectReferences(defined.Tree.Root, seen, &names)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectReferences(node parse.Node, seen map[string]bool, names *[]string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectReferences(child, seen, names)
+		}
+	case *parse.TemplateNode:
+		if !seen[n.Name] {
+			seen[n.Name] = true
+			*names = append(*names, n.Name)
+		}
+	case *parse.IfNode:
+		collectReferences(n.List, seen, names)
+		collectReferences(n.ElseList, seen, names)
+	case *parse.RangeNode:
+		collectReferences(n.List, seen, names)
+		collectReferences(n.ElseList, seen, names)
+	case *parse.WithNode:
+		collectReferences(n.List, seen, names)
+		collectReferences(n.ElseList, seen, names)
+	}
+}
@@ -0,0 +1,76 @@
+package prompt_templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func partialLoader(partials map[string]string, loaded *[]string) PartialLoader {
+	return func(name string) (*PromptPartial, error) {
+		text, ok := partials[name]
+		if !ok {
+			return nil, fmt.Errorf("partial %q not found", name)
+		}
+		*loaded = append(*loaded, name)
+		return &PromptPartial{ID: uuid.New(), Name: name, Version: 2, Template: text}, nil
+	}
+}
+
+func TestCompose_ResolvesNestedPartials(t *testing.T) {
+	var loaded []string
+	load := partialLoader(map[string]string{
+		"grounding_rules": "- Use ONLY the documents.\n{{template \"format_rules\" .}}",
+		"format_rules":    "- Return {{.count}} items as JSON.",
+	}, &loaded)
+
+	tmpl, used, err := Compose("prompt", "Rules:\n{{if .count}}{{template \"grounding_rules\" .}}{{end}}", load)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, tmpl.Execute(&out, map[string]any{"count": 3}))
+	assert.Equal(t, "Rules:\n- Use ONLY the documents.\n- Return 3 items as JSON.", out.String())
+
+	require.Len(t, used, 2)
+	assert.Equal(t, "format_rules", used[0].Name)
+	assert.Equal(t, "grounding_rules", used[1].Name)
+	assert.Equal(t, int32(2), used[1].Version)
+	assert.Equal(t, []string{"grounding_rules", "format_rules"}, loaded)
+}
+
+func TestCompose_InlineDefinitionsAndCycles(t *testing.T) {
+	var loaded []string
+	load := partialLoader(map[string]string{
+		"a": "A{{if false}}{{template \"b\" .}}{{end}}",
+		"b": "B{{template \"a\" .}}",
+	}, &loaded)
+
+	_, used, err := Compose("prompt", `{{define "local"}}x{{end}}{{template "local" .}}{{template "a" .}}`, load)
+	require.NoError(t, err)
+	assert.Len(t, used, 2)
+	assert.Equal(t, []string{"a", "b"}, loaded)
+}
+
+func TestCompose_Errors(t *testing.T) {
+	var loaded []string
+	load := partialLoader(map[string]string{"broken": "{{if}}"}, &loaded)
+
+	_, _, err := Compose("prompt", `{{template "missing" .}}`, load)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `partial "missing" not found`)
+
+	_, _, err = Compose("prompt", `{{template "broken" .}}`, load)
+	assert.True(t, errors.Is(err, ErrInvalidPartial))
+}
+
+func TestValidatePartial(t *testing.T) {
+	assert.NoError(t, ValidatePartial("grounding_rules", "- Use the documents."))
+	assert.ErrorIs(t, ValidatePartial("Grounding-Rules", "text"), ErrInvalidPartial)
+	assert.ErrorIs(t, ValidatePartial("rules", ""), ErrInvalidPartial)
+	assert.ErrorIs(t, ValidatePartial("rules", "{{.unclosed"), ErrInvalidPartial)
+}
@@ -16,4 +16,20 @@ type Repository interface {
 	Activate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error)
 	Deactivate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error)
 	DeactivateOtherVersions(ctx context.Context, generationType string, id uuid.UUID) error
+
+	CreatePartialVersion(ctx context.Context, req CreatePromptPartialVersionRequest) (*PromptPartial, error)
+	GetPartialByID(ctx context.Context, id uuid.UUID) (*PromptPartial, error)
+	GetActivePartialByName(ctx context.Context, name string) (*PromptPartial, error)
+	GetPartialByNameAndVersion(ctx context.Context, name string, version int32) (*PromptPartial, error)
+	ListPartialVersions(ctx context.Context, name string) ([]*PromptPartial, error)
+	ListActivePartials(ctx context.Context) ([]*PromptPartial, error)
+	ActivatePartial(ctx context.Context, id uuid.UUID) (*PromptPartial, error)
+
+	CreateExperiment(ctx context.Context, req CreatePromptExperimentRequest, variants []ExperimentVariant) (*PromptExperiment, error)
+	GetExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	GetRunningExperiment(ctx context.Context, generationType string) (*PromptExperiment, error)
+	ListExperiments(ctx context.Context, generationType string) ([]*PromptExperiment, error)
+	StartExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	StopExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	GetExperimentReport(ctx context.Context, id uuid.UUID, evalType string) ([]VariantReport, error)
 }
@@ -125,6 +125,107 @@ func (r *RepositoryImpl) DeactivateOtherVersions(ctx context.Context, generation
 	return nil
 }
 
+// CreatePartialVersion creates a new prompt partial version.
+func (r *RepositoryImpl) CreatePartialVersion(ctx context.Context, req CreatePromptPartialVersionRequest) (*PromptPartial, error) {
+	row, err := r.queries.CreatePromptPartialVersion(ctx, store.CreatePromptPartialVersionParams{
+		Name:        req.Name,
+		IsActive:    req.IsActive,
+		Description: utils.SqlNullString(req.Description),
+		Template:    req.Template,
+		CreatedBy:   utils.SqlNullString(req.CreatedBy),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prompt partial version: %w", err)
+	}
+
+	return toDomainPromptPartial(store.PromptPartial(row)), nil
+}
+
+// GetPartialByID retrieves a prompt partial by ID.
+func (r *RepositoryImpl) GetPartialByID(ctx context.Context, id uuid.UUID) (*PromptPartial, error) {
+	partial, err := r.queries.GetPromptPartial(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt partial: %w", err)
+	}
+
+	return toDomainPromptPartial(partial), nil
+}
+
+// GetActivePartialByName retrieves the active version of a prompt partial.
+func (r *RepositoryImpl) GetActivePartialByName(ctx context.Context, name string) (*PromptPartial, error) {
+	partial, err := r.queries.GetActivePromptPartialByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active prompt partial: %w", err)
+	}
+
+	return toDomainPromptPartial(partial), nil
+}
+
+// GetPartialByNameAndVersion retrieves a prompt partial by name and version.
+func (r *RepositoryImpl) GetPartialByNameAndVersion(ctx context.Context, name string, version int32) (*PromptPartial, error) {
+	partial, err := r.queries.GetPromptPartialByNameAndVersion(ctx, store.GetPromptPartialByNameAndVersionParams{
+		Name:    name,
+		Version: version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt partial by name/version: %w", err)
+	}
+
+	return toDomainPromptPartial(partial), nil
+}
+
+// ListPartialVersions lists every version of a prompt partial, newest first.
+func (r *RepositoryImpl) ListPartialVersions(ctx context.Context, name string) ([]*PromptPartial, error) {
+	rows, err := r.queries.ListPromptPartialVersions(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt partial versions: %w", err)
+	}
+
+	return toDomainPromptPartials(rows), nil
+}
+
+// ListActivePartials lists the active version of every prompt partial.
+func (r *RepositoryImpl) ListActivePartials(ctx context.Context) ([]*PromptPartial, error) {
+	rows, err := r.queries.ListActivePromptPartials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active prompt partials: %w", err)
+	}
+
+	return toDomainPromptPartials(rows), nil
+}
+
+// ActivatePartial marks a prompt partial version as active.
+func (r *RepositoryImpl) ActivatePartial(ctx context.Context, id uuid.UUID) (*PromptPartial, error) {
+	row, err := r.queries.ActivatePromptPartial(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate prompt partial: %w", err)
+	}
+
+	return toDomainPromptPartial(store.PromptPartial(row)), nil
+}
+
+func toDomainPromptPartial(partial store.PromptPartial) *PromptPartial {
+	return &PromptPartial{
+		ID:          partial.ID,
+		Name:        partial.Name,
+		Version:     partial.Version,
+		IsActive:    partial.IsActive,
+		Description: utils.NullStringToPtr(partial.Description),
+		Template:    partial.Template,
+		CreatedBy:   utils.NullStringToPtr(partial.CreatedBy),
+		CreatedAt:   partial.CreatedAt,
+		UpdatedAt:   partial.UpdatedAt,
+	}
+}
+
+func toDomainPromptPartials(rows []store.PromptPartial) []*PromptPartial {
+	partials := make([]*PromptPartial, 0, len(rows))
+	for _, row := range rows {
+		partials = append(partials, toDomainPromptPartial(row))
+	}
+	return partials
+}
+
 func toDomainPromptTemplate(storeTemplate *store.PromptTemplate) *PromptTemplate {
 	var metadata json.RawMessage
 	if storeTemplate.Metadata.Valid {
@@ -140,6 +241,7 @@ func toDomainPromptTemplate(storeTemplate *store.PromptTemplate) *PromptTemplate
 		Description:    utils.NullStringToPtr(storeTemplate.Description),
 		Template:       storeTemplate.Template,
 		Metadata:       metadata,
+		Variables:      declaredVariables(metadata),
 		CreatedBy:      utils.NullStringToPtr(storeTemplate.CreatedBy),
 		CreatedAt:      storeTemplate.CreatedAt,
 		UpdatedAt:      storeTemplate.UpdatedAt,
@@ -221,8 +323,152 @@ func toDomainPromptTemplateFields(
 		Description:    utils.NullStringToPtr(description),
 		Template:       templateText,
 		Metadata:       metadata,
+		Variables:      declaredVariables(metadata),
 		CreatedBy:      utils.NullStringToPtr(createdBy),
 		CreatedAt:      createdAt,
 		UpdatedAt:      updatedAt,
 	}
 }
+
+// declaredVariables returns the variables declared in metadata. Declarations are
+// validated on write, so rows that fail to parse expose no variables.
+func declaredVariables(metadata json.RawMessage) []TemplateVariable {
+	variables, err := ParseVariables(metadata)
+	if err != nil || variables == nil {
+		return []TemplateVariable{}
+	}
+	return variables
+}
+
+// CreateExperiment creates a draft prompt experiment with its variants.
+func (r *RepositoryImpl) CreateExperiment(ctx context.Context, req CreatePromptExperimentRequest, variants []ExperimentVariant) (*PromptExperiment, error) {
+	templateIDs := make([]uuid.UUID, 0, len(variants))
+	weights := make([]int32, 0, len(variants))
+	for _, variant := range variants {
+		templateIDs = append(templateIDs, variant.PromptTemplateID)
+		weights = append(weights, variant.Weight)
+	}
+
+	row, err := r.queries.CreatePromptExperiment(ctx, store.CreatePromptExperimentParams{
+		Name:              req.Name,
+		Description:       utils.SqlNullString(req.Description),
+		GenerationType:    store.GenerationType(req.GenerationType),
+		CreatedBy:         utils.SqlNullString(req.CreatedBy),
+		PromptTemplateIds: templateIDs,
+		Weights:           weights,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prompt experiment: %w", err)
+	}
+
+	return r.withVariants(ctx, store.PromptExperiment(row))
+}
+
+// GetExperiment retrieves a prompt experiment with its variants.
+func (r *RepositoryImpl) GetExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	row, err := r.queries.GetPromptExperiment(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt experiment: %w", err)
+	}
+
+	return r.withVariants(ctx, row)
+}
+
+// GetRunningExperiment retrieves the running experiment for a generation type.
+func (r *RepositoryImpl) GetRunningExperiment(ctx context.Context, generationType string) (*PromptExperiment, error) {
+	row, err := r.queries.GetRunningPromptExperiment(ctx, store.GenerationType(generationType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running prompt experiment: %w", err)
+	}
+
+	return r.withVariants(ctx, row)
+}
+
+// ListExperiments lists prompt experiments, newest first, optionally for one generation type.
+func (r *RepositoryImpl) ListExperiments(ctx context.Context, generationType string) ([]*PromptExperiment, error) {
+	rows, err := r.queries.ListPromptExperiments(ctx, store.NullGenerationType{
+		GenerationType: store.GenerationType(generationType),
+		Valid:          generationType != "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt experiments: %w", err)
+	}
+
+	experiments := make([]*PromptExperiment, 0, len(rows))
+	for _, row := range rows {
+		experiment, err := r.withVariants(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, experiment)
+	}
+	return experiments, nil
+}
+
+// StartExperiment moves a draft experiment to running.
+func (r *RepositoryImpl) StartExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	row, err := r.queries.StartPromptExperiment(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start prompt experiment: %w", err)
+	}
+
+	return r.withVariants(ctx, row)
+}
+
+// StopExperiment moves a running experiment to stopped.
+func (r *RepositoryImpl) StopExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	row, err := r.queries.StopPromptExperiment(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop prompt experiment: %w", err)
+	}
+
+	return r.withVariants(ctx, row)
+}
+
+// GetExperimentReport counts each variant's artifacts and eval verdicts of evalType.
+func (r *RepositoryImpl) GetExperimentReport(ctx context.Context, id uuid.UUID, evalType string) ([]VariantReport, error) {
+	rows, err := r.queries.GetPromptExperimentReport(ctx, store.GetPromptExperimentReportParams{
+		ExperimentID: id,
+		EvalType:     evalType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt experiment report: %w", err)
+	}
+
+	reports := make([]VariantReport, 0, len(rows))
+	for _, row := range rows {
+		variant := ExperimentVariant{PromptTemplateID: row.PromptTemplateID, PromptVersion: row.PromptVersion, Weight: row.Weight}
+		reports = append(reports, newVariantReport(variant, row.ArtifactCount, row.UsableCount, row.EvaluatedCount, row.Passed, row.Failed, row.Warned, row.AvgScore))
+	}
+	return reports, nil
+}
+
+func (r *RepositoryImpl) withVariants(ctx context.Context, row store.PromptExperiment) (*PromptExperiment, error) {
+	rows, err := r.queries.ListPromptExperimentVariants(ctx, row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt experiment variants: %w", err)
+	}
+
+	variants := make([]ExperimentVariant, 0, len(rows))
+	for _, variant := range rows {
+		variants = append(variants, ExperimentVariant{
+			PromptTemplateID: variant.PromptTemplateID,
+			PromptVersion:    variant.PromptVersion,
+			Weight:           variant.Weight,
+		})
+	}
+
+	return &PromptExperiment{
+		ID:             row.ID,
+		Name:           row.Name,
+		Description:    utils.NullStringToPtr(row.Description),
+		GenerationType: string(row.GenerationType),
+		Status:         row.Status,
+		Variants:       variants,
+		CreatedBy:      utils.NullStringToPtr(row.CreatedBy),
+		StartedAt:      utils.NullTimeToPtr(row.StartedAt),
+		StoppedAt:      utils.NullTimeToPtr(row.StoppedAt),
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
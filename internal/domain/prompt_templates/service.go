@@ -2,8 +2,13 @@ package prompt_templates
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
+
+	"learning-core-api/internal/utils"
 )
 
 // Service defines business logic for prompt templates.
@@ -14,6 +19,18 @@ type Service interface {
 	CreateVersion(ctx context.Context, req CreatePromptTemplateVersionRequest) (*PromptTemplate, error)
 	Activate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error)
 	Deactivate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error)
+
+	CreatePartialVersion(ctx context.Context, req CreatePromptPartialVersionRequest) (*PromptPartial, error)
+	ListActivePartials(ctx context.Context) ([]*PromptPartial, error)
+	ListPartialVersions(ctx context.Context, name string) ([]*PromptPartial, error)
+	ActivatePartial(ctx context.Context, id uuid.UUID) (*PromptPartial, error)
+
+	CreateExperiment(ctx context.Context, req CreatePromptExperimentRequest) (*PromptExperiment, error)
+	GetExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	ListExperiments(ctx context.Context, generationType string) ([]*PromptExperiment, error)
+	StartExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	StopExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error)
+	GetExperimentReport(ctx context.Context, id uuid.UUID, evalType string) (*ExperimentReport, error)
 }
 
 // ServiceImpl implements Service.
@@ -48,10 +65,41 @@ func (s *ServiceImpl) ListByGenerationType(ctx context.Context, generationType s
 }
 
 // CreateVersion creates a new version of a prompt template.
+// The generation type and variable declarations in metadata are validated first.
 func (s *ServiceImpl) CreateVersion(ctx context.Context, req CreatePromptTemplateVersionRequest) (*PromptTemplate, error) {
+	if !utils.GenerationType(req.GenerationType).IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGenerationType, req.GenerationType)
+	}
+	if _, err := ParseVariables(req.Metadata); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateVersion(ctx, req)
 }
 
+// CreatePartialVersion creates a new version of a prompt partial.
+// The name and template syntax are validated first.
+func (s *ServiceImpl) CreatePartialVersion(ctx context.Context, req CreatePromptPartialVersionRequest) (*PromptPartial, error) {
+	if err := ValidatePartial(req.Name, req.Template); err != nil {
+		return nil, err
+	}
+	return s.repo.CreatePartialVersion(ctx, req)
+}
+
+// ListActivePartials lists the active version of every prompt partial.
+func (s *ServiceImpl) ListActivePartials(ctx context.Context) ([]*PromptPartial, error) {
+	return s.repo.ListActivePartials(ctx)
+}
+
+// ListPartialVersions lists every version of a prompt partial.
+func (s *ServiceImpl) ListPartialVersions(ctx context.Context, name string) ([]*PromptPartial, error) {
+	return s.repo.ListPartialVersions(ctx, name)
+}
+
+// ActivatePartial marks a prompt partial version as active.
+func (s *ServiceImpl) ActivatePartial(ctx context.Context, id uuid.UUID) (*PromptPartial, error) {
+	return s.repo.ActivatePartial(ctx, id)
+}
+
 // Activate marks a prompt template as active.
 func (s *ServiceImpl) Activate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error) {
 	return s.repo.Activate(ctx, id)
@@ -61,3 +109,119 @@ func (s *ServiceImpl) Activate(ctx context.Context, id uuid.UUID) (*PromptTempla
 func (s *ServiceImpl) Deactivate(ctx context.Context, id uuid.UUID) (*PromptTemplate, error) {
 	return s.repo.Deactivate(ctx, id)
 }
+
+// CreateExperiment creates a draft experiment over prompt template versions of
+// one generation type. Every version must exist for that generation type.
+func (s *ServiceImpl) CreateExperiment(ctx context.Context, req CreatePromptExperimentRequest) (*PromptExperiment, error) {
+	if !utils.GenerationType(req.GenerationType).IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGenerationType, req.GenerationType)
+	}
+	if err := ValidateExperiment(req); err != nil {
+		return nil, err
+	}
+
+	variants := make([]ExperimentVariant, 0, len(req.Variants))
+	for _, variant := range req.Variants {
+		tmpl, err := s.repo.GetByGenerationTypeAndVersion(ctx, req.GenerationType, variant.PromptVersion)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: prompt template %q version %d not found", ErrInvalidExperiment, req.GenerationType, variant.PromptVersion)
+			}
+			return nil, err
+		}
+		variants = append(variants, ExperimentVariant{
+			PromptTemplateID: tmpl.ID,
+			PromptVersion:    tmpl.Version,
+			Weight:           variant.Weight,
+		})
+	}
+	return s.repo.CreateExperiment(ctx, req, variants)
+}
+
+// GetExperiment retrieves a prompt experiment by ID.
+func (s *ServiceImpl) GetExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	experiment, err := s.repo.GetExperiment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExperimentNotFound
+		}
+		return nil, err
+	}
+	return experiment, nil
+}
+
+// ListExperiments lists prompt experiments, optionally for one generation type.
+func (s *ServiceImpl) ListExperiments(ctx context.Context, generationType string) ([]*PromptExperiment, error) {
+	if generationType != "" && !utils.GenerationType(generationType).IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGenerationType, generationType)
+	}
+	return s.repo.ListExperiments(ctx, generationType)
+}
+
+// StartExperiment starts splitting traffic for a draft experiment. Only one
+// experiment per generation type may run at a time.
+func (s *ServiceImpl) StartExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	experiment, err := s.GetExperiment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status != ExperimentStatusDraft {
+		return nil, fmt.Errorf("%w: experiment is %s, only drafts can be started", ErrExperimentConflict, experiment.Status)
+	}
+
+	running, err := s.repo.GetRunningExperiment(ctx, experiment.GenerationType)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("%w: experiment %s is already running for %s", ErrExperimentConflict, running.ID, experiment.GenerationType)
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	started, err := s.repo.StartExperiment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: experiment was changed concurrently", ErrExperimentConflict)
+		}
+		return nil, err
+	}
+	return started, nil
+}
+
+// StopExperiment stops a running experiment. Generations go back to the
+// active prompt template; the experiment's artifacts stay in its report.
+func (s *ServiceImpl) StopExperiment(ctx context.Context, id uuid.UUID) (*PromptExperiment, error) {
+	experiment, err := s.GetExperiment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status != ExperimentStatusRunning {
+		return nil, fmt.Errorf("%w: experiment is %s, only running experiments can be stopped", ErrExperimentConflict, experiment.Status)
+	}
+
+	stopped, err := s.repo.StopExperiment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: experiment was changed concurrently", ErrExperimentConflict)
+		}
+		return nil, err
+	}
+	return stopped, nil
+}
+
+// GetExperimentReport compares the latest evalType verdicts of the eval items
+// generated from each variant's artifacts. evalType defaults to groundedness.
+func (s *ServiceImpl) GetExperimentReport(ctx context.Context, id uuid.UUID, evalType string) (*ExperimentReport, error) {
+	if evalType == "" {
+		evalType = DefaultReportEvalType
+	}
+	experiment, err := s.GetExperiment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repo.GetExperimentReport(ctx, id, evalType)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentReport{Experiment: experiment, EvalType: evalType, Variants: variants}, nil
+}
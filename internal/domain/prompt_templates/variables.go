@@ -0,0 +1,175 @@
+package prompt_templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Supported template variable types.
+const (
+	VariableTypeString  = "string"
+	VariableTypeInteger = "integer"
+	VariableTypeNumber  = "number"
+	VariableTypeBoolean = "boolean"
+	VariableTypeArray   = "array"
+	VariableTypeObject  = "object"
+)
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// TemplateVariable declares a variable a prompt template expects. Declarations
+// live under the "variables" key of prompt_templates.metadata.
+// @Description Variable declared by a prompt template
+type TemplateVariable struct {
+	Name        string `json:"name" example:"question_count"`
+	Type        string `json:"type" example:"integer"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty" swaggertype:"object"`
+	Description string `json:"description,omitempty" example:"Number of questions to generate"`
+}
+
+type templateMetadata struct {
+	Variables []TemplateVariable `json:"variables"`
+}
+
+// ParseVariables reads and validates the variable declarations in template metadata.
+// Metadata without a "variables" key declares nothing.
+func ParseVariables(metadata json.RawMessage) ([]TemplateVariable, error) {
+	if len(metadata) == 0 || string(metadata) == "null" {
+		return nil, nil
+	}
+
+	var meta templateMetadata
+	if err := json.Unmarshal(metadata, &meta); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVariableDeclaration, err)
+	}
+
+	seen := make(map[string]bool, len(meta.Variables))
+	for i, variable := range meta.Variables {
+		if !variableNamePattern.MatchString(variable.Name) {
+			return nil, fmt.Errorf("%w: variables[%d] has invalid name %q", ErrInvalidVariableDeclaration, i, variable.Name)
+		}
+		if seen[variable.Name] {
+			return nil, fmt.Errorf("%w: variable %q is declared more than once", ErrInvalidVariableDeclaration, variable.Name)
+		}
+		seen[variable.Name] = true
+
+		if variable.Type == "" {
+			meta.Variables[i].Type = VariableTypeString
+			variable.Type = VariableTypeString
+		}
+		if !isKnownVariableType(variable.Type) {
+			return nil, fmt.Errorf("%w: variable %q has unsupported type %q", ErrInvalidVariableDeclaration, variable.Name, variable.Type)
+		}
+		if variable.Default != nil && !matchesVariableType(variable.Type, variable.Default) {
+			return nil, fmt.Errorf("%w: default for variable %q is not a %s", ErrInvalidVariableDeclaration, variable.Name, variable.Type)
+		}
+	}
+
+	return meta.Variables, nil
+}
+
+// ResolveVariables checks values against the declarations and fills in defaults.
+// Values for undeclared variables are passed through unchanged. All problems are
+// reported together in a single ErrInvalidVariables error.
+func ResolveVariables(declared []TemplateVariable, values map[string]any) (map[string]any, error) {
+	resolved := make(map[string]any, len(values)+len(declared))
+	for name, value := range values {
+		resolved[name] = value
+	}
+
+	var problems []string
+	for _, variable := range declared {
+		value, ok := values[variable.Name]
+		if !ok || value == nil {
+			switch {
+			case variable.Default != nil:
+				resolved[variable.Name] = variable.Default
+			case variable.Required:
+				problems = append(problems, fmt.Sprintf("%s: required %s variable is missing", variable.Name, variable.Type))
+			}
+			continue
+		}
+		if !matchesVariableType(variable.Type, value) {
+			problems = append(problems, fmt.Sprintf("%s: expected %s, got %s", variable.Name, variable.Type, variableTypeName(value)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidVariables, strings.Join(problems, "; "))
+	}
+	return resolved, nil
+}
+
+func isKnownVariableType(variableType string) bool {
+	switch variableType {
+	case VariableTypeString, VariableTypeInteger, VariableTypeNumber, VariableTypeBoolean, VariableTypeArray, VariableTypeObject:
+		return true
+	}
+	return false
+}
+
+func matchesVariableType(variableType string, value any) bool {
+	switch variableType {
+	case VariableTypeString:
+		_, ok := value.(string)
+		return ok
+	case VariableTypeInteger:
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	case VariableTypeNumber:
+		_, ok := toFloat(value)
+		return ok
+	case VariableTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case VariableTypeArray:
+		_, ok := value.([]any)
+		return ok
+	case VariableTypeObject:
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+// toFloat accepts the numeric types produced by encoding/json as well as Go
+// integers passed by internal callers.
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func variableTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return VariableTypeString
+	case bool:
+		return VariableTypeBoolean
+	case []any:
+		return VariableTypeArray
+	case map[string]any:
+		return VariableTypeObject
+	}
+	if _, ok := toFloat(value); ok {
+		return VariableTypeNumber
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,61 @@
+package prompt_templates
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariables(t *testing.T) {
+	variables, err := ParseVariables(json.RawMessage(`{"variables": [
+		{"name": "question_count", "type": "integer", "default": 5},
+		{"name": "topic", "required": true}
+	], "owner": "content"}`))
+	require.NoError(t, err)
+	require.Len(t, variables, 2)
+	assert.Equal(t, VariableTypeInteger, variables[0].Type)
+	assert.Equal(t, VariableTypeString, variables[1].Type)
+	assert.True(t, variables[1].Required)
+
+	none, err := ParseVariables(json.RawMessage(`{"owner": "content"}`))
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	for name, metadata := range map[string]string{
+		"bad name":      `{"variables": [{"name": "question count"}]}`,
+		"duplicate":     `{"variables": [{"name": "a"}, {"name": "a"}]}`,
+		"unknown type":  `{"variables": [{"name": "a", "type": "date"}]}`,
+		"bad default":   `{"variables": [{"name": "a", "type": "integer", "default": "five"}]}`,
+		"not an object": `["a"]`,
+	} {
+		_, err := ParseVariables(json.RawMessage(metadata))
+		assert.ErrorIs(t, err, ErrInvalidVariableDeclaration, name)
+	}
+}
+
+func TestResolveVariables(t *testing.T) {
+	declared := []TemplateVariable{
+		{Name: "question_count", Type: VariableTypeInteger, Default: float64(5)},
+		{Name: "topic", Type: VariableTypeString, Required: true},
+		{Name: "include_hints", Type: VariableTypeBoolean},
+	}
+
+	resolved, err := ResolveVariables(declared, map[string]any{"topic": "Photosynthesis", "extra": "kept"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), resolved["question_count"])
+	assert.Equal(t, "Photosynthesis", resolved["topic"])
+	assert.Equal(t, "kept", resolved["extra"])
+	assert.NotContains(t, resolved, "include_hints")
+
+	_, err = ResolveVariables(declared, map[string]any{"question_count": 2.5, "include_hints": "yes"})
+	require.ErrorIs(t, err, ErrInvalidVariables)
+	assert.Contains(t, err.Error(), "question_count: expected integer, got number")
+	assert.Contains(t, err.Error(), "topic: required string variable is missing")
+	assert.Contains(t, err.Error(), "include_hints: expected boolean, got string")
+
+	resolved, err = ResolveVariables(declared, map[string]any{"topic": "Cells", "question_count": 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resolved["question_count"])
+}
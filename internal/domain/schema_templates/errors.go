@@ -0,0 +1,8 @@
+package schema_templates
+
+import "errors"
+
+// Domain-specific errors for schema templates
+var (
+	ErrInvalidGenerationType = errors.New("invalid generation type")
+)
@@ -1,6 +1,7 @@
 package schema_templates
 
 import (
+	"errors"
 	"net/http"
 
 	"learning-core-api/internal/http/authz"
@@ -45,7 +46,7 @@ func (h *Handler) RegisterLearnerRoutes(r chi.Router) {
 // @Description Get all schema templates for a specific generation type
 // @Tags Schema Templates
 // @Security OAuth2[read]
-// @Param generation_type query string true "Generation type (CLASSIFICATION, QUESTIONS, or SECTION_TOPICS)"
+// @Param generation_type query string true "Generation type (CLASSIFICATION, QUESTIONS, SECTION_TOPICS, FLASHCARDS, SUMMARY, OUTLINE, HINT or MULTIPLE_CHOICE)"
 // @Success 200 {array} SchemaTemplate "List of schema templates"
 // @Failure 400 {object} map[string]string "Bad request - missing generation_type"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -100,7 +101,7 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve the currently active schema template for a specific generation type
 // @Tags Schema Templates
 // @Security OAuth2[read]
-// @Param generationType path string true "Generation type (CLASSIFICATION, QUESTIONS, or SECTION_TOPICS)"
+// @Param generationType path string true "Generation type (CLASSIFICATION, QUESTIONS, SECTION_TOPICS, FLASHCARDS, SUMMARY, OUTLINE, HINT or MULTIPLE_CHOICE)"
 // @Success 200 {object} SchemaTemplate "Active schema template"
 // @Failure 400 {object} map[string]string "Bad request - missing generation_type"
 // @Failure 404 {object} map[string]string "Active template not found"
@@ -130,7 +131,7 @@ func (h *Handler) GetActiveByGenerationType(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Param request body CreateSchemaTemplateRequest true "Schema template request"
 // @Success 201 {object} SchemaTemplate "Created schema template"
-// @Failure 400 {object} map[string]string "Bad request - invalid request body"
+// @Failure 400 {object} map[string]string "Bad request - invalid request body or generation type"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /schema-templates [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -143,6 +144,10 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	template, err := h.service.Create(ctx, req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidGenerationType) {
+			render.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		render.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
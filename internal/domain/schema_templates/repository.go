@@ -11,6 +11,7 @@ type Repository interface {
 	Create(ctx context.Context, req CreateSchemaTemplateRequest) (*SchemaTemplate, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*SchemaTemplate, error)
 	GetActiveByGenerationType(ctx context.Context, generationType string) (*SchemaTemplate, error)
+	GetByGenerationTypeAndVersion(ctx context.Context, generationType string, version int32) (*SchemaTemplate, error)
 	ListByGenerationType(ctx context.Context, generationType string) ([]*SchemaTemplate, error)
 	ListActive(ctx context.Context) ([]*SchemaTemplate, error)
 	Activate(ctx context.Context, id uuid.UUID) (*SchemaTemplate, error)
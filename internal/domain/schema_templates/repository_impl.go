@@ -61,6 +61,19 @@ func (r *RepositoryImpl) GetActiveByGenerationType(ctx context.Context, generati
 	return toDomainSchemaTemplate(&storeTemplate), nil
 }
 
+// GetByGenerationTypeAndVersion retrieves a schema template by generation type and version.
+func (r *RepositoryImpl) GetByGenerationTypeAndVersion(ctx context.Context, generationType string, version int32) (*SchemaTemplate, error) {
+	storeTemplate, err := r.queries.GetSchemaTemplateByGenerationTypeAndVersion(ctx, store.GetSchemaTemplateByGenerationTypeAndVersionParams{
+		GenerationType: store.GenerationType(generationType),
+		Version:        version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema template by generation_type/version: %w", err)
+	}
+
+	return toDomainSchemaTemplate(&storeTemplate), nil
+}
+
 // ListByGenerationType lists schema templates by generation type.
 func (r *RepositoryImpl) ListByGenerationType(ctx context.Context, generationType string) ([]*SchemaTemplate, error) {
 	storeTemplates, err := r.queries.ListSchemaTemplatesByGenerationType(ctx, store.GenerationType(generationType))
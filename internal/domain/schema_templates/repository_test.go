@@ -94,3 +94,38 @@ func TestSchemaTemplateRepository_CreateActivate(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, secondReload.IsActive)
 }
+
+func TestSchemaTemplateRepository_PinnedVersionLocksOnUse(t *testing.T) {
+	db, _, repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := createTestUser(t, db)
+	inactive := false
+	generationType := utils.GenerationTypeQuestions.String()
+
+	created, err := repo.Create(ctx, CreateSchemaTemplateRequest{
+		GenerationType: generationType,
+		SchemaJSON:     []byte(`{"type":"object"}`),
+		IsActive:       &inactive,
+		CreatedBy:      userID,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, created.LockedAt)
+
+	pinned, err := repo.GetByGenerationTypeAndVersion(ctx, generationType, created.Version)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, pinned.ID)
+
+	_, err = repo.GetByGenerationTypeAndVersion(ctx, generationType, created.Version+100)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = db.ExecContext(ctx,
+		"INSERT INTO artifacts (type, status, schema_template_id) VALUES ('OTHER', 'READY', $1)",
+		created.ID)
+	require.NoError(t, err)
+
+	locked, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, locked.LockedAt)
+}
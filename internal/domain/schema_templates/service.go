@@ -2,14 +2,18 @@ package schema_templates
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
+
+	"learning-core-api/internal/utils"
 )
 
 // Service defines business logic for schema templates.
 type Service interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*SchemaTemplate, error)
 	GetActiveByGenerationType(ctx context.Context, generationType string) (*SchemaTemplate, error)
+	GetByGenerationTypeAndVersion(ctx context.Context, generationType string, version int32) (*SchemaTemplate, error)
 	ListByGenerationType(ctx context.Context, generationType string) ([]*SchemaTemplate, error)
 	Create(ctx context.Context, req CreateSchemaTemplateRequest) (*SchemaTemplate, error)
 	Activate(ctx context.Context, id uuid.UUID) (*SchemaTemplate, error)
@@ -35,6 +39,11 @@ func (s *ServiceImpl) GetActiveByGenerationType(ctx context.Context, generationT
 	return s.repo.GetActiveByGenerationType(ctx, generationType)
 }
 
+// GetByGenerationTypeAndVersion retrieves a specific schema template version.
+func (s *ServiceImpl) GetByGenerationTypeAndVersion(ctx context.Context, generationType string, version int32) (*SchemaTemplate, error) {
+	return s.repo.GetByGenerationTypeAndVersion(ctx, generationType, version)
+}
+
 // ListByGenerationType lists schema templates by generation type.
 func (s *ServiceImpl) ListByGenerationType(ctx context.Context, generationType string) ([]*SchemaTemplate, error) {
 	return s.repo.ListByGenerationType(ctx, generationType)
@@ -42,6 +51,9 @@ func (s *ServiceImpl) ListByGenerationType(ctx context.Context, generationType s
 
 // Create creates a new schema template.
 func (s *ServiceImpl) Create(ctx context.Context, req CreateSchemaTemplateRequest) (*SchemaTemplate, error) {
+	if !utils.GenerationType(req.GenerationType).IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGenerationType, req.GenerationType)
+	}
 	return s.repo.Create(ctx, req)
 }
 
@@ -0,0 +1,16 @@
+package tutoring
+
+import "errors"
+
+// Domain errors for tutoring
+var (
+	ErrThreadNotFound        = errors.New("tutoring thread not found")
+	ErrInvalidUserID         = errors.New("invalid user ID")
+	ErrInvalidDocumentID     = errors.New("document_id is required")
+	ErrDocumentNotFound      = errors.New("document not found")
+	ErrTitleTooLong          = errors.New("thread title too long")
+	ErrEmptyMessage          = errors.New("message content is required")
+	ErrMessageTooLong        = errors.New("message content too long")
+	ErrReplyFailed           = errors.New("tutor reply could not be generated")
+	ErrGenerationUnavailable = errors.New("generation service unavailable")
+)
@@ -0,0 +1,211 @@
+package tutoring
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/domain/generation"
+	httpPkg "learning-core-api/internal/http"
+	"learning-core-api/internal/http/authz"
+	"learning-core-api/internal/http/render"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterPublicRoutes(r chi.Router) {}
+
+func (h *Handler) RegisterAdminRoutes(r chi.Router) {}
+
+func (h *Handler) RegisterTeacherRoutes(r chi.Router) {}
+
+func (h *Handler) RegisterLearnerRoutes(r chi.Router) {
+	r.With(authz.RequireScope("write")).Post("/tutoring/threads", h.CreateThread)
+	r.With(authz.RequireScope("read")).Get("/tutoring/threads", h.ListThreads)
+	r.With(authz.RequireScope("read")).Get("/tutoring/threads/{id}", h.GetThread)
+	r.With(authz.RequireScope("write")).Post("/tutoring/threads/{id}/messages", h.Ask)
+}
+
+// CreateThread starts a tutoring thread about a document.
+// @Summary Start a tutoring thread
+// @Description Learner-only. Starts a conversation about a document. The title defaults to the document title and the model to the active model config.
+// @Tags Tutoring
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param request body CreateThreadRequest true "Thread to create"
+// @Success 201 {object} Thread "Created thread"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tutoring/threads [post]
+func (h *Handler) CreateThread(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req CreateThreadRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	thread, err := h.service.CreateThread(r.Context(), userID, req)
+	if err != nil {
+		render.Error(w, errorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, thread)
+}
+
+// ListThreads lists the requesting learner's tutoring threads.
+// @Summary List tutoring threads
+// @Description Learner-only. Lists the learner's threads, most recently active first.
+// @Tags Tutoring
+// @Security OAuth2[read]
+// @Produce json
+// @Param document_id query string false "Only threads about this document"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20, max: 100)"
+// @Success 200 {array} Thread "Threads"
+// @Failure 400 {object} map[string]string "Bad request - invalid document ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tutoring/threads [get]
+func (h *Handler) ListThreads(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var documentID *uuid.UUID
+	if raw := r.URL.Query().Get("document_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			render.Error(w, http.StatusBadRequest, "Invalid document ID")
+			return
+		}
+		documentID = &parsed
+	}
+
+	pagination := httpPkg.GetPaginationParams(r)
+	threads, err := h.service.ListThreads(r.Context(), userID, documentID, int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		render.Error(w, errorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, threads)
+}
+
+// GetThread returns a tutoring thread with its messages.
+// @Summary Get a tutoring thread
+// @Description Learner-only. Returns one of the learner's threads with all of its messages in order.
+// @Tags Tutoring
+// @Security OAuth2[read]
+// @Produce json
+// @Param id path string true "Thread ID"
+// @Success 200 {object} ThreadDetail "Thread and messages"
+// @Failure 400 {object} map[string]string "Bad request - invalid ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Thread not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tutoring/threads/{id} [get]
+func (h *Handler) GetThread(w http.ResponseWriter, r *http.Request) {
+	threadID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	detail, err := h.service.GetThread(r.Context(), threadID, userID)
+	if err != nil {
+		render.Error(w, errorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusOK, detail)
+}
+
+// Ask sends a follow-up question to a tutoring thread.
+// @Summary Ask a follow-up question
+// @Description Learner-only. Sends a message to the thread and returns the tutor's reply, grounded in the thread's document with file_search and graph_rag retrieval. Older messages are summarised once the history grows long. The reply is saved as an artifact linked to the thread.
+// @Tags Tutoring
+// @Security OAuth2[write]
+// @Accept json
+// @Produce json
+// @Param id path string true "Thread ID"
+// @Param request body AskRequest true "Learner message"
+// @Success 201 {object} AskResponse "Stored message and reply"
+// @Failure 400 {object} map[string]string "Bad request - invalid payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Thread not found"
+// @Failure 502 {object} map[string]string "Reply could not be generated"
+// @Failure 503 {object} map[string]string "Generation service unavailable"
+// @Router /tutoring/threads/{id}/messages [post]
+func (h *Handler) Ask(w http.ResponseWriter, r *http.Request) {
+	threadID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+	userID, err := uuid.Parse(authz.UserIDFromContext(r.Context()))
+	if err != nil {
+		render.Error(w, http.StatusUnauthorized, "Invalid user ID in token")
+		return
+	}
+
+	var req AskRequest
+	if err := render.DecodeJSON(r, &req); err != nil {
+		render.Error(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	resp, err := h.service.Ask(r.Context(), threadID, userID, req)
+	if err != nil {
+		render.Error(w, errorStatus(err), err.Error())
+		return
+	}
+
+	render.JSON(w, http.StatusCreated, resp)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrThreadNotFound), errors.Is(err, ErrDocumentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidDocumentID),
+		errors.Is(err, ErrTitleTooLong),
+		errors.Is(err, ErrEmptyMessage),
+		errors.Is(err, ErrMessageTooLong):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidUserID):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrReplyFailed), errors.Is(err, generation.ErrGenerationFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, ErrGenerationUnavailable), errors.Is(err, generation.ErrGeneratorUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, generation.ErrInvalidRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
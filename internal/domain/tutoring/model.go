@@ -0,0 +1,71 @@
+package tutoring
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+const (
+	maxTitleLength   = 255
+	maxMessageLength = 4000
+)
+
+// Thread is a tutoring conversation between a learner and the model about one
+// document.
+type Thread struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	DocumentID    uuid.UUID  `json:"document_id"`
+	Title         string     `json:"title"`
+	ModelConfigID *uuid.UUID `json:"model_config_id,omitempty"`
+	// Summary covers the messages up to and including SummarizedThrough; later
+	// messages are sent to the model verbatim.
+	Summary           *string   `json:"summary,omitempty"`
+	SummarizedThrough int32     `json:"summarized_through"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Message is one turn of a thread. Assistant messages link the artifact that
+// produced them.
+type Message struct {
+	ID         uuid.UUID  `json:"id"`
+	ThreadID   uuid.UUID  `json:"thread_id"`
+	Seq        int32      `json:"seq"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ArtifactID *uuid.UUID `json:"artifact_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateThreadRequest starts a thread about a document. Title defaults to the
+// document title; ModelConfigID defaults to the active model config.
+type CreateThreadRequest struct {
+	DocumentID    uuid.UUID  `json:"document_id"`
+	Title         *string    `json:"title,omitempty"`
+	ModelConfigID *uuid.UUID `json:"model_config_id,omitempty"`
+}
+
+// ThreadDetail is a thread with all of its messages.
+type ThreadDetail struct {
+	Thread   *Thread    `json:"thread"`
+	Messages []*Message `json:"messages"`
+}
+
+// AskRequest is a learner message sent to a thread.
+type AskRequest struct {
+	Content string `json:"content"`
+}
+
+// AskResponse holds the stored learner message and the tutor's reply.
+type AskResponse struct {
+	UserMessage      *Message `json:"user_message"`
+	AssistantMessage *Message `json:"assistant_message"`
+}
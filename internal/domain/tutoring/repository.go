@@ -0,0 +1,31 @@
+package tutoring
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for tutoring thread data operations
+type Repository interface {
+	// CreateThread creates a new thread
+	CreateThread(ctx context.Context, userID uuid.UUID, documentID uuid.UUID, title string, modelConfigID *uuid.UUID) (*Thread, error)
+
+	// GetThread retrieves a thread by ID; it returns nil when none exists
+	GetThread(ctx context.Context, id uuid.UUID) (*Thread, error)
+
+	// ListThreads retrieves a user's threads, most recently active first
+	ListThreads(ctx context.Context, userID uuid.UUID, documentID *uuid.UUID, limit, offset int32) ([]*Thread, error)
+
+	// UpdateSummary replaces the thread summary and the last message it covers
+	UpdateSummary(ctx context.Context, threadID uuid.UUID, summary string, summarizedThrough int32) (*Thread, error)
+
+	// ListMessages retrieves all messages of a thread in order
+	ListMessages(ctx context.Context, threadID uuid.UUID) ([]*Message, error)
+
+	// ListMessagesAfter retrieves the messages following seq in order
+	ListMessagesAfter(ctx context.Context, threadID uuid.UUID, seq int32) ([]*Message, error)
+
+	// AppendExchange stores a learner message and the reply to it atomically
+	AppendExchange(ctx context.Context, threadID uuid.UUID, question, answer string, artifactID uuid.UUID) (*Message, *Message, error)
+}
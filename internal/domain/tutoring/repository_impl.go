@@ -0,0 +1,187 @@
+package tutoring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/utils"
+)
+
+// RepositoryImpl implements the Repository interface using SQLC
+type RepositoryImpl struct {
+	db      *sql.DB
+	queries *store.Queries
+}
+
+// NewRepository creates a new tutoring repository
+func NewRepository(db *sql.DB) Repository {
+	return &RepositoryImpl{
+		db:      db,
+		queries: store.New(db),
+	}
+}
+
+// CreateThread creates a new thread
+func (r *RepositoryImpl) CreateThread(ctx context.Context, userID uuid.UUID, documentID uuid.UUID, title string, modelConfigID *uuid.UUID) (*Thread, error) {
+	thread, err := r.queries.CreateTutoringThread(ctx, store.CreateTutoringThreadParams{
+		UserID:        userID,
+		DocumentID:    documentID,
+		Title:         title,
+		ModelConfigID: utils.PtrToNullUUID(modelConfigID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tutoring thread: %w", err)
+	}
+	return toDomainThread(thread), nil
+}
+
+// GetThread retrieves a thread by ID
+func (r *RepositoryImpl) GetThread(ctx context.Context, id uuid.UUID) (*Thread, error) {
+	thread, err := r.queries.GetTutoringThread(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tutoring thread: %w", err)
+	}
+	return toDomainThread(thread), nil
+}
+
+// ListThreads retrieves a user's threads, most recently active first
+func (r *RepositoryImpl) ListThreads(ctx context.Context, userID uuid.UUID, documentID *uuid.UUID, limit, offset int32) ([]*Thread, error) {
+	threads, err := r.queries.ListTutoringThreadsByUser(ctx, store.ListTutoringThreadsByUserParams{
+		UserID:     userID,
+		DocumentID: utils.PtrToNullUUID(documentID),
+		PageLimit:  limit,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tutoring threads: %w", err)
+	}
+
+	mapped := make([]*Thread, len(threads))
+	for i, thread := range threads {
+		mapped[i] = toDomainThread(thread)
+	}
+	return mapped, nil
+}
+
+// UpdateSummary replaces the thread summary and the last message it covers
+func (r *RepositoryImpl) UpdateSummary(ctx context.Context, threadID uuid.UUID, summary string, summarizedThrough int32) (*Thread, error) {
+	thread, err := r.queries.UpdateTutoringThreadSummary(ctx, store.UpdateTutoringThreadSummaryParams{
+		ID:                threadID,
+		Summary:           utils.ToNullString(summary),
+		SummarizedThrough: summarizedThrough,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tutoring thread summary: %w", err)
+	}
+	return toDomainThread(thread), nil
+}
+
+// ListMessages retrieves all messages of a thread in order
+func (r *RepositoryImpl) ListMessages(ctx context.Context, threadID uuid.UUID) ([]*Message, error) {
+	messages, err := r.queries.ListTutoringMessages(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tutoring messages: %w", err)
+	}
+	return toDomainMessages(messages), nil
+}
+
+// ListMessagesAfter retrieves the messages following seq in order
+func (r *RepositoryImpl) ListMessagesAfter(ctx context.Context, threadID uuid.UUID, seq int32) ([]*Message, error) {
+	messages, err := r.queries.ListTutoringMessagesAfter(ctx, store.ListTutoringMessagesAfterParams{
+		ThreadID: threadID,
+		AfterSeq: seq,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tutoring messages: %w", err)
+	}
+	return toDomainMessages(messages), nil
+}
+
+// AppendExchange stores a learner message and the reply to it atomically
+func (r *RepositoryImpl) AppendExchange(ctx context.Context, threadID uuid.UUID, question, answer string, artifactID uuid.UUID) (*Message, *Message, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	q := r.queries.WithTx(tx)
+
+	// Messages take the next seq in the thread, so concurrent exchanges on the
+	// same thread wait for each other.
+	if _, err := q.LockTutoringThread(ctx, threadID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock tutoring thread: %w", err)
+	}
+	userMessage, err := q.CreateTutoringMessage(ctx, store.CreateTutoringMessageParams{
+		ThreadID: threadID,
+		Role:     RoleUser,
+		Content:  question,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create user message: %w", err)
+	}
+	assistantMessage, err := q.CreateTutoringMessage(ctx, store.CreateTutoringMessageParams{
+		ThreadID:   threadID,
+		Role:       RoleAssistant,
+		Content:    answer,
+		ArtifactID: uuid.NullUUID{UUID: artifactID, Valid: artifactID != uuid.Nil},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create assistant message: %w", err)
+	}
+	if err := q.TouchTutoringThread(ctx, threadID); err != nil {
+		return nil, nil, fmt.Errorf("failed to touch tutoring thread: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit tutoring messages: %w", err)
+	}
+	return toDomainMessage(userMessage), toDomainMessage(assistantMessage), nil
+}
+
+func toDomainThread(thread store.TutoringThread) *Thread {
+	domainThread := &Thread{
+		ID:                thread.ID,
+		UserID:            thread.UserID,
+		DocumentID:        thread.DocumentID,
+		Title:             thread.Title,
+		Summary:           utils.NullStringToPtr(thread.Summary),
+		SummarizedThrough: thread.SummarizedThrough,
+		CreatedAt:         thread.CreatedAt,
+		UpdatedAt:         thread.UpdatedAt,
+	}
+	if thread.ModelConfigID.Valid {
+		domainThread.ModelConfigID = &thread.ModelConfigID.UUID
+	}
+	return domainThread
+}
+
+func toDomainMessage(message store.TutoringMessage) *Message {
+	domainMessage := &Message{
+		ID:        message.ID,
+		ThreadID:  message.ThreadID,
+		Seq:       message.Seq,
+		Role:      message.Role,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+	}
+	if message.ArtifactID.Valid {
+		domainMessage.ArtifactID = &message.ArtifactID.UUID
+	}
+	return domainMessage
+}
+
+func toDomainMessages(messages []store.TutoringMessage) []*Message {
+	mapped := make([]*Message, len(messages))
+	for i, message := range messages {
+		mapped[i] = toDomainMessage(message)
+	}
+	return mapped
+}
@@ -0,0 +1,82 @@
+package tutoring
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/persistance/store"
+	"learning-core-api/internal/testutil"
+)
+
+func TestRepository_ConcurrentExchangesGetDistinctSeqs(t *testing.T) {
+	if os.Getenv("TEST_DB_URL") == "" {
+		t.Skip("missing TEST_DB_URL")
+	}
+
+	ctx := context.Background()
+	db := testutil.NewTestDB(t)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	queries := store.New(db)
+	repo := NewRepository(db)
+
+	userID := uuid.New()
+	_, err := queries.CreateUser(ctx, store.CreateUserParams{
+		ID:       userID,
+		Email:    fmt.Sprintf("tutoring-test-%s@example.com", userID),
+		Password: "password123",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = queries.DeleteUser(ctx, userID)
+	})
+	doc, err := queries.CreateDocument(ctx, store.CreateDocumentParams{
+		Filename:  "tutoring.pdf",
+		RagStatus: "READY",
+		UserID:    userID,
+	})
+	require.NoError(t, err)
+	// Deleting the document cascades to the thread and its messages.
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM documents WHERE id = $1", doc.ID)
+	})
+	thread, err := repo.CreateThread(ctx, userID, doc.ID, "Concurrent", nil)
+	require.NoError(t, err)
+
+	const exchanges = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, exchanges)
+	for i := 0; i < exchanges; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := repo.AppendExchange(ctx, thread.ID, fmt.Sprintf("question %d", i), fmt.Sprintf("answer %d", i), uuid.Nil)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	messages, err := repo.ListMessages(ctx, thread.ID)
+	require.NoError(t, err)
+	require.Len(t, messages, 2*exchanges)
+	for i, message := range messages {
+		assert.Equal(t, int32(i+1), message.Seq)
+	}
+	// Each question is directly followed by its answer.
+	for i := 0; i < len(messages); i += 2 {
+		assert.Equal(t, RoleUser, messages[i].Role)
+		assert.Equal(t, RoleAssistant, messages[i+1].Role)
+	}
+}
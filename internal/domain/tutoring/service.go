@@ -0,0 +1,320 @@
+package tutoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"learning-core-api/internal/domain/documents"
+	"learning-core-api/internal/domain/generation"
+	"learning-core-api/internal/utils"
+)
+
+// History windowing: once more than maxHistoryMessages messages follow the
+// summary, all but the latest keptHistoryMessages are folded into it. Folding
+// in batches keeps the summarisation call off most turns.
+const (
+	maxHistoryMessages  = 12
+	keptHistoryMessages = 6
+)
+
+const summaryPrompt = `You maintain running notes for a tutor who is helping a learner with a document.
+Update the notes with the conversation below so the tutor can continue without rereading it.
+
+Keep:
+- what the learner asked and what was explained
+- misunderstandings the learner showed and whether they were resolved
+- anything the learner said they want to focus on
+
+Write at most 150 words of plain text. Do not add information that is not in the notes or the conversation.
+`
+
+// Generator runs a single generation. *generation.Service implements it.
+type Generator interface {
+	Generate(ctx context.Context, req generation.GenerateRequest) (*generation.GenerateResponse, error)
+}
+
+// Service handles tutoring conversations
+type Service struct {
+	repo          Repository
+	documentsRepo documents.Repository
+	generator     Generator
+	graphRAG      bool
+}
+
+// NewService creates a new tutoring service. graphRAG enables graph_rag
+// retrieval on every turn and requires the document graph repository to be
+// configured on the generation service.
+func NewService(repo Repository, documentsRepo documents.Repository, generator Generator, graphRAG bool) *Service {
+	return &Service{
+		repo:          repo,
+		documentsRepo: documentsRepo,
+		generator:     generator,
+		graphRAG:      graphRAG,
+	}
+}
+
+// CreateThread starts a thread for a learner about a document
+func (s *Service) CreateThread(ctx context.Context, userID uuid.UUID, req CreateThreadRequest) (*Thread, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if req.DocumentID == uuid.Nil {
+		return nil, ErrInvalidDocumentID
+	}
+
+	doc, err := s.getDocument(ctx, req.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := ""
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if title == "" {
+		title = strings.TrimSpace(stringValue(doc.Title))
+	}
+	if title == "" {
+		title = doc.Filename
+	}
+	if len(title) > maxTitleLength {
+		return nil, ErrTitleTooLong
+	}
+
+	return s.repo.CreateThread(ctx, userID, doc.ID, title, req.ModelConfigID)
+}
+
+// ListThreads lists a learner's threads, optionally for one document
+func (s *Service) ListThreads(ctx context.Context, userID uuid.UUID, documentID *uuid.UUID, limit, offset int32) ([]*Thread, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	return s.repo.ListThreads(ctx, userID, documentID, limit, offset)
+}
+
+// GetThread returns a learner's thread with all of its messages
+func (s *Service) GetThread(ctx context.Context, threadID, userID uuid.UUID) (*ThreadDetail, error) {
+	thread, err := s.getOwnedThread(ctx, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.ListMessages(ctx, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadDetail{Thread: thread, Messages: messages}, nil
+}
+
+// Ask sends a learner message to a thread and returns the tutor's reply. The
+// reply is generated from the thread summary, the recent messages and context
+// retrieved for the message, and is saved as an artifact linked to the thread.
+// Nothing is added to the thread when the reply fails.
+func (s *Service) Ask(ctx context.Context, threadID, userID uuid.UUID, req AskRequest) (*AskResponse, error) {
+	if s.generator == nil {
+		return nil, ErrGenerationUnavailable
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, ErrEmptyMessage
+	}
+	if len(content) > maxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+
+	thread, err := s.getOwnedThread(ctx, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.getDocument(ctx, thread.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.repo.ListMessagesAfter(ctx, thread.ID, thread.SummarizedThrough)
+	if err != nil {
+		return nil, err
+	}
+	thread, recent, err = s.compactHistory(ctx, thread, recent)
+	if err != nil {
+		return nil, err
+	}
+
+	turnReq, err := s.turnRequest(thread, doc, recent, content)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.generator.Generate(ctx, turnReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tutor reply: %w", err)
+	}
+	reply := strings.TrimSpace(resp.OutputText)
+	if resp.Status != generation.ArtifactStatusReady || reply == "" {
+		return nil, fmt.Errorf("%w: artifact %s has status %s", ErrReplyFailed, resp.ArtifactID, resp.Status)
+	}
+
+	userMessage, assistantMessage, err := s.repo.AppendExchange(ctx, thread.ID, content, reply, resp.ArtifactID)
+	if err != nil {
+		return nil, err
+	}
+	return &AskResponse{UserMessage: userMessage, AssistantMessage: assistantMessage}, nil
+}
+
+// compactHistory folds the older messages into the thread summary once the
+// unsummarised history grows past maxHistoryMessages and returns the updated
+// thread with the messages that remain verbatim.
+func (s *Service) compactHistory(ctx context.Context, thread *Thread, recent []*Message) (*Thread, []*Message, error) {
+	if len(recent) <= maxHistoryMessages {
+		return thread, recent, nil
+	}
+
+	cut := len(recent) - keptHistoryMessages
+	// Keep the verbatim history starting on a learner message.
+	for cut < len(recent) && recent[cut].Role != RoleUser {
+		cut++
+	}
+	older, kept := recent[:cut], recent[cut:]
+
+	summary, err := s.summarise(ctx, thread, older)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := s.repo.UpdateSummary(ctx, thread.ID, summary, older[len(older)-1].Seq)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, kept, nil
+}
+
+// summarise asks the model to merge messages into the thread summary. The
+// call is saved as an artifact linked to the thread like any other turn.
+func (s *Service) summarise(ctx context.Context, thread *Thread, messages []*Message) (string, error) {
+	var prompt strings.Builder
+	prompt.WriteString(summaryPrompt)
+	if thread.Summary != nil && *thread.Summary != "" {
+		prompt.WriteString("\nCurrent notes:\n")
+		prompt.WriteString(*thread.Summary)
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString("\nConversation:\n")
+	for _, message := range messages {
+		speaker := "Learner"
+		if message.Role == RoleAssistant {
+			speaker = "Tutor"
+		}
+		fmt.Fprintf(&prompt, "%s: %s\n", speaker, message.Content)
+	}
+
+	resp, err := s.generator.Generate(ctx, generation.GenerateRequest{
+		UserID:        thread.UserID,
+		Target:        generation.Target{DocumentID: &thread.DocumentID, ThreadID: &thread.ID},
+		Instructions:  generation.Instructions{Inline: prompt.String()},
+		Output:        generation.OutputConfig{Format: "text"},
+		ModelConfigID: modelConfigID(thread),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarise tutoring history: %w", err)
+	}
+	summary := strings.TrimSpace(resp.OutputText)
+	if resp.Status != generation.ArtifactStatusReady || summary == "" {
+		return "", fmt.Errorf("%w: summary artifact %s has status %s", ErrReplyFailed, resp.ArtifactID, resp.Status)
+	}
+	return summary, nil
+}
+
+// turnRequest builds the generation request for one learner message: the
+// TUTORING prompt with the thread summary, the recent messages as history and
+// file_search and graph_rag retrieval for the message.
+func (s *Service) turnRequest(thread *Thread, doc *documents.Document, recent []*Message, content string) (generation.GenerateRequest, error) {
+	history := make([]generation.HistoryMessage, 0, len(recent))
+	for _, message := range recent {
+		role := generation.HistoryRoleUser
+		if message.Role == RoleAssistant {
+			role = generation.HistoryRoleModel
+		}
+		history = append(history, generation.HistoryMessage{Role: role, Text: message.Content})
+	}
+
+	summary := ""
+	if thread.Summary != nil {
+		summary = *thread.Summary
+	}
+
+	var tools []generation.ToolConfig
+	if storeName := strings.TrimSpace(stringValue(doc.FileStoreName)); storeName != "" {
+		config, err := json.Marshal(map[string]any{"store_names": []string{storeName}})
+		if err != nil {
+			return generation.GenerateRequest{}, fmt.Errorf("failed to marshal file_search config: %w", err)
+		}
+		tools = append(tools, generation.ToolConfig{Type: "file_search", Config: config})
+	}
+	if s.graphRAG {
+		config, err := json.Marshal(map[string]any{"query": content})
+		if err != nil {
+			return generation.GenerateRequest{}, fmt.Errorf("failed to marshal graph_rag config: %w", err)
+		}
+		tools = append(tools, generation.ToolConfig{Type: "graph_rag", Config: config})
+	}
+
+	return generation.GenerateRequest{
+		UserID: thread.UserID,
+		Target: generation.Target{DocumentID: &thread.DocumentID, ThreadID: &thread.ID},
+		Instructions: generation.Instructions{
+			GenerationType: string(utils.GenerationTypeTutoring),
+			Variables: map[string]interface{}{
+				"question":             content,
+				"conversation_summary": summary,
+				"document_title":       stringValue(doc.Title),
+			},
+		},
+		Output:        generation.OutputConfig{Format: "text"},
+		Tools:         tools,
+		ModelConfigID: modelConfigID(thread),
+		History:       history,
+	}, nil
+}
+
+func (s *Service) getOwnedThread(ctx context.Context, threadID, userID uuid.UUID) (*Thread, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	thread, err := s.repo.GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+	// Other learners' threads are reported as missing rather than forbidden.
+	if thread == nil || thread.UserID != userID {
+		return nil, ErrThreadNotFound
+	}
+	return thread, nil
+}
+
+func (s *Service) getDocument(ctx context.Context, documentID uuid.UUID) (*documents.Document, error) {
+	doc, err := s.documentsRepo.GetByID(ctx, documentID)
+	if err != nil {
+		if errors.Is(err, documents.ErrDocumentNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return doc, nil
+}
+
+func modelConfigID(thread *Thread) uuid.UUID {
+	if thread.ModelConfigID == nil {
+		return uuid.Nil
+	}
+	return *thread.ModelConfigID
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,197 @@
+package tutoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/documents"
+	"learning-core-api/internal/domain/generation"
+)
+
+type fakeRepository struct {
+	Repository
+	thread   *Thread
+	messages []*Message
+}
+
+func (r *fakeRepository) GetThread(ctx context.Context, id uuid.UUID) (*Thread, error) {
+	if r.thread == nil || r.thread.ID != id {
+		return nil, nil
+	}
+	return r.thread, nil
+}
+
+func (r *fakeRepository) ListMessagesAfter(ctx context.Context, threadID uuid.UUID, seq int32) ([]*Message, error) {
+	var after []*Message
+	for _, message := range r.messages {
+		if message.Seq > seq {
+			after = append(after, message)
+		}
+	}
+	return after, nil
+}
+
+func (r *fakeRepository) UpdateSummary(ctx context.Context, threadID uuid.UUID, summary string, summarizedThrough int32) (*Thread, error) {
+	updated := *r.thread
+	updated.Summary = &summary
+	updated.SummarizedThrough = summarizedThrough
+	r.thread = &updated
+	return r.thread, nil
+}
+
+func (r *fakeRepository) AppendExchange(ctx context.Context, threadID uuid.UUID, question, answer string, artifactID uuid.UUID) (*Message, *Message, error) {
+	seq := int32(len(r.messages))
+	user := &Message{ID: uuid.New(), ThreadID: threadID, Seq: seq + 1, Role: RoleUser, Content: question}
+	assistant := &Message{ID: uuid.New(), ThreadID: threadID, Seq: seq + 2, Role: RoleAssistant, Content: answer, ArtifactID: &artifactID}
+	r.messages = append(r.messages, user, assistant)
+	return user, assistant, nil
+}
+
+type fakeDocuments struct {
+	documents.Repository
+	doc *documents.Document
+}
+
+func (d *fakeDocuments) GetByID(ctx context.Context, id uuid.UUID) (*documents.Document, error) {
+	if d.doc == nil || d.doc.ID != id {
+		return nil, documents.ErrDocumentNotFound
+	}
+	return d.doc, nil
+}
+
+type fakeGenerator struct {
+	requests []generation.GenerateRequest
+	status   string
+}
+
+func (g *fakeGenerator) Generate(ctx context.Context, req generation.GenerateRequest) (*generation.GenerateResponse, error) {
+	g.requests = append(g.requests, req)
+	status := g.status
+	if status == "" {
+		status = generation.ArtifactStatusReady
+	}
+	return &generation.GenerateResponse{
+		ArtifactID: uuid.New(),
+		Status:     status,
+		OutputText: fmt.Sprintf("reply %d", len(g.requests)),
+	}, nil
+}
+
+func newTestService(t *testing.T, exchanges int) (*Service, *fakeRepository, *fakeGenerator) {
+	t.Helper()
+
+	title := "Cell Biology"
+	storeName := "fileSearchStores/biology"
+	doc := &documents.Document{ID: uuid.New(), Filename: "cells.pdf", Title: &title, FileStoreName: &storeName}
+	thread := &Thread{ID: uuid.New(), UserID: uuid.New(), DocumentID: doc.ID, Title: title, CreatedAt: time.Now()}
+
+	repo := &fakeRepository{thread: thread}
+	for i := 0; i < exchanges; i++ {
+		_, _, err := repo.AppendExchange(context.Background(), thread.ID, fmt.Sprintf("question %d", i+1), fmt.Sprintf("answer %d", i+1), uuid.New())
+		require.NoError(t, err)
+	}
+
+	generator := &fakeGenerator{}
+	return NewService(repo, &fakeDocuments{doc: doc}, generator, true), repo, generator
+}
+
+func TestAsk_SendsHistoryAndRetrieval(t *testing.T) {
+	service, repo, generator := newTestService(t, 2)
+
+	resp, err := service.Ask(context.Background(), repo.thread.ID, repo.thread.UserID, AskRequest{Content: "  Why do cells divide?  "})
+	require.NoError(t, err)
+	assert.Equal(t, "Why do cells divide?", resp.UserMessage.Content)
+	assert.Equal(t, "reply 1", resp.AssistantMessage.Content)
+	assert.EqualValues(t, 5, resp.UserMessage.Seq)
+	require.NotNil(t, resp.AssistantMessage.ArtifactID)
+
+	require.Len(t, generator.requests, 1)
+	req := generator.requests[0]
+	assert.Equal(t, "TUTORING", req.Instructions.GenerationType)
+	assert.Equal(t, "Why do cells divide?", req.Instructions.Variables["question"])
+	assert.Equal(t, "Cell Biology", req.Instructions.Variables["document_title"])
+	assert.Equal(t, repo.thread.ID, *req.Target.ThreadID)
+	assert.Equal(t, repo.thread.DocumentID, *req.Target.DocumentID)
+	assert.Equal(t, []generation.HistoryMessage{
+		{Role: generation.HistoryRoleUser, Text: "question 1"},
+		{Role: generation.HistoryRoleModel, Text: "answer 1"},
+		{Role: generation.HistoryRoleUser, Text: "question 2"},
+		{Role: generation.HistoryRoleModel, Text: "answer 2"},
+	}, req.History)
+
+	require.Len(t, req.Tools, 2)
+	assert.Equal(t, "file_search", req.Tools[0].Type)
+	assert.JSONEq(t, `{"store_names": ["fileSearchStores/biology"]}`, string(req.Tools[0].Config))
+	assert.Equal(t, "graph_rag", req.Tools[1].Type)
+	assert.JSONEq(t, `{"query": "Why do cells divide?"}`, string(req.Tools[1].Config))
+}
+
+func TestAsk_SummarisesOlderTurns(t *testing.T) {
+	service, repo, generator := newTestService(t, 7)
+
+	_, err := service.Ask(context.Background(), repo.thread.ID, repo.thread.UserID, AskRequest{Content: "And mitosis?"})
+	require.NoError(t, err)
+
+	require.Len(t, generator.requests, 2)
+	summaryReq := generator.requests[0]
+	assert.Empty(t, summaryReq.Instructions.GenerationType)
+	assert.Contains(t, summaryReq.Instructions.Inline, "Learner: question 1\nTutor: answer 1\n")
+	assert.Contains(t, summaryReq.Instructions.Inline, "Tutor: answer 4\n")
+	assert.NotContains(t, summaryReq.Instructions.Inline, "question 5")
+	assert.Equal(t, repo.thread.ID, *summaryReq.Target.ThreadID)
+
+	require.NotNil(t, repo.thread.Summary)
+	assert.Equal(t, "reply 1", *repo.thread.Summary)
+	assert.EqualValues(t, 8, repo.thread.SummarizedThrough)
+
+	turnReq := generator.requests[1]
+	assert.Equal(t, "reply 1", turnReq.Instructions.Variables["conversation_summary"])
+	require.Len(t, turnReq.History, keptHistoryMessages)
+	assert.Equal(t, "question 5", turnReq.History[0].Text)
+}
+
+func TestAsk_FailedReplyStoresNothing(t *testing.T) {
+	service, repo, generator := newTestService(t, 1)
+	generator.status = generation.ArtifactStatusError
+
+	_, err := service.Ask(context.Background(), repo.thread.ID, repo.thread.UserID, AskRequest{Content: "Hello?"})
+	assert.ErrorIs(t, err, ErrReplyFailed)
+	assert.Len(t, repo.messages, 2)
+}
+
+func TestAsk_Validation(t *testing.T) {
+	service, repo, generator := newTestService(t, 0)
+
+	_, err := service.Ask(context.Background(), repo.thread.ID, repo.thread.UserID, AskRequest{Content: " "})
+	assert.ErrorIs(t, err, ErrEmptyMessage)
+
+	_, err = service.Ask(context.Background(), repo.thread.ID, repo.thread.UserID, AskRequest{Content: strings.Repeat("a", maxMessageLength+1)})
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+
+	_, err = service.Ask(context.Background(), repo.thread.ID, uuid.New(), AskRequest{Content: "Hi"})
+	assert.ErrorIs(t, err, ErrThreadNotFound)
+
+	assert.Empty(t, generator.requests)
+}
+
+func TestTurnRequest_SkipsFileSearchWithoutStore(t *testing.T) {
+	service, repo, _ := newTestService(t, 0)
+	service.graphRAG = false
+
+	req, err := service.turnRequest(repo.thread, &documents.Document{ID: repo.thread.DocumentID}, nil, "Hi")
+	require.NoError(t, err)
+	assert.Empty(t, req.Tools)
+	assert.Empty(t, req.History)
+
+	encoded, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), `"history"`)
+}
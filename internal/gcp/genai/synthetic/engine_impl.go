@@ -13,8 +13,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// syntheticEpoch anchors generated timestamps so seeded runs are reproducible.
+var syntheticEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// maxRefDepth bounds recursive $ref expansion (e.g. taxonomy children).
+const maxRefDepth = 3
+
 type GenericSyntheticEngine struct {
-	rng *rand.Rand
+	rng      *rand.Rand
+	root     map[string]any
+	refDepth int
 }
 
 func NewGenericSyntheticEngine(seed uint64) *GenericSyntheticEngine {
@@ -34,6 +42,7 @@ func (e *GenericSyntheticEngine) Generate(_ context.Context, prompt PromptTempla
 		return nil, err
 	}
 
+	e.root = schemaDef
 	output := e.walk(schemaDef)
 	return json.Marshal(output)
 }
@@ -71,6 +80,16 @@ func (e *GenericSyntheticEngine) walk(schema any) any {
 }
 
 func (e *GenericSyntheticEngine) walkObjectSchema(schema map[string]any) any {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved := e.resolveRef(ref)
+		if resolved == nil || e.refDepth >= maxRefDepth {
+			return nil
+		}
+		e.refDepth++
+		defer func() { e.refDepth-- }()
+		return e.walk(resolved)
+	}
+
 	if value, ok := schema["const"]; ok {
 		return value
 	}
@@ -88,7 +107,7 @@ func (e *GenericSyntheticEngine) walkObjectSchema(schema map[string]any) any {
 		return e.mergeAllOf(options)
 	}
 
-	if schemaType, ok := schema["type"].(string); ok {
+	if schemaType := schemaTypeOf(schema); schemaType != "" {
 		switch schemaType {
 		case "object":
 			return e.walkProperties(schema)
@@ -112,6 +131,39 @@ func (e *GenericSyntheticEngine) walkObjectSchema(schema map[string]any) any {
 	return nil
 }
 
+// resolveRef follows local JSON pointers such as "#/definitions/node" or "#/$defs/node".
+func (e *GenericSyntheticEngine) resolveRef(ref string) any {
+	if !strings.HasPrefix(ref, "#/") || e.root == nil {
+		return nil
+	}
+
+	var current any = e.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = node[part]
+	}
+	return current
+}
+
+// schemaTypeOf normalises the schema type so both draft-07 ("object", ["string", "null"])
+// and Gemini-style ("OBJECT") schemas are supported.
+func schemaTypeOf(schema map[string]any) string {
+	switch typed := schema["type"].(type) {
+	case string:
+		return strings.ToLower(typed)
+	case []any:
+		for _, candidate := range typed {
+			if name, ok := candidate.(string); ok && strings.ToLower(name) != "null" {
+				return strings.ToLower(name)
+			}
+		}
+	}
+	return ""
+}
+
 func (e *GenericSyntheticEngine) walkProperties(schema map[string]any) map[string]any {
 	properties, _ := schema["properties"].(map[string]any)
 	keys := sortedKeys(properties)
@@ -127,6 +179,9 @@ func (e *GenericSyntheticEngine) walkArraySchema(schema map[string]any) []any {
 	if !ok {
 		return []any{}
 	}
+	if items, ok := itemsSchema.(map[string]any); ok && items["$ref"] != nil && e.refDepth >= maxRefDepth {
+		return []any{}
+	}
 
 	minItems := int64From(schema["minItems"], 1)
 	maxItems := int64From(schema["maxItems"], minItems+2)
@@ -177,9 +232,18 @@ func (e *GenericSyntheticEngine) fakeString(schema map[string]any) string {
 	if format, ok := schema["format"].(string); ok {
 		switch format {
 		case "uuid":
-			return uuid.New().String()
+			var id uuid.UUID
+			binaryIDs := [2]uint64{e.rng.Uint64(), e.rng.Uint64()}
+			for i := 0; i < 8; i++ {
+				id[i] = byte(binaryIDs[0] >> (8 * i))
+				id[8+i] = byte(binaryIDs[1] >> (8 * i))
+			}
+			id[6] = (id[6] & 0x0f) | 0x40
+			id[8] = (id[8] & 0x3f) | 0x80
+			return id.String()
 		case "date-time":
-			return time.Now().UTC().Format(time.RFC3339)
+			offset := time.Duration(e.rng.IntN(365*24)) * time.Hour
+			return syntheticEpoch.Add(offset).Format(time.RFC3339)
 		case "email":
 			return "synthetic@example.com"
 		}
@@ -3,7 +3,9 @@ package gcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"google.golang.org/genai"
 
@@ -36,15 +38,16 @@ func NewGenerationServiceFromAPIKey(ctx context.Context, apiKey string) (*Genera
 	return NewGenerationService(client)
 }
 
-func (s *GenerationService) Generate(ctx context.Context, req generation.GeneratorRequest) (*generation.GeneratorResponse, error) {
+// buildContentRequest maps a GeneratorRequest onto the genai model name, contents and config.
+func (s *GenerationService) buildContentRequest(req generation.GeneratorRequest) (string, []*genai.Content, *genai.GenerateContentConfig, error) {
 	if s == nil || s.client == nil {
-		return nil, fmt.Errorf("genai client is required")
+		return "", nil, nil, fmt.Errorf("genai client is required")
 	}
 	if req.Model == nil {
-		return nil, fmt.Errorf("model config is required")
+		return "", nil, nil, fmt.Errorf("model config is required")
 	}
 	if req.Model.Name == "" {
-		return nil, fmt.Errorf("model name is required")
+		return "", nil, nil, fmt.Errorf("model name is required")
 	}
 
 	modelName := req.Model.Name
@@ -67,6 +70,7 @@ func (s *GenerationService) Generate(ctx context.Context, req generation.Generat
 		if req.Model.MimeType != "" {
 			genConfig.ResponseMIMEType = req.Model.MimeType
 		}
+		applySamplingParams(genConfig, req.Model)
 	}
 
 	if req.SystemInstruction != "" {
@@ -80,32 +84,38 @@ func (s *GenerationService) Generate(ctx context.Context, req generation.Generat
 	if len(req.OutputSchema) > 0 {
 		schema := &genai.Schema{}
 		if err := json.Unmarshal(req.OutputSchema, schema); err != nil {
-			return nil, fmt.Errorf("failed to parse response schema: %w", err)
+			return "", nil, nil, fmt.Errorf("failed to parse response schema: %w", err)
 		}
 		genConfig.ResponseMIMEType = "application/json"
 		genConfig.ResponseSchema = schema
 	}
 
-	contents := []*genai.Content{
-		{
-			Role: "user",
-			Parts: []*genai.Part{
-				{Text: req.Prompt},
-			},
-		},
+	contents := make([]*genai.Content, 0, len(req.History)+1+len(req.Turns))
+	for _, message := range req.History {
+		role := genai.RoleUser
+		if message.Role == generation.HistoryRoleModel {
+			role = genai.RoleModel
+		}
+		contents = append(contents, &genai.Content{Role: role, Parts: []*genai.Part{{Text: message.Text}}})
 	}
+	contents = append(contents, &genai.Content{
+		Role: "user",
+		Parts: []*genai.Part{
+			{Text: req.Prompt},
+		},
+	})
 
 	for _, tool := range req.Tools {
 		if tool.Type == "file_search" {
 			var cfg fileSearchToolConfig
 			if len(tool.Config) == 0 {
-				return nil, fmt.Errorf("file_search tool config is required")
+				return "", nil, nil, fmt.Errorf("file_search tool config is required")
 			}
 			if err := json.Unmarshal(tool.Config, &cfg); err != nil {
-				return nil, fmt.Errorf("failed to parse file_search config: %w", err)
+				return "", nil, nil, fmt.Errorf("failed to parse file_search config: %w", err)
 			}
 			if len(cfg.StoreNames) == 0 {
-				return nil, fmt.Errorf("file_search store_names is required")
+				return "", nil, nil, fmt.Errorf("file_search store_names is required")
 			}
 			genConfig.Tools = append(genConfig.Tools, &genai.Tool{
 				FileSearch: &genai.FileSearch{
@@ -116,19 +126,134 @@ func (s *GenerationService) Generate(ctx context.Context, req generation.Generat
 		}
 	}
 
+	if len(req.Functions) > 0 {
+		declarations := make([]*genai.FunctionDeclaration, 0, len(req.Functions))
+		for _, fn := range req.Functions {
+			declaration := &genai.FunctionDeclaration{Name: fn.Name, Description: fn.Description}
+			if len(fn.Parameters) > 0 {
+				params := &genai.Schema{}
+				if err := json.Unmarshal(fn.Parameters, params); err != nil {
+					return "", nil, nil, fmt.Errorf("failed to parse parameters of function %s: %w", fn.Name, err)
+				}
+				declaration.Parameters = params
+			}
+			declarations = append(declarations, declaration)
+		}
+		genConfig.Tools = append(genConfig.Tools, &genai.Tool{FunctionDeclarations: declarations})
+	}
+
+	for _, turn := range req.Turns {
+		content, err := turnContent(turn)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		contents = append(contents, content)
+	}
+
+	return modelName, contents, genConfig, nil
+}
+
+// applySamplingParams maps the optional sampling and safety parameters of a
+// model config onto the genai config.
+func applySamplingParams(genConfig *genai.GenerateContentConfig, model *generation.ModelConfig) {
+	genConfig.Seed = model.Seed
+	genConfig.StopSequences = model.StopSequences
+	if model.CandidateCount != nil {
+		genConfig.CandidateCount = *model.CandidateCount
+	}
+	genConfig.PresencePenalty = model.PresencePenalty
+	genConfig.FrequencyPenalty = model.FrequencyPenalty
+	if model.ThinkingBudget != nil {
+		genConfig.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: model.ThinkingBudget}
+	}
+	for _, setting := range model.SafetySettings {
+		genConfig.SafetySettings = append(genConfig.SafetySettings, &genai.SafetySetting{
+			Category:  genai.HarmCategory(setting.Category),
+			Threshold: genai.HarmBlockThreshold(setting.Threshold),
+		})
+	}
+}
+
+// turnContent maps a function-calling turn onto a model or user content.
+func turnContent(turn generation.ConversationTurn) (*genai.Content, error) {
+	if len(turn.Results) > 0 {
+		content := &genai.Content{Role: genai.RoleUser}
+		for _, result := range turn.Results {
+			response := map[string]any{}
+			if err := json.Unmarshal(result.Response, &response); err != nil {
+				// Non-object results are wrapped as the conventional "output" field.
+				response = map[string]any{"output": json.RawMessage(result.Response)}
+			}
+			content.Parts = append(content.Parts, &genai.Part{
+				FunctionResponse: &genai.FunctionResponse{ID: result.ID, Name: result.Name, Response: response},
+			})
+		}
+		return content, nil
+	}
+
+	content := &genai.Content{Role: genai.RoleModel}
+	if turn.Text != "" {
+		content.Parts = append(content.Parts, &genai.Part{Text: turn.Text})
+	}
+	for _, call := range turn.Calls {
+		args := map[string]any{}
+		if len(call.Args) > 0 {
+			if err := json.Unmarshal(call.Args, &args); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments of function call %s: %w", call.Name, err)
+			}
+		}
+		content.Parts = append(content.Parts, &genai.Part{
+			FunctionCall:     &genai.FunctionCall{ID: call.ID, Name: call.Name, Args: args},
+			ThoughtSignature: call.Signature,
+		})
+	}
+	return content, nil
+}
+
+// functionCalls extracts the function calls of a candidate, keeping their
+// thought signatures so they can be replayed.
+func functionCalls(content *genai.Content) ([]generation.FunctionCall, error) {
+	if content == nil {
+		return nil, nil
+	}
+	var calls []generation.FunctionCall
+	for _, part := range content.Parts {
+		if part.FunctionCall == nil {
+			continue
+		}
+		args, err := json.Marshal(part.FunctionCall.Args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode arguments of function call %s: %w", part.FunctionCall.Name, err)
+		}
+		calls = append(calls, generation.FunctionCall{
+			ID:        part.FunctionCall.ID,
+			Name:      part.FunctionCall.Name,
+			Args:      args,
+			Signature: part.ThoughtSignature,
+		})
+	}
+	return calls, nil
+}
+
+func (s *GenerationService) Generate(ctx context.Context, req generation.GeneratorRequest) (*generation.GeneratorResponse, error) {
+	modelName, contents, genConfig, err := s.buildContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := s.client.Models.GenerateContent(ctx, modelName, contents, genConfig)
 	if err != nil {
-		return nil, fmt.Errorf("genai call failed: %w", err)
+		return nil, fmt.Errorf("genai call failed: %w", providerError(err))
 	}
 	if len(resp.Candidates) == 0 {
 		return nil, fmt.Errorf("no candidates returned")
 	}
 
-	var outputText string
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if part.Text != "" {
-			outputText += part.Text
-		}
+	outputText := candidateText(resp.Candidates[0])
+
+	calls, err := functionCalls(resp.Candidates[0].Content)
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract grounding metadata if available
@@ -139,10 +264,126 @@ func (s *GenerationService) Generate(ctx context.Context, req generation.Generat
 		}
 	}
 
+	var candidates []string
+	if len(resp.Candidates) > 1 {
+		candidates = make([]string, 0, len(resp.Candidates))
+		for _, candidate := range resp.Candidates {
+			candidates = append(candidates, candidateText(candidate))
+		}
+	}
+
 	return &generation.GeneratorResponse{
 		OutputText:        outputText,
 		FinishReason:      string(resp.Candidates[0].FinishReason),
 		ModelUsed:         modelName,
 		GroundingMetadata: groundingMetadata,
+		Usage:             tokenUsage(resp.UsageMetadata),
+		FunctionCalls:     calls,
+		Candidates:        candidates,
 	}, nil
 }
+
+func (s *GenerationService) GenerateStream(ctx context.Context, req generation.GeneratorRequest, onChunk generation.ChunkHandler) (*generation.GeneratorResponse, error) {
+	modelName, contents, genConfig, err := s.buildContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputText strings.Builder
+	var finishReason string
+	var groundingMetadata json.RawMessage
+	var usage *generation.TokenUsage
+
+	for resp, err := range s.client.Models.GenerateContentStream(ctx, modelName, contents, genConfig) {
+		if err != nil {
+			return nil, fmt.Errorf("genai stream failed: %w", providerError(err))
+		}
+		// Usage is cumulative; the last chunk carries the final counts.
+		if resp.UsageMetadata != nil {
+			usage = tokenUsage(resp.UsageMetadata)
+		}
+		if len(resp.Candidates) == 0 {
+			continue
+		}
+
+		// Only the first candidate is streamed; others are dropped.
+		var candidate *genai.Candidate
+		for _, streamed := range resp.Candidates {
+			if streamed.Index == 0 {
+				candidate = streamed
+				break
+			}
+		}
+		if candidate == nil {
+			continue
+		}
+		if candidate.Content != nil {
+			for _, part := range candidate.Content.Parts {
+				if part.Text == "" {
+					continue
+				}
+				outputText.WriteString(part.Text)
+				if onChunk != nil {
+					if err := onChunk(part.Text); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		if candidate.FinishReason != "" {
+			finishReason = string(candidate.FinishReason)
+		}
+		if candidate.GroundingMetadata != nil {
+			if groundingBytes, err := json.Marshal(candidate.GroundingMetadata); err == nil {
+				groundingMetadata = groundingBytes
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &generation.GeneratorResponse{
+		OutputText:        outputText.String(),
+		FinishReason:      finishReason,
+		ModelUsed:         modelName,
+		GroundingMetadata: groundingMetadata,
+		Usage:             usage,
+	}, nil
+}
+
+// candidateText concatenates the text parts of a candidate.
+func candidateText(candidate *genai.Candidate) string {
+	if candidate == nil || candidate.Content == nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String()
+}
+
+// tokenUsage converts genai usage metadata; thinking tokens are counted as output.
+func tokenUsage(metadata *genai.GenerateContentResponseUsageMetadata) *generation.TokenUsage {
+	if metadata == nil {
+		return nil
+	}
+	return &generation.TokenUsage{
+		PromptTokens:    metadata.PromptTokenCount + metadata.ToolUsePromptTokenCount,
+		CandidateTokens: metadata.CandidatesTokenCount + metadata.ThoughtsTokenCount,
+		CachedTokens:    metadata.CachedContentTokenCount,
+		TotalTokens:     metadata.TotalTokenCount,
+	}
+}
+
+// providerError tags genai API errors with their HTTP status so the generation
+// layer can tell transient failures (429, 5xx) from fatal ones.
+func providerError(err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return &generation.ProviderError{StatusCode: apiErr.Code, Err: err}
+	}
+	return err
+}
@@ -81,6 +81,23 @@ func RequireRole(requiredRole string) func(http.Handler) http.Handler {
 	}
 }
 
+// RequireAnyRole allows requests from users holding at least one of roles.
+func RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, role := range RolesFromContext(r.Context()) {
+				for _, allowed := range roles {
+					if role == allowed {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		})
+	}
+}
+
 func RequireScope(requiredScope string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -2,6 +2,7 @@ package infra
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"io"
 	"log"
@@ -27,9 +28,11 @@ import (
 	"learning-core-api/internal/domain/subjects"
 	"learning-core-api/internal/domain/system_instructions"
 	"learning-core-api/internal/domain/textbooks"
+	"learning-core-api/internal/domain/tutoring"
 	"learning-core-api/internal/domain/users"
 	"learning-core-api/internal/gcp"
 	"learning-core-api/internal/http/authz"
+	"learning-core-api/internal/openai"
 	"learning-core-api/internal/persistance/store"
 )
 
@@ -38,9 +41,15 @@ type RouterDeps struct {
 	Queries           *store.Queries
 	DB                *sql.DB
 	GoogleAPIKey      string
+	OpenAIAPIKey      string
+	OpenAIBaseURL     string
 	GCSService        *gcp.GCSService
 	FileService       *gcp.FileService
 	DocumentAIService *gcp.DocumentAIService
+
+	// RequireApprovedArtifacts limits ingestion of generated artifacts into
+	// evals and the document graph to artifacts approved in review
+	RequireApprovedArtifacts bool
 }
 
 type RoleRouteRegistrar interface {
@@ -50,6 +59,14 @@ type RoleRouteRegistrar interface {
 	RegisterLearnerRoutes(r chi.Router)
 }
 
+// StaffRouteRegistrar is implemented by handlers with routes open to both
+// teachers and admins. chi keeps one handler per method and path, so a route
+// registered in the teacher and the admin group is only reachable by admins;
+// shared routes are registered once, in a group that accepts either role.
+type StaffRouteRegistrar interface {
+	RegisterStaffRoutes(r chi.Router)
+}
+
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		timestamp := time.Now().Format(time.RFC3339)
@@ -102,6 +119,17 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+// Flush lets streaming handlers (SSE) push data through the logging wrapper.
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func NewRouter(deps RouterDeps) http.Handler {
 	r := chi.NewRouter()
 
@@ -162,18 +190,51 @@ func NewRouter(deps RouterDeps) http.Handler {
 	modelConfigsHandler := model_configs.NewHandler(modelConfigsService)
 
 	artifactsService := artifacts.NewService(deps.DB)
-	artifactsHandler := artifacts.NewHandler(artifactsService)
+	artifactsHandler := artifacts.NewHandler(artifactsService, evals.NewIngestService(deps.DB, deps.RequireApprovedArtifacts))
 
 	graphRepo, err := document_graph.NewRepository(deps.DB)
 	if err != nil {
 		log.Printf("Warning: Failed to create document graph repository: %v", err)
 	}
 
-	generationService, err := generation.NewService(deps.DB, artifactsService, nil, graphRepo)
+	providers := map[string]generation.Generator{
+		model_configs.ProviderSynthetic: generation.NewSyntheticGenerator(),
+	}
+	if deps.GoogleAPIKey != "" {
+		geminiGenerator, err := gcp.NewGenerationServiceFromAPIKey(context.Background(), deps.GoogleAPIKey)
+		if err != nil {
+			log.Printf("Warning: Failed to create Gemini generator: %v", err)
+		} else {
+			providers[model_configs.ProviderGemini] = geminiGenerator
+		}
+	}
+	if deps.OpenAIAPIKey != "" || deps.OpenAIBaseURL != "" {
+		providers[model_configs.ProviderOpenAI] = openai.NewChatGenerator(deps.OpenAIBaseURL, deps.OpenAIAPIKey, nil)
+	}
+	generator := generation.NewResilientGenerator(
+		generation.NewRateLimitedGenerator(generation.NewRoutingGenerator(providers)),
+		generation.DefaultRetryPolicy(),
+	)
+
+	generationService, err := generation.NewService(deps.DB, artifactsService, generator, graphRepo)
 	if err != nil {
 		log.Printf("Warning: Failed to create generation service: %v", err)
 	}
 
+	var batchService *generation.BatchService
+	if generationService != nil {
+		batchService, err = generation.NewBatchService(deps.DB, generationService)
+		if err != nil {
+			log.Printf("Warning: Failed to create generation batch service: %v", err)
+		} else if interrupted, err := batchService.FailInterruptedBatches(context.Background()); err != nil {
+			log.Printf("Warning: Failed to fail interrupted generation batches: %v", err)
+		} else if interrupted > 0 {
+			log.Printf("Marked %d interrupted generation batches as failed", interrupted)
+		}
+	}
+
+	generationHandler := generation.NewHandler(generationService, batchService)
+
 	var graphService *document_graph.Service
 	if graphRepo != nil {
 		graphService, err = document_graph.NewService(graphRepo, documents.NewRepository(deps.Queries), deps.GCSService, deps.DocumentAIService)
@@ -185,9 +246,24 @@ func NewRouter(deps RouterDeps) http.Handler {
 	contentDiscoveryService := content_discovery.NewService(subjectsService, documentsService, deps.GCSService, deps.FileService, generationService, graphService)
 	contentDiscoveryHandler := content_discovery.NewHandler(contentDiscoveryService)
 
+	var sectionService *document_graph.SectionService
+	if graphRepo != nil {
+		sectionService, err = document_graph.NewSectionService(graphRepo, deps.DB, deps.RequireApprovedArtifacts)
+		if err != nil {
+			log.Printf("Warning: Failed to create document section service: %v", err)
+		}
+	}
+
+	var tutoringGenerator tutoring.Generator
+	if generationService != nil {
+		tutoringGenerator = generationService
+	}
+	tutoringService := tutoring.NewService(tutoring.NewRepository(deps.DB), documents.NewRepository(deps.Queries), tutoringGenerator, graphRepo != nil)
+	tutoringHandler := tutoring.NewHandler(tutoringService)
+
 	var graphHandler *document_graph.Handler
-	if graphService != nil {
-		graphHandler = document_graph.NewHandler(graphService)
+	if graphService != nil || sectionService != nil {
+		graphHandler = document_graph.NewHandler(graphService, sectionService)
 	}
 
 	authHandler.RegisterPublicRoutes(r)
@@ -200,6 +276,7 @@ func NewRouter(deps RouterDeps) http.Handler {
 	registerRoleRoutes(r, deps.JWTSecret, evalsHandler)
 	registerRoleRoutes(r, deps.JWTSecret, reviewsHandler)
 	registerRoleRoutes(r, deps.JWTSecret, attemptsHandler)
+	registerRoleRoutes(r, deps.JWTSecret, tutoringHandler)
 	registerRoleRoutes(r, deps.JWTSecret, promptTemplatesHandler)
 	registerRoleRoutes(r, deps.JWTSecret, schemaTemplatesHandler)
 	registerRoleRoutes(r, deps.JWTSecret, chunkingConfigsHandler)
@@ -207,6 +284,7 @@ func NewRouter(deps RouterDeps) http.Handler {
 	registerRoleRoutes(r, deps.JWTSecret, subjectsHandler)
 	registerRoleRoutes(r, deps.JWTSecret, modelConfigsHandler)
 	registerRoleRoutes(r, deps.JWTSecret, artifactsHandler)
+	registerRoleRoutes(r, deps.JWTSecret, generationHandler)
 	registerRoleRoutes(r, deps.JWTSecret, contentDiscoveryHandler)
 	if graphHandler != nil {
 		registerRoleRoutes(r, deps.JWTSecret, graphHandler)
@@ -216,15 +294,18 @@ func NewRouter(deps RouterDeps) http.Handler {
 }
 
 func registerRoleRoutes(r chi.Router, secret string, registrar RoleRouteRegistrar) {
-	registerProtectedRoleRoutes(r, secret, authz.RoleLearner, registrar.RegisterLearnerRoutes)
-	registerProtectedRoleRoutes(r, secret, authz.RoleTeacher, registrar.RegisterTeacherRoutes)
-	registerProtectedRoleRoutes(r, secret, authz.RoleAdmin, registrar.RegisterAdminRoutes)
+	registerProtectedRoleRoutes(r, secret, authz.RequireRole(authz.RoleLearner), registrar.RegisterLearnerRoutes)
+	registerProtectedRoleRoutes(r, secret, authz.RequireRole(authz.RoleTeacher), registrar.RegisterTeacherRoutes)
+	registerProtectedRoleRoutes(r, secret, authz.RequireRole(authz.RoleAdmin), registrar.RegisterAdminRoutes)
+	if staff, ok := registrar.(StaffRouteRegistrar); ok {
+		registerProtectedRoleRoutes(r, secret, authz.RequireAnyRole(authz.RoleTeacher, authz.RoleAdmin), staff.RegisterStaffRoutes)
+	}
 }
 
-func registerProtectedRoleRoutes(r chi.Router, secret, role string, register func(chi.Router)) {
+func registerProtectedRoleRoutes(r chi.Router, secret string, requireRole func(http.Handler) http.Handler, register func(chi.Router)) {
 	r.Group(func(r chi.Router) {
 		r.Use(JWTMiddleware(secret))
-		r.Use(authz.RequireRole(role))
+		r.Use(requireRole)
 		register(r)
 	})
 }
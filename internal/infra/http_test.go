@@ -0,0 +1,136 @@
+package infra
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/artifacts"
+	"learning-core-api/internal/domain/document_graph"
+	"learning-core-api/internal/domain/generation"
+	"learning-core-api/internal/http/authz"
+)
+
+const testJWTSecret = "test-secret"
+
+type routeCase struct {
+	method string
+	path   string
+	// status the handler answers with when the request reaches it
+	status int
+}
+
+func newRoleRouter(registrars ...RoleRouteRegistrar) http.Handler {
+	r := chi.NewRouter()
+	for _, registrar := range registrars {
+		registerRoleRoutes(r, testJWTSecret, registrar)
+	}
+	return r
+}
+
+func testToken(t *testing.T, subject string, roles ...string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   subject,
+		"roles": roles,
+	}).SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+// assertRoleAccess checks that teachers and admins reach the handler of every
+// route and learners are forbidden.
+func assertRoleAccess(t *testing.T, router http.Handler, subject string, routes []routeCase) {
+	t.Helper()
+	for _, role := range []string{authz.RoleTeacher, authz.RoleAdmin, authz.RoleLearner} {
+		token := testToken(t, subject, role)
+		for _, route := range routes {
+			req := httptest.NewRequest(route.method, route.path, strings.NewReader("{}"))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			want := route.status
+			if role == authz.RoleLearner {
+				want = http.StatusForbidden
+			}
+			assert.Equal(t, want, w.Code, "%s %s %s", role, route.method, route.path)
+		}
+	}
+}
+
+func TestRoleRoutes_Generations(t *testing.T) {
+	// Without a service every generation handler answers 503.
+	router := newRoleRouter(generation.NewHandler(nil, nil))
+
+	assertRoleAccess(t, router, uuid.NewString(), []routeCase{
+		{http.MethodPost, "/generations", http.StatusServiceUnavailable},
+		{http.MethodPost, "/generations/stream", http.StatusServiceUnavailable},
+		{http.MethodPost, "/generations/preview", http.StatusServiceUnavailable},
+		{http.MethodGet, "/generations", http.StatusServiceUnavailable},
+		{http.MethodGet, "/generations/" + uuid.NewString(), http.StatusServiceUnavailable},
+		{http.MethodPost, "/generations/batches", http.StatusServiceUnavailable},
+		{http.MethodGet, "/generations/batches", http.StatusServiceUnavailable},
+		{http.MethodGet, "/generations/batches/" + uuid.NewString(), http.StatusServiceUnavailable},
+		{http.MethodPost, "/artifacts/" + uuid.NewString() + "/regenerate", http.StatusServiceUnavailable},
+		{http.MethodPost, "/artifacts/" + uuid.NewString() + "/refine", http.StatusServiceUnavailable},
+	})
+}
+
+func TestRoleRoutes_Artifacts(t *testing.T) {
+	// Invalid artifact and user IDs are rejected by the handlers before the
+	// database is queried.
+	router := newRoleRouter(artifacts.NewHandler(artifacts.NewService(nil), nil))
+
+	assertRoleAccess(t, router, "not-a-uuid", []routeCase{
+		{http.MethodPost, "/artifacts/not-a-uuid/ingest", http.StatusBadRequest},
+		{http.MethodGet, "/artifacts/not-a-uuid/lineage", http.StatusBadRequest},
+		{http.MethodGet, "/artifacts/not-a-uuid/diff/" + uuid.NewString(), http.StatusBadRequest},
+		{http.MethodGet, "/artifacts/review-queue", http.StatusUnauthorized},
+		{http.MethodGet, "/artifacts/not-a-uuid/review", http.StatusBadRequest},
+		{http.MethodPost, "/artifacts/not-a-uuid/review/assign", http.StatusBadRequest},
+		{http.MethodPost, "/artifacts/not-a-uuid/review/comments", http.StatusBadRequest},
+		{http.MethodPost, "/artifacts/not-a-uuid/review/approve", http.StatusBadRequest},
+		{http.MethodPost, "/artifacts/not-a-uuid/review/reject", http.StatusBadRequest},
+	})
+}
+
+func TestRoleRoutes_DocumentGraph(t *testing.T) {
+	// Without a section service the handler answers 503.
+	router := newRoleRouter(document_graph.NewHandler(nil, nil))
+
+	assertRoleAccess(t, router, uuid.NewString(), []routeCase{
+		{http.MethodPost, "/documents/" + uuid.NewString() + "/graph/sections", http.StatusServiceUnavailable},
+	})
+}
+
+func TestRequireAnyRole(t *testing.T) {
+	handler := authz.RequireAnyRole(authz.RoleTeacher, authz.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for roles, want := range map[string]int{
+		"teacher":       http.StatusNoContent,
+		"admin":         http.StatusNoContent,
+		"learner,admin": http.StatusNoContent,
+		"learner":       http.StatusForbidden,
+		"":              http.StatusForbidden,
+	} {
+		var list []string
+		if roles != "" {
+			list = strings.Split(roles, ",")
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(authz.WithAuth(req.Context(), uuid.NewString(), list, nil))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, roles)
+	}
+}
@@ -0,0 +1,559 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"learning-core-api/internal/domain/generation"
+)
+
+// DefaultBaseURL is used when neither the generator nor the model config sets an endpoint.
+const DefaultBaseURL = "https://api.openai.com"
+
+const chatCompletionsPath = "/v1/chat/completions"
+
+// ChatGenerator implements generation.Generator against any OpenAI-compatible
+// /v1/chat/completions endpoint (OpenAI, Azure-style proxies, vLLM, Ollama, ...).
+type ChatGenerator struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewChatGenerator creates a generator. baseURL defaults to DefaultBaseURL and
+// client to an http.Client with a generous timeout; a model config's base_url
+// overrides baseURL per request.
+func NewChatGenerator(baseURL, apiKey string, client *http.Client) *ChatGenerator {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &ChatGenerator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  client,
+	}
+}
+
+type chatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type toolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function toolCallFunction `json:"function"`
+}
+
+type toolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type chatTool struct {
+	Type     string       `json:"type"`
+	Function chatFunction `json:"function"`
+}
+
+type chatFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type responseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema *jsonSchemaSpec `json:"json_schema,omitempty"`
+}
+
+type jsonSchemaSpec struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+	Strict bool            `json:"strict"`
+}
+
+type chatRequest struct {
+	Model            string          `json:"model"`
+	Messages         []chatMessage   `json:"messages"`
+	Temperature      *float32        `json:"temperature,omitempty"`
+	TopP             *float32        `json:"top_p,omitempty"`
+	MaxTokens        *int32          `json:"max_tokens,omitempty"`
+	Seed             *int32          `json:"seed,omitempty"`
+	Stop             []string        `json:"stop,omitempty"`
+	N                *int32          `json:"n,omitempty"`
+	PresencePenalty  *float32        `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32        `json:"frequency_penalty,omitempty"`
+	ResponseFormat   *responseFormat `json:"response_format,omitempty"`
+	Tools            []chatTool      `json:"tools,omitempty"`
+	Stream           bool            `json:"stream,omitempty"`
+	StreamOptions    *streamOptions  `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type chatUsage struct {
+	PromptTokens        int32 `json:"prompt_tokens"`
+	CompletionTokens    int32 `json:"completion_tokens"`
+	TotalTokens         int32 `json:"total_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int32 `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int         `json:"index"`
+		Message      chatMessage `json:"message"`
+		Delta        chatMessage `json:"delta"`
+		FinishReason *string     `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+func (g *ChatGenerator) Generate(ctx context.Context, req generation.GeneratorRequest) (*generation.GeneratorResponse, error) {
+	httpResp, modelName, err := g.do(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var resp chatResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode chat completion: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned")
+	}
+
+	choice := resp.Choices[0]
+	finishReason := ""
+	if choice.FinishReason != nil {
+		finishReason = normaliseFinishReason(*choice.FinishReason)
+	}
+
+	var calls []generation.FunctionCall
+	for _, call := range choice.Message.ToolCalls {
+		args := json.RawMessage(call.Function.Arguments)
+		if len(args) == 0 {
+			args = json.RawMessage(`{}`)
+		}
+		if !json.Valid(args) {
+			return nil, fmt.Errorf("function call %s has invalid JSON arguments", call.Function.Name)
+		}
+		calls = append(calls, generation.FunctionCall{ID: call.ID, Name: call.Function.Name, Args: args})
+	}
+
+	var candidates []string
+	if len(resp.Choices) > 1 {
+		candidates = make([]string, 0, len(resp.Choices))
+		for _, candidate := range resp.Choices {
+			candidates = append(candidates, candidate.Message.Content)
+		}
+	}
+
+	return &generation.GeneratorResponse{
+		OutputText:    choice.Message.Content,
+		FinishReason:  finishReason,
+		ModelUsed:     firstNonEmpty(resp.Model, modelName),
+		Usage:         tokenUsage(resp.Usage),
+		FunctionCalls: calls,
+		Candidates:    candidates,
+	}, nil
+}
+
+func (g *ChatGenerator) GenerateStream(ctx context.Context, req generation.GeneratorRequest, onChunk generation.ChunkHandler) (*generation.GeneratorResponse, error) {
+	httpResp, modelName, err := g.do(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var outputText strings.Builder
+	var finishReason string
+	var usage *generation.TokenUsage
+	modelUsed := modelName
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var event chatResponse
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode stream event: %w", err)
+		}
+		if event.Model != "" {
+			modelUsed = event.Model
+		}
+		// With include_usage the final event has no choices and carries the usage.
+		if event.Usage != nil {
+			usage = tokenUsage(event.Usage)
+		}
+		if len(event.Choices) == 0 {
+			continue
+		}
+
+		choice := event.Choices[0]
+		if choice.Delta.Content != "" {
+			outputText.WriteString(choice.Delta.Content)
+			if onChunk != nil {
+				if err := onChunk(choice.Delta.Content); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			finishReason = normaliseFinishReason(*choice.FinishReason)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("chat completion stream failed: %w", err)
+	}
+
+	return &generation.GeneratorResponse{
+		OutputText:   outputText.String(),
+		FinishReason: finishReason,
+		ModelUsed:    modelUsed,
+		Usage:        usage,
+	}, nil
+}
+
+func tokenUsage(usage *chatUsage) *generation.TokenUsage {
+	if usage == nil {
+		return nil
+	}
+	converted := &generation.TokenUsage{
+		PromptTokens:    usage.PromptTokens,
+		CandidateTokens: usage.CompletionTokens,
+		TotalTokens:     usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		converted.CachedTokens = usage.PromptTokensDetails.CachedTokens
+	}
+	return converted
+}
+
+// do sends the chat completion request and returns the response once a 2xx
+// status has been received. Other statuses become *generation.ProviderError so
+// ResilientGenerator can classify them.
+func (g *ChatGenerator) do(ctx context.Context, req generation.GeneratorRequest, stream bool) (*http.Response, string, error) {
+	body, err := g.buildRequest(req, stream)
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal chat completion request: %w", err)
+	}
+
+	baseURL := g.baseURL
+	if req.Model.BaseURL != "" {
+		baseURL = strings.TrimRight(req.Model.BaseURL, "/")
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+chatCompletionsPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build chat completion request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+	if g.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	httpResp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, "", fmt.Errorf("chat completion call failed: %w", err)
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		defer httpResp.Body.Close()
+		return nil, "", &generation.ProviderError{
+			StatusCode: httpResp.StatusCode,
+			Err:        fmt.Errorf("chat completion call failed: %s", errorMessage(httpResp)),
+		}
+	}
+
+	return httpResp, body.Model, nil
+}
+
+func (g *ChatGenerator) buildRequest(req generation.GeneratorRequest, stream bool) (*chatRequest, error) {
+	if req.Model == nil {
+		return nil, fmt.Errorf("model config is required")
+	}
+	if req.Model.Name == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+	for _, tool := range req.Tools {
+		if tool.Type == "file_search" {
+			return nil, fmt.Errorf("file_search tool is not supported by OpenAI-compatible models")
+		}
+	}
+	// Chat completions have no equivalent; dropping them would silently weaken the config.
+	if len(req.Model.SafetySettings) > 0 {
+		return nil, fmt.Errorf("safety_settings are not supported by OpenAI-compatible models")
+	}
+	if req.Model.ThinkingBudget != nil {
+		return nil, fmt.Errorf("thinking_budget is not supported by OpenAI-compatible models")
+	}
+
+	body := &chatRequest{
+		Model:            req.Model.Name,
+		Temperature:      req.Model.Temperature,
+		TopP:             req.Model.TopP,
+		MaxTokens:        req.Model.MaxTokens,
+		Seed:             req.Model.Seed,
+		Stop:             req.Model.StopSequences,
+		PresencePenalty:  req.Model.PresencePenalty,
+		FrequencyPenalty: req.Model.FrequencyPenalty,
+		Stream:           stream,
+	}
+	// Streams only carry the first choice, so n is left out of them.
+	if req.Model.CandidateCount != nil && *req.Model.CandidateCount > 1 && !stream {
+		body.N = req.Model.CandidateCount
+	}
+	if stream {
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+	if body.MaxTokens != nil && *body.MaxTokens <= 0 {
+		body.MaxTokens = nil
+	}
+
+	if req.SystemInstruction != "" {
+		body.Messages = append(body.Messages, chatMessage{Role: "system", Content: req.SystemInstruction})
+	}
+	for _, message := range req.History {
+		role := "user"
+		if message.Role == generation.HistoryRoleModel {
+			role = "assistant"
+		}
+		body.Messages = append(body.Messages, chatMessage{Role: role, Content: message.Text})
+	}
+	body.Messages = append(body.Messages, chatMessage{Role: "user", Content: req.Prompt})
+	for _, turn := range req.Turns {
+		body.Messages = append(body.Messages, turnMessages(turn)...)
+	}
+
+	for _, fn := range req.Functions {
+		tool := chatTool{Type: "function", Function: chatFunction{Name: fn.Name, Description: fn.Description}}
+		if len(fn.Parameters) > 0 {
+			params, err := ToJSONSchema(fn.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("function %s: %w", fn.Name, err)
+			}
+			tool.Function.Parameters = params
+		}
+		body.Tools = append(body.Tools, tool)
+	}
+
+	switch {
+	case len(req.OutputSchema) > 0:
+		schema, err := ToJSONSchema(req.OutputSchema)
+		if err != nil {
+			return nil, err
+		}
+		body.ResponseFormat = &responseFormat{
+			Type:       "json_schema",
+			JSONSchema: &jsonSchemaSpec{Name: "output", Schema: schema},
+		}
+	case req.Model.MimeType == "application/json":
+		body.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	return body, nil
+}
+
+// turnMessages maps a function-calling turn onto an assistant message with
+// tool calls or one tool message per result.
+func turnMessages(turn generation.ConversationTurn) []chatMessage {
+	if len(turn.Results) > 0 {
+		messages := make([]chatMessage, 0, len(turn.Results))
+		for _, result := range turn.Results {
+			messages = append(messages, chatMessage{Role: "tool", ToolCallID: result.ID, Content: string(result.Response)})
+		}
+		return messages
+	}
+
+	message := chatMessage{Role: "assistant", Content: turn.Text}
+	for _, call := range turn.Calls {
+		args := string(call.Args)
+		if args == "" {
+			args = "{}"
+		}
+		message.ToolCalls = append(message.ToolCalls, toolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: toolCallFunction{Name: call.Name, Arguments: args},
+		})
+	}
+	return []chatMessage{message}
+}
+
+// ToJSONSchema converts a Gemini-style schema ("type": "OBJECT", "nullable": true,
+// numeric keywords encoded as strings) into the JSON Schema dialect accepted by
+// OpenAI's json_schema response format. Draft-07 schemas pass through unchanged.
+func ToJSONSchema(raw json.RawMessage) (json.RawMessage, error) {
+	var schema any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse response schema: %w", err)
+	}
+	converted, err := json.Marshal(convertSchemaNode(schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response schema: %w", err)
+	}
+	return converted, nil
+}
+
+// integerKeywords are int64 fields that Gemini schemas may encode as strings.
+var integerKeywords = map[string]bool{
+	"minItems": true, "maxItems": true, "minLength": true, "maxLength": true,
+	"minProperties": true, "maxProperties": true,
+}
+
+func convertSchemaNode(node any) any {
+	switch typed := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		nullable, _ := typed["nullable"].(bool)
+		for key, value := range typed {
+			switch {
+			case key == "nullable" || key == "propertyOrdering":
+				continue
+			case key == "type":
+				out[key] = convertSchemaType(value, nullable)
+			case integerKeywords[key]:
+				out[key] = convertSchemaInteger(value)
+			case key == "properties" || key == "$defs" || key == "definitions":
+				props, ok := value.(map[string]any)
+				if !ok {
+					out[key] = value
+					continue
+				}
+				converted := make(map[string]any, len(props))
+				for name, prop := range props {
+					converted[name] = convertSchemaNode(prop)
+				}
+				out[key] = converted
+			default:
+				out[key] = convertSchemaNode(value)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for i, item := range typed {
+			out[i] = convertSchemaNode(item)
+		}
+		return out
+	default:
+		return node
+	}
+}
+
+func convertSchemaType(value any, nullable bool) any {
+	switch typed := value.(type) {
+	case string:
+		lowered := strings.ToLower(typed)
+		if nullable && lowered != "null" {
+			return []any{lowered, "null"}
+		}
+		return lowered
+	case []any:
+		out := make([]any, 0, len(typed)+1)
+		hasNull := false
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				name = strings.ToLower(name)
+				hasNull = hasNull || name == "null"
+				out = append(out, name)
+			}
+		}
+		if nullable && !hasNull {
+			out = append(out, "null")
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func convertSchemaInteger(value any) any {
+	if text, ok := value.(string); ok {
+		if parsed, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// normaliseFinishReason maps OpenAI finish reasons onto the Gemini names already
+// stored on artifacts.
+func normaliseFinishReason(reason string) string {
+	switch reason {
+	case "stop":
+		return "STOP"
+	case "length":
+		return "MAX_TOKENS"
+	case "content_filter":
+		return "SAFETY"
+	default:
+		return strings.ToUpper(reason)
+	}
+}
+
+func errorMessage(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var parsed errorResponse
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error.Message != "" {
+		return fmt.Sprintf("%s (status %d)", parsed.Error.Message, resp.StatusCode)
+	}
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Sprintf("%s (status %d)", text, resp.StatusCode)
+	}
+	return resp.Status
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+var _ generation.Generator = (*ChatGenerator)(nil)
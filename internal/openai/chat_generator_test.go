@@ -0,0 +1,294 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"learning-core-api/internal/domain/generation"
+	"learning-core-api/internal/domain/model_configs"
+)
+
+func float32Ptr(v float32) *float32 { return &v }
+func int32Ptr(v int32) *int32       { return &v }
+
+func TestChatGenerator_Generate(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "gpt-4o-mini-2024", "choices": [{"message": {"role": "assistant", "content": "{\"questions\": []}"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 120, "completion_tokens": 30, "total_tokens": 150, "prompt_tokens_details": {"cached_tokens": 100}}}`)
+	}))
+	defer server.Close()
+
+	generator := NewChatGenerator(server.URL, "test-key", server.Client())
+	resp, err := generator.Generate(context.Background(), generation.GeneratorRequest{
+		Prompt:            "Generate questions",
+		SystemInstruction: "You are a teacher",
+		OutputSchema:      json.RawMessage(`{"type": "OBJECT", "properties": {"questions": {"type": "ARRAY", "minItems": "1", "items": {"type": "STRING", "nullable": true}}}}`),
+		Model: &generation.ModelConfig{
+			Name:        "gpt-4o-mini",
+			Temperature: float32Ptr(0.5),
+			TopP:        float32Ptr(0.9),
+			MaxTokens:   int32Ptr(256),
+			Provider:    "openai",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"questions": []}`, resp.OutputText)
+	assert.Equal(t, "STOP", resp.FinishReason)
+	assert.Equal(t, "gpt-4o-mini-2024", resp.ModelUsed)
+	assert.Equal(t, &generation.TokenUsage{PromptTokens: 120, CandidateTokens: 30, CachedTokens: 100, TotalTokens: 150}, resp.Usage)
+
+	assert.Equal(t, "gpt-4o-mini", captured["model"])
+	assert.EqualValues(t, 0.5, captured["temperature"])
+	assert.InDelta(t, 0.9, captured["top_p"], 0.0001)
+	assert.EqualValues(t, 256, captured["max_tokens"])
+
+	messages := captured["messages"].([]any)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "system", messages[0].(map[string]any)["role"])
+	assert.Equal(t, "Generate questions", messages[1].(map[string]any)["content"])
+
+	format := captured["response_format"].(map[string]any)
+	assert.Equal(t, "json_schema", format["type"])
+	schema := format["json_schema"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, "object", schema["type"])
+	questions := schema["properties"].(map[string]any)["questions"].(map[string]any)
+	assert.Equal(t, "array", questions["type"])
+	assert.EqualValues(t, 1, questions["minItems"])
+	assert.Equal(t, []any{"string", "null"}, questions["items"].(map[string]any)["type"])
+}
+
+func TestChatGenerator_JSONObjectWithoutSchema(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		fmt.Fprint(w, `{"choices": [{"message": {"content": "{}"}, "finish_reason": "length"}]}`)
+	}))
+	defer server.Close()
+
+	resp, err := NewChatGenerator(server.URL, "", server.Client()).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Reply with JSON",
+		Model:  &generation.ModelConfig{Name: "local-model", MimeType: "application/json"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "MAX_TOKENS", resp.FinishReason)
+	assert.Equal(t, "local-model", resp.ModelUsed)
+	assert.Equal(t, map[string]any{"type": "json_object"}, captured["response_format"])
+	assert.NotContains(t, captured, "temperature")
+}
+
+func TestChatGenerator_ModelBaseURLOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices": [{"message": {"content": "hi"}, "finish_reason": "stop"}]}`)
+	}))
+	defer server.Close()
+
+	generator := NewChatGenerator("http://127.0.0.1:1", "", server.Client())
+	resp, err := generator.Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Hello",
+		Model:  &generation.ModelConfig{Name: "llama", BaseURL: server.URL + "/"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", resp.OutputText)
+}
+
+func TestChatGenerator_ErrorStatusIsProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "type": "requests"}}`)
+	}))
+	defer server.Close()
+
+	_, err := NewChatGenerator(server.URL, "", server.Client()).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Hello",
+		Model:  &generation.ModelConfig{Name: "gpt-4o-mini"},
+	})
+	require.Error(t, err)
+
+	var providerErr *generation.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	assert.Equal(t, http.StatusTooManyRequests, providerErr.StatusCode)
+	assert.Contains(t, err.Error(), "Rate limit reached")
+	assert.True(t, generation.IsRetryableError(err))
+}
+
+func TestChatGenerator_GenerateStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+		assert.Equal(t, map[string]any{"include_usage": true}, body["stream_options"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"model": "gpt-4o-mini", "choices": [{"delta": {"role": "assistant"}}]}`,
+			`{"choices": [{"delta": {"content": "Hello"}}]}`,
+			`{"choices": [{"delta": {"content": ", world"}}]}`,
+			`{"choices": [{"delta": {}, "finish_reason": "stop"}]}`,
+			`{"choices": [], "usage": {"prompt_tokens": 5, "completion_tokens": 3, "total_tokens": 8}}`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	var chunks []string
+	resp, err := NewChatGenerator(server.URL, "", server.Client()).GenerateStream(context.Background(), generation.GeneratorRequest{
+		Prompt: "Greet",
+		Model:  &generation.ModelConfig{Name: "gpt-4o-mini"},
+	}, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello", ", world"}, chunks)
+	assert.Equal(t, "Hello, world", resp.OutputText)
+	assert.Equal(t, "STOP", resp.FinishReason)
+	assert.Equal(t, "gpt-4o-mini", resp.ModelUsed)
+	assert.Equal(t, &generation.TokenUsage{PromptTokens: 5, CandidateTokens: 3, TotalTokens: 8}, resp.Usage)
+}
+
+func TestChatGenerator_RejectsFileSearch(t *testing.T) {
+	_, err := NewChatGenerator("http://127.0.0.1:1", "", nil).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Hello",
+		Model:  &generation.ModelConfig{Name: "gpt-4o-mini"},
+		Tools:  []generation.ToolConfig{{Type: "file_search"}},
+	})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "file_search"))
+}
+
+func TestChatGenerator_FunctionCalling(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": null, "tool_calls": [{"id": "call_2", "type": "function", "function": {"name": "get_document_page", "arguments": "{\"page\": 2}"}}]}, "finish_reason": "tool_calls"}]}`)
+	}))
+	defer server.Close()
+
+	resp, err := NewChatGenerator(server.URL, "", server.Client()).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Summarise page 2",
+		Model:  &generation.ModelConfig{Name: "gpt-4o-mini"},
+		Functions: []generation.FunctionDeclaration{{
+			Name:       "get_document_page",
+			Parameters: json.RawMessage(`{"type": "OBJECT", "properties": {"page": {"type": "INTEGER"}}}`),
+		}},
+		Turns: []generation.ConversationTurn{
+			{Calls: []generation.FunctionCall{{ID: "call_1", Name: "get_document_page", Args: json.RawMessage(`{"page": 1}`)}}},
+			{Results: []generation.FunctionResult{{ID: "call_1", Name: "get_document_page", Response: json.RawMessage(`{"text": "Page one"}`)}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TOOL_CALLS", resp.FinishReason)
+	require.Len(t, resp.FunctionCalls, 1)
+	assert.Equal(t, "call_2", resp.FunctionCalls[0].ID)
+	assert.JSONEq(t, `{"page": 2}`, string(resp.FunctionCalls[0].Args))
+
+	tools := captured["tools"].([]any)
+	require.Len(t, tools, 1)
+	function := tools[0].(map[string]any)["function"].(map[string]any)
+	assert.Equal(t, "get_document_page", function["name"])
+	assert.Equal(t, "object", function["parameters"].(map[string]any)["type"])
+
+	messages := captured["messages"].([]any)
+	require.Len(t, messages, 3)
+	assistant := messages[1].(map[string]any)
+	assert.Equal(t, "assistant", assistant["role"])
+	toolCall := assistant["tool_calls"].([]any)[0].(map[string]any)
+	assert.Equal(t, "call_1", toolCall["id"])
+	assert.Equal(t, `{"page": 1}`, toolCall["function"].(map[string]any)["arguments"])
+	toolMessage := messages[2].(map[string]any)
+	assert.Equal(t, "tool", toolMessage["role"])
+	assert.Equal(t, "call_1", toolMessage["tool_call_id"])
+	assert.Equal(t, `{"text": "Page one"}`, toolMessage["content"])
+}
+
+func TestChatGenerator_History(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		fmt.Fprint(w, `{"choices": [{"message": {"content": "Because of mitosis."}, "finish_reason": "stop"}]}`)
+	}))
+	defer server.Close()
+
+	_, err := NewChatGenerator(server.URL, "", server.Client()).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt:            "Why do cells divide?",
+		SystemInstruction: "You are a tutor",
+		Model:             &generation.ModelConfig{Name: "gpt-4o-mini"},
+		History: []generation.HistoryMessage{
+			{Role: generation.HistoryRoleUser, Text: "What is a cell?"},
+			{Role: generation.HistoryRoleModel, Text: "The basic unit of life."},
+		},
+	})
+	require.NoError(t, err)
+
+	messages := captured["messages"].([]any)
+	require.Len(t, messages, 4)
+	roles := make([]string, 0, len(messages))
+	for _, message := range messages {
+		roles = append(roles, message.(map[string]any)["role"].(string))
+	}
+	assert.Equal(t, []string{"system", "user", "assistant", "user"}, roles)
+	assert.Equal(t, "The basic unit of life.", messages[2].(map[string]any)["content"])
+	assert.Equal(t, "Why do cells divide?", messages[3].(map[string]any)["content"])
+}
+
+func TestChatGenerator_SamplingParams(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		fmt.Fprint(w, `{"choices": [
+			{"index": 0, "message": {"content": "first"}, "finish_reason": "stop"},
+			{"index": 1, "message": {"content": "second"}, "finish_reason": "stop"}
+		]}`)
+	}))
+	defer server.Close()
+
+	resp, err := NewChatGenerator(server.URL, "", server.Client()).Generate(context.Background(), generation.GeneratorRequest{
+		Prompt: "Classify",
+		Model: &generation.ModelConfig{
+			Name:             "gpt-4o-mini",
+			Seed:             int32Ptr(42),
+			StopSequences:    []string{"###"},
+			CandidateCount:   int32Ptr(2),
+			PresencePenalty:  float32Ptr(0.5),
+			FrequencyPenalty: float32Ptr(-0.5),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 42, captured["seed"])
+	assert.Equal(t, []any{"###"}, captured["stop"])
+	assert.EqualValues(t, 2, captured["n"])
+	assert.EqualValues(t, 0.5, captured["presence_penalty"])
+	assert.EqualValues(t, -0.5, captured["frequency_penalty"])
+	assert.Equal(t, "first", resp.OutputText)
+	assert.Equal(t, []string{"first", "second"}, resp.Candidates)
+}
+
+func TestChatGenerator_RejectsGeminiOnlyParams(t *testing.T) {
+	generator := NewChatGenerator("http://127.0.0.1:1", "", nil)
+	for name, model := range map[string]*generation.ModelConfig{
+		"safety settings": {Name: "gpt-4o-mini", SafetySettings: []model_configs.SafetySetting{{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_LOW_AND_ABOVE"}}},
+		"thinking budget": {Name: "gpt-4o-mini", ThinkingBudget: int32Ptr(1024)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := generator.Generate(context.Background(), generation.GeneratorRequest{Prompt: "Hi", Model: model})
+			assert.Error(t, err)
+		})
+	}
+}
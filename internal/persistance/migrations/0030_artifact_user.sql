@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE artifacts
+  ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN artifacts.user_id IS 'User who requested the generation';
+
+CREATE INDEX IF NOT EXISTS idx_artifacts_user_id ON artifacts(user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_user_id;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS user_id;
+
+-- +goose StatementEnd
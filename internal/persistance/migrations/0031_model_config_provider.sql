@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE model_configs
+  ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT 'gemini',
+  ADD COLUMN IF NOT EXISTS base_url TEXT;
+
+ALTER TABLE model_configs
+  ADD CONSTRAINT model_configs_provider_check CHECK (provider IN ('gemini', 'openai', 'synthetic'));
+
+COMMENT ON COLUMN model_configs.provider IS 'Generator implementation used for this model (gemini, openai, synthetic)';
+COMMENT ON COLUMN model_configs.base_url IS 'Endpoint override for OpenAI-compatible providers';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE model_configs DROP CONSTRAINT IF EXISTS model_configs_provider_check;
+ALTER TABLE model_configs
+  DROP COLUMN IF EXISTS base_url,
+  DROP COLUMN IF EXISTS provider;
+
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A schema version becomes immutable as soon as an artifact references it so
+-- old prompt/schema pairs can be re-run reproducibly.
+CREATE OR REPLACE FUNCTION lock_referenced_schema_template()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.schema_template_id IS NOT NULL THEN
+        UPDATE schema_templates
+        SET locked_at = now()
+        WHERE id = NEW.schema_template_id AND locked_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS lock_artifact_schema_template ON artifacts;
+CREATE TRIGGER lock_artifact_schema_template
+    AFTER INSERT OR UPDATE OF schema_template_id ON artifacts
+    FOR EACH ROW
+    EXECUTE FUNCTION lock_referenced_schema_template();
+
+-- Lock versions already referenced by existing artifacts.
+UPDATE schema_templates
+SET locked_at = now()
+WHERE locked_at IS NULL
+  AND id IN (SELECT DISTINCT schema_template_id FROM artifacts WHERE schema_template_id IS NOT NULL);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS lock_artifact_schema_template ON artifacts;
+DROP FUNCTION IF EXISTS lock_referenced_schema_template();
+
+-- +goose StatementEnd
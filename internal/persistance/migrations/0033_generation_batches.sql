@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE model_configs
+  ADD COLUMN IF NOT EXISTS requests_per_minute INTEGER CHECK (requests_per_minute IS NULL OR requests_per_minute > 0);
+
+COMMENT ON COLUMN model_configs.requests_per_minute IS 'Process-wide request rate limit for this model; NULL means unlimited';
+
+CREATE TABLE IF NOT EXISTS generation_batches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  status TEXT NOT NULL DEFAULT 'RUNNING' CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+  request JSONB NOT NULL,
+  total INTEGER NOT NULL,
+  succeeded INTEGER NOT NULL DEFAULT 0,
+  failed INTEGER NOT NULL DEFAULT 0,
+  results JSONB NOT NULL DEFAULT '[]'::jsonb,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  completed_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE generation_batches IS 'Batch generation jobs run over many documents';
+COMMENT ON COLUMN generation_batches.request IS 'Shared instructions, output, tools and document IDs for the batch';
+COMMENT ON COLUMN generation_batches.results IS 'Per-document outcome with artifact IDs, written when the batch finishes';
+
+CREATE INDEX IF NOT EXISTS idx_generation_batches_user_id ON generation_batches(user_id);
+CREATE INDEX IF NOT EXISTS idx_generation_batches_created_at ON generation_batches(created_at DESC);
+
+DROP TRIGGER IF EXISTS update_generation_batches_updated_at ON generation_batches;
+CREATE TRIGGER update_generation_batches_updated_at
+    BEFORE UPDATE ON generation_batches
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS generation_batches;
+ALTER TABLE model_configs DROP COLUMN IF EXISTS requests_per_minute;
+
+-- +goose StatementEnd
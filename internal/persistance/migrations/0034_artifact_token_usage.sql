@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE model_configs
+  ADD COLUMN IF NOT EXISTS input_price_per_million DOUBLE PRECISION CHECK (input_price_per_million IS NULL OR input_price_per_million >= 0),
+  ADD COLUMN IF NOT EXISTS output_price_per_million DOUBLE PRECISION CHECK (output_price_per_million IS NULL OR output_price_per_million >= 0),
+  ADD COLUMN IF NOT EXISTS cached_input_price_per_million DOUBLE PRECISION CHECK (cached_input_price_per_million IS NULL OR cached_input_price_per_million >= 0);
+
+COMMENT ON COLUMN model_configs.input_price_per_million IS 'USD per million prompt tokens; NULL means cost is not tracked';
+COMMENT ON COLUMN model_configs.output_price_per_million IS 'USD per million candidate (output) tokens';
+COMMENT ON COLUMN model_configs.cached_input_price_per_million IS 'USD per million cached prompt tokens; NULL falls back to the input price';
+
+ALTER TABLE artifacts
+  ADD COLUMN IF NOT EXISTS prompt_tokens INTEGER,
+  ADD COLUMN IF NOT EXISTS candidate_tokens INTEGER,
+  ADD COLUMN IF NOT EXISTS cached_tokens INTEGER,
+  ADD COLUMN IF NOT EXISTS total_tokens INTEGER,
+  ADD COLUMN IF NOT EXISTS cost_usd DOUBLE PRECISION;
+
+COMMENT ON COLUMN artifacts.prompt_tokens IS 'Prompt tokens reported by the provider, summed over repair calls';
+COMMENT ON COLUMN artifacts.candidate_tokens IS 'Output tokens reported by the provider, summed over repair calls';
+COMMENT ON COLUMN artifacts.cached_tokens IS 'Portion of prompt_tokens served from the provider cache';
+COMMENT ON COLUMN artifacts.total_tokens IS 'Total tokens billed for the generation';
+COMMENT ON COLUMN artifacts.cost_usd IS 'Cost computed from the model config price table when the artifact was saved';
+
+CREATE INDEX IF NOT EXISTS idx_artifacts_created_at ON artifacts(created_at);
+CREATE INDEX IF NOT EXISTS idx_artifacts_meta_document_id ON artifacts((meta->>'document_id'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_meta_document_id;
+DROP INDEX IF EXISTS idx_artifacts_created_at;
+
+ALTER TABLE artifacts
+  DROP COLUMN IF EXISTS cost_usd,
+  DROP COLUMN IF EXISTS total_tokens,
+  DROP COLUMN IF EXISTS cached_tokens,
+  DROP COLUMN IF EXISTS candidate_tokens,
+  DROP COLUMN IF EXISTS prompt_tokens;
+
+ALTER TABLE model_configs
+  DROP COLUMN IF EXISTS cached_input_price_per_million,
+  DROP COLUMN IF EXISTS output_price_per_million,
+  DROP COLUMN IF EXISTS input_price_per_million;
+
+-- +goose StatementEnd
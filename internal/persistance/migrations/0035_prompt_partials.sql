@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS prompt_partials (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL CHECK (name ~ '^[a-z][a-z0-9_]*$'),
+  version INTEGER NOT NULL,
+  is_active BOOLEAN NOT NULL DEFAULT false,
+  description TEXT,
+  template TEXT NOT NULL,
+  created_by TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (name, version)
+);
+
+COMMENT ON TABLE prompt_partials IS 'Versioned prompt fragments included by prompt templates with {{template "name" .}}';
+COMMENT ON COLUMN prompt_partials.name IS 'Template name used to include the partial';
+
+CREATE INDEX IF NOT EXISTS idx_prompt_partials_name_active ON prompt_partials(name, is_active);
+
+DROP TRIGGER IF EXISTS update_prompt_partials_updated_at ON prompt_partials;
+CREATE TRIGGER update_prompt_partials_updated_at
+    BEFORE UPDATE ON prompt_partials
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS prompt_partials;
+
+-- +goose StatementEnd
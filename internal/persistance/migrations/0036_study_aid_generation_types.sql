@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TYPE generation_type ADD VALUE IF NOT EXISTS 'FLASHCARDS';
+ALTER TYPE generation_type ADD VALUE IF NOT EXISTS 'SUMMARY';
+ALTER TYPE generation_type ADD VALUE IF NOT EXISTS 'OUTLINE';
+ALTER TYPE generation_type ADD VALUE IF NOT EXISTS 'HINT';
+
+ALTER TYPE artifact_type ADD VALUE IF NOT EXISTS 'FLASHCARDS';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Enum values cannot be removed safely; no-op rollback.
+SELECT 1;
+
+-- +goose StatementEnd
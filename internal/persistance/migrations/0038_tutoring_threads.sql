@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TYPE generation_type ADD VALUE IF NOT EXISTS 'TUTORING';
+
+CREATE TABLE IF NOT EXISTS tutoring_threads (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+  title TEXT NOT NULL,
+  model_config_id UUID REFERENCES model_configs(id) ON DELETE SET NULL,
+  summary TEXT,
+  summarized_through INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE tutoring_threads IS 'Tutoring conversations between a learner and the model about one document';
+COMMENT ON COLUMN tutoring_threads.model_config_id IS 'Model used for the thread; NULL uses the active model config';
+COMMENT ON COLUMN tutoring_threads.summary IS 'Summary of the messages older than the history window';
+COMMENT ON COLUMN tutoring_threads.summarized_through IS 'Sequence number of the last message folded into summary';
+
+CREATE INDEX IF NOT EXISTS idx_tutoring_threads_user_id ON tutoring_threads(user_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tutoring_threads_document_id ON tutoring_threads(document_id);
+
+DROP TRIGGER IF EXISTS update_tutoring_threads_updated_at ON tutoring_threads;
+CREATE TRIGGER update_tutoring_threads_updated_at
+    BEFORE UPDATE ON tutoring_threads
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS tutoring_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  thread_id UUID NOT NULL REFERENCES tutoring_threads(id) ON DELETE CASCADE,
+  seq INTEGER NOT NULL CHECK (seq > 0),
+  role TEXT NOT NULL CHECK (role IN ('user', 'assistant')),
+  content TEXT NOT NULL,
+  artifact_id UUID REFERENCES artifacts(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (thread_id, seq)
+);
+
+COMMENT ON TABLE tutoring_messages IS 'Messages of a tutoring thread in conversation order';
+COMMENT ON COLUMN tutoring_messages.artifact_id IS 'Artifact that produced an assistant message';
+
+ALTER TABLE artifacts
+  ADD COLUMN IF NOT EXISTS thread_id UUID REFERENCES tutoring_threads(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN artifacts.thread_id IS 'Tutoring thread the artifact was generated for';
+
+CREATE INDEX IF NOT EXISTS idx_artifacts_thread_id ON artifacts(thread_id) WHERE thread_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_thread_id;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS thread_id;
+DROP TABLE IF EXISTS tutoring_messages;
+DROP TABLE IF EXISTS tutoring_threads;
+
+-- Enum values cannot be removed safely; TUTORING stays in generation_type.
+
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS prompt_experiments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  description TEXT,
+  generation_type generation_type NOT NULL,
+  status TEXT NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'RUNNING', 'STOPPED')),
+  created_by TEXT,
+  started_at TIMESTAMPTZ,
+  stopped_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS prompt_experiment_variants (
+  experiment_id UUID NOT NULL REFERENCES prompt_experiments(id) ON DELETE CASCADE,
+  prompt_template_id UUID NOT NULL REFERENCES prompt_templates(id),
+  weight INTEGER NOT NULL CHECK (weight > 0),
+  PRIMARY KEY (experiment_id, prompt_template_id)
+);
+
+COMMENT ON TABLE prompt_experiments IS 'A/B experiments splitting generation traffic across prompt template versions';
+COMMENT ON COLUMN prompt_experiments.status IS 'DRAFT, RUNNING or STOPPED; at most one experiment per generation type runs at a time';
+COMMENT ON COLUMN prompt_experiment_variants.weight IS 'Relative share of generations rendered with this template';
+
+-- Only one experiment may split traffic for a generation type.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_experiments_running
+  ON prompt_experiments(generation_type) WHERE status = 'RUNNING';
+
+-- Experiment reports select artifacts by the experiment recorded in meta.
+CREATE INDEX IF NOT EXISTS idx_artifacts_meta_experiment
+  ON artifacts((meta->'experiment'->>'id')) WHERE meta ? 'experiment';
+
+DROP TRIGGER IF EXISTS update_prompt_experiments_updated_at ON prompt_experiments;
+CREATE TRIGGER update_prompt_experiments_updated_at
+    BEFORE UPDATE ON prompt_experiments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_meta_experiment;
+DROP TABLE IF EXISTS prompt_experiment_variants;
+DROP TABLE IF EXISTS prompt_experiments;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE model_configs
+  ADD COLUMN IF NOT EXISTS seed INTEGER,
+  ADD COLUMN IF NOT EXISTS stop_sequences TEXT[],
+  ADD COLUMN IF NOT EXISTS candidate_count INTEGER CHECK (candidate_count IS NULL OR candidate_count BETWEEN 1 AND 8),
+  ADD COLUMN IF NOT EXISTS presence_penalty DOUBLE PRECISION CHECK (presence_penalty IS NULL OR presence_penalty BETWEEN -2 AND 2),
+  ADD COLUMN IF NOT EXISTS frequency_penalty DOUBLE PRECISION CHECK (frequency_penalty IS NULL OR frequency_penalty BETWEEN -2 AND 2),
+  ADD COLUMN IF NOT EXISTS thinking_budget INTEGER CHECK (thinking_budget IS NULL OR thinking_budget >= -1),
+  ADD COLUMN IF NOT EXISTS safety_settings JSONB;
+
+COMMENT ON COLUMN model_configs.seed IS 'Sampling seed for reproducible output; NULL lets the provider choose';
+COMMENT ON COLUMN model_configs.stop_sequences IS 'Sequences that end generation when produced';
+COMMENT ON COLUMN model_configs.candidate_count IS 'Number of candidates to request; the first schema-valid one is kept';
+COMMENT ON COLUMN model_configs.thinking_budget IS 'Thinking token budget; 0 disables thinking and -1 lets the model decide';
+COMMENT ON COLUMN model_configs.safety_settings IS 'Per-category block thresholds as [{"category", "threshold"}]';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE model_configs
+  DROP COLUMN IF EXISTS safety_settings,
+  DROP COLUMN IF EXISTS thinking_budget,
+  DROP COLUMN IF EXISTS frequency_penalty,
+  DROP COLUMN IF EXISTS presence_penalty,
+  DROP COLUMN IF EXISTS candidate_count,
+  DROP COLUMN IF EXISTS stop_sequences,
+  DROP COLUMN IF EXISTS seed;
+
+-- +goose StatementEnd
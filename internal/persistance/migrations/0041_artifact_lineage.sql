@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE artifacts
+  ADD COLUMN IF NOT EXISTS parent_artifact_id UUID REFERENCES artifacts(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS derivation TEXT CHECK (derivation IN ('REGENERATE', 'REFINE'));
+
+COMMENT ON COLUMN artifacts.parent_artifact_id IS 'Artifact this one was regenerated or refined from';
+COMMENT ON COLUMN artifacts.derivation IS 'REGENERATE (same inputs, optional overrides) or REFINE (previous output plus feedback); NULL for fresh generations';
+
+CREATE INDEX IF NOT EXISTS idx_artifacts_parent_artifact_id ON artifacts(parent_artifact_id) WHERE parent_artifact_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_parent_artifact_id;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS derivation;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS parent_artifact_id;
+
+-- +goose StatementEnd
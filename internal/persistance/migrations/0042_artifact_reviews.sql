@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE artifacts
+  ADD COLUMN IF NOT EXISTS review_requested_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN artifacts.review_requested_at IS 'When a reviewer was first assigned (status moved to IN_REVIEW)';
+COMMENT ON COLUMN artifacts.reviewed_at IS 'When the reviewer approved or rejected the artifact';
+
+CREATE TABLE IF NOT EXISTS artifact_review_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  artifact_id UUID NOT NULL REFERENCES artifacts(id) ON DELETE CASCADE,
+  actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  action TEXT NOT NULL CHECK (action IN ('ASSIGN', 'COMMENT', 'APPROVE', 'REJECT')),
+  from_status TEXT NOT NULL,
+  to_status TEXT NOT NULL,
+  reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  comment TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE artifact_review_events IS 'Review history of an artifact: reviewer assignments, comments and decisions';
+COMMENT ON COLUMN artifact_review_events.reviewer_id IS 'Reviewer assigned by an ASSIGN event';
+
+CREATE INDEX IF NOT EXISTS idx_artifact_review_events_artifact_id ON artifact_review_events(artifact_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_artifacts_reviewer_status ON artifacts(reviewer_id, status) WHERE reviewer_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_artifacts_reviewer_status;
+DROP TABLE IF EXISTS artifact_review_events;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS review_requested_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+COMMENT ON COLUMN generation_batches.results IS 'Per-document outcome with artifact IDs, appended as each document finishes and rewritten in request order when the batch finishes';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+COMMENT ON COLUMN generation_batches.results IS 'Per-document outcome with artifact IDs, written when the batch finishes';
+
+-- +goose StatementEnd
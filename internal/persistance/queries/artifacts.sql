@@ -26,9 +26,12 @@ SELECT * FROM artifacts ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 INSERT INTO artifacts (
   type, generation_type, status, eval_id, eval_item_id, attempt_id, reviewer_id,
   text, output_json, model, prompt, prompt_template_id, schema_template_id,
-  model_params, prompt_render, input_hash, meta, error
+  model_params, prompt_render, input_hash, meta, error, user_id,
+  prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id,
+  parent_artifact_id, derivation
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+  $20, $21, $22, $23, $24, $25, $26, $27
 ) RETURNING *;
 
 -- name: GetArtifactsByTypeAndEntity :many
@@ -63,7 +66,13 @@ SELECT
   COUNT(CASE WHEN status = 'READY' THEN 1 END) as ready_count,
   COUNT(CASE WHEN status = 'PENDING' THEN 1 END) as pending_count,
   COUNT(CASE WHEN status = 'ERROR' THEN 1 END) as error_count,
-  COUNT(CASE WHEN error IS NOT NULL THEN 1 END) as with_errors
+  COUNT(CASE WHEN status = 'INVALID' THEN 1 END) as invalid_count,
+  COUNT(CASE WHEN error IS NOT NULL THEN 1 END) as with_errors,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
 FROM artifacts;
 
 -- name: ListArtifactsByType :many
@@ -78,3 +87,158 @@ WHERE type = $1;
 
 -- name: CountArtifacts :one
 SELECT COUNT(*) FROM artifacts;
+
+-- name: ListGenerationArtifacts :many
+SELECT * FROM artifacts
+WHERE generation_type IS NOT NULL OR user_id IS NOT NULL
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountGenerationArtifacts :one
+SELECT COUNT(*) FROM artifacts
+WHERE generation_type IS NOT NULL OR user_id IS NOT NULL;
+
+-- name: ListGenerationArtifactsByUser :many
+SELECT * FROM artifacts
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountGenerationArtifactsByUser :one
+SELECT COUNT(*) FROM artifacts
+WHERE user_id = $1;
+
+-- name: GetLatestReusableArtifactByInputHash :one
+SELECT * FROM artifacts
+WHERE input_hash = $1 AND status IN ('READY', 'APPROVED')
+ORDER BY status = 'APPROVED' DESC, created_at DESC
+LIMIT 1;
+
+-- name: GetArtifactUsageByUser :many
+SELECT
+  user_id,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= @from_time AND created_at < @to_time
+GROUP BY user_id
+ORDER BY cost_usd DESC, total_tokens DESC;
+
+-- name: GetArtifactUsageByGenerationType :many
+SELECT
+  generation_type,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= @from_time AND created_at < @to_time
+GROUP BY generation_type
+ORDER BY cost_usd DESC, total_tokens DESC;
+
+-- name: GetArtifactUsageByDocument :many
+SELECT
+  (meta->>'document_id')::text as document_id,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= @from_time AND created_at < @to_time
+  AND meta->>'document_id' IS NOT NULL
+GROUP BY meta->>'document_id'
+ORDER BY cost_usd DESC, total_tokens DESC;
+
+-- name: GetArtifactUsageByDay :many
+SELECT
+  date_trunc('day', created_at AT TIME ZONE 'UTC')::date as day,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= @from_time AND created_at < @to_time
+GROUP BY day
+ORDER BY day;
+
+-- name: LinkArtifactEval :one
+UPDATE artifacts SET eval_id = $2
+WHERE id = $1 AND eval_id IS NULL
+RETURNING *;
+
+
+-- name: GetArtifactLineage :many
+-- Every artifact in the lineage tree containing the given artifact: its root
+-- ancestor and all of the root's descendants, parents before children.
+WITH RECURSIVE ancestors AS (
+  SELECT a.id, a.parent_artifact_id, 0 AS depth
+  FROM artifacts a
+  WHERE a.id = @artifact_id::uuid
+  UNION ALL
+  SELECT p.id, p.parent_artifact_id, anc.depth + 1
+  FROM artifacts p
+  JOIN ancestors anc ON p.id = anc.parent_artifact_id
+  WHERE anc.depth < 100
+),
+root AS (
+  SELECT id FROM ancestors ORDER BY depth DESC LIMIT 1
+),
+tree AS (
+  SELECT a.id, 0 AS depth
+  FROM artifacts a
+  JOIN root ON root.id = a.id
+  UNION ALL
+  SELECT c.id, tree.depth + 1
+  FROM artifacts c
+  JOIN tree ON c.parent_artifact_id = tree.id
+  WHERE tree.depth < 100
+)
+SELECT a.id, a.parent_artifact_id, a.derivation, a.generation_type, a.status, a.model,
+  a.user_id, COALESCE(a.meta->'lineage'->>'feedback', '')::text AS feedback, a.created_at, tree.depth::int AS depth
+FROM tree
+JOIN artifacts a ON a.id = tree.id
+ORDER BY tree.depth, a.created_at;
+
+-- name: AssignArtifactReviewer :one
+UPDATE artifacts SET
+  status = 'IN_REVIEW',
+  reviewer_id = @reviewer_id,
+  review_requested_at = COALESCE(review_requested_at, now())
+WHERE id = @id AND status = @from_status
+RETURNING *;
+
+-- name: CompleteArtifactReview :one
+UPDATE artifacts SET
+  status = @to_status,
+  reviewed_at = now()
+WHERE id = @id AND status = 'IN_REVIEW'
+RETURNING *;
+
+-- name: ListArtifactsByReviewerAndStatus :many
+SELECT * FROM artifacts
+WHERE reviewer_id = @reviewer_id
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY review_requested_at DESC NULLS LAST, created_at DESC
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: CreateArtifactReviewEvent :one
+INSERT INTO artifact_review_events (
+  artifact_id, actor_id, action, from_status, to_status, reviewer_id, comment
+) VALUES (
+  @artifact_id, @actor_id, @action, @from_status, @to_status, @reviewer_id, @comment
+) RETURNING *;
+
+-- name: ListArtifactReviewEvents :many
+SELECT * FROM artifact_review_events
+WHERE artifact_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,49 @@
+-- name: CreateGenerationBatch :one
+INSERT INTO generation_batches (user_id, request, total)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetGenerationBatch :one
+SELECT * FROM generation_batches WHERE id = $1 LIMIT 1;
+
+-- name: ListGenerationBatches :many
+SELECT * FROM generation_batches
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountGenerationBatches :one
+SELECT COUNT(*) FROM generation_batches;
+
+-- name: ListGenerationBatchesByUser :many
+SELECT * FROM generation_batches
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountGenerationBatchesByUser :one
+SELECT COUNT(*) FROM generation_batches WHERE user_id = $1;
+
+-- name: AppendGenerationBatchResult :exec
+-- Records one finished document while the batch runs. Results are in
+-- completion order until CompleteGenerationBatch rewrites them in request order.
+UPDATE generation_batches
+SET succeeded = @succeeded, failed = @failed, results = results || jsonb_build_array(@result::jsonb)
+WHERE id = @id;
+
+-- name: FailRunningGenerationBatches :execrows
+UPDATE generation_batches
+SET status = 'FAILED',
+    error = @reason::text,
+    completed_at = now()
+WHERE status = 'RUNNING';
+
+-- name: CompleteGenerationBatch :one
+UPDATE generation_batches
+SET status = $2,
+    succeeded = $3,
+    failed = $4,
+    results = $5,
+    error = $6,
+    completed_at = now()
+WHERE id = $1
+RETURNING *;
@@ -1,10 +1,14 @@
 -- name: CreateModelConfig :one
 WITH inserted AS (
   INSERT INTO model_configs (
-    version, model_name, display_name, temperature, max_tokens, top_p, top_k, mime_type, is_active, created_by
+    version, model_name, display_name, temperature, max_tokens, top_p, top_k, mime_type, is_active, created_by,
+    provider, base_url, requests_per_minute,
+    input_price_per_million, output_price_per_million, cached_input_price_per_million,
+    seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings
   ) VALUES (
     (SELECT COALESCE(MAX(version), 0) + 1 FROM model_configs),
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+    $16, $17, $18, $19, $20, $21, $22
   )
   RETURNING *
 ),
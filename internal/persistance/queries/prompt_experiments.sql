@@ -0,0 +1,87 @@
+-- name: CreatePromptExperiment :one
+WITH inserted AS (
+  INSERT INTO prompt_experiments (
+    name, description, generation_type, created_by
+  ) VALUES (
+    @name, @description, @generation_type, @created_by
+  )
+  RETURNING *
+),
+variants AS (
+  INSERT INTO prompt_experiment_variants (experiment_id, prompt_template_id, weight)
+  SELECT inserted.id, unnest(@prompt_template_ids::uuid[]), unnest(@weights::int[])
+  FROM inserted
+)
+SELECT * FROM inserted;
+
+-- name: GetPromptExperiment :one
+SELECT * FROM prompt_experiments WHERE id = $1 LIMIT 1;
+
+-- name: GetRunningPromptExperiment :one
+SELECT * FROM prompt_experiments WHERE generation_type = $1 AND status = 'RUNNING' LIMIT 1;
+
+-- name: ListPromptExperiments :many
+SELECT * FROM prompt_experiments
+WHERE sqlc.narg(generation_type)::generation_type IS NULL OR generation_type = sqlc.narg(generation_type)::generation_type
+ORDER BY created_at DESC;
+
+-- name: ListPromptExperimentVariants :many
+SELECT v.experiment_id, v.prompt_template_id, v.weight, pt.version AS prompt_version
+FROM prompt_experiment_variants v
+JOIN prompt_templates pt ON pt.id = v.prompt_template_id
+WHERE v.experiment_id = $1
+ORDER BY pt.version ASC;
+
+-- name: StartPromptExperiment :one
+UPDATE prompt_experiments SET
+  status = 'RUNNING',
+  started_at = now()
+WHERE id = $1 AND status = 'DRAFT'
+RETURNING *;
+
+-- name: StopPromptExperiment :one
+UPDATE prompt_experiments SET
+  status = 'STOPPED',
+  stopped_at = now()
+WHERE id = $1 AND status = 'RUNNING'
+RETURNING *;
+
+-- name: GetPromptExperimentReport :many
+-- Per-variant counts of the experiment's artifacts and the latest verdict of
+-- the given eval type for the eval items generated from them.
+WITH experiment_artifacts AS (
+  SELECT a.id, a.status, a.eval_item_id, a.prompt_template_id
+  FROM artifacts a
+  WHERE a.meta ? 'experiment'
+    AND a.meta->'experiment'->>'id' = (@experiment_id::uuid)::text
+),
+latest_results AS (
+  SELECT DISTINCT ON (er.eval_item_id) er.eval_item_id, er.verdict, er.score
+  FROM eval_results er
+  WHERE er.eval_type = @eval_type::text
+  ORDER BY er.eval_item_id, er.created_at DESC
+),
+artifact_results AS (
+  SELECT ea.id AS artifact_id, lr.verdict, lr.score
+  FROM experiment_artifacts ea
+  JOIN eval_items ei ON ei.id = ea.eval_item_id OR ei.metadata->>'artifact_id' = ea.id::text
+  JOIN latest_results lr ON lr.eval_item_id = ei.id
+)
+SELECT
+  v.prompt_template_id,
+  pt.version AS prompt_version,
+  v.weight,
+  COUNT(DISTINCT ea.id)::bigint AS artifact_count,
+  (COUNT(DISTINCT ea.id) FILTER (WHERE ea.status NOT IN ('ERROR', 'INVALID')))::bigint AS usable_count,
+  (COUNT(ar.verdict))::bigint AS evaluated_count,
+  (COUNT(ar.verdict) FILTER (WHERE ar.verdict = 'PASS'))::bigint AS passed,
+  (COUNT(ar.verdict) FILTER (WHERE ar.verdict = 'FAIL'))::bigint AS failed,
+  (COUNT(ar.verdict) FILTER (WHERE ar.verdict = 'WARN'))::bigint AS warned,
+  COALESCE(AVG(ar.score), 0)::float8 AS avg_score
+FROM prompt_experiment_variants v
+JOIN prompt_templates pt ON pt.id = v.prompt_template_id
+LEFT JOIN experiment_artifacts ea ON ea.prompt_template_id = v.prompt_template_id
+LEFT JOIN artifact_results ar ON ar.artifact_id = ea.id
+WHERE v.experiment_id = @experiment_id::uuid
+GROUP BY v.prompt_template_id, pt.version, v.weight
+ORDER BY pt.version ASC;
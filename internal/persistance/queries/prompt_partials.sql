@@ -0,0 +1,54 @@
+-- name: GetPromptPartial :one
+SELECT * FROM prompt_partials WHERE id = $1 LIMIT 1;
+
+-- name: GetActivePromptPartialByName :one
+SELECT * FROM prompt_partials WHERE name = $1 AND is_active = true LIMIT 1;
+
+-- name: GetPromptPartialByNameAndVersion :one
+SELECT * FROM prompt_partials WHERE name = $1 AND version = $2 LIMIT 1;
+
+-- name: ListPromptPartialVersions :many
+SELECT * FROM prompt_partials WHERE name = $1 ORDER BY version DESC;
+
+-- name: ListActivePromptPartials :many
+SELECT * FROM prompt_partials WHERE is_active = true ORDER BY name ASC;
+
+-- name: CreatePromptPartialVersion :one
+WITH inserted AS (
+  INSERT INTO prompt_partials (
+    name, version, is_active, description, template, created_by
+  ) VALUES (
+    $1,
+    (SELECT COALESCE(MAX(version), 0) + 1 FROM prompt_partials WHERE name = $1),
+    $2, $3, $4, $5
+  )
+  RETURNING *
+),
+deactivated AS (
+  UPDATE prompt_partials SET
+    is_active = false,
+    updated_at = now()
+  WHERE name = (SELECT name FROM inserted)
+    AND id != (SELECT id FROM inserted)
+    AND (SELECT is_active FROM inserted) = true
+)
+SELECT * FROM inserted;
+
+-- name: ActivatePromptPartial :one
+WITH target AS (
+  SELECT name FROM prompt_partials WHERE prompt_partials.id = $1
+),
+deactivated AS (
+  UPDATE prompt_partials SET
+    is_active = false,
+    updated_at = now()
+  WHERE name = (SELECT name FROM target) AND id != $1
+),
+activated AS (
+  UPDATE prompt_partials SET
+    is_active = true,
+    updated_at = now()
+  WHERE id = $1
+  RETURNING *
+)
+SELECT * FROM activated;
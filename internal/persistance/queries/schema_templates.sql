@@ -4,6 +4,9 @@ SELECT * FROM schema_templates WHERE id = $1 LIMIT 1;
 -- name: GetActiveSchemaTemplateByGenerationType :one
 SELECT * FROM schema_templates WHERE generation_type = $1 AND is_active = true LIMIT 1;
 
+-- name: GetSchemaTemplateByGenerationTypeAndVersion :one
+SELECT * FROM schema_templates WHERE generation_type = $1 AND version = $2 LIMIT 1;
+
 -- name: ListSchemaTemplatesByGenerationType :many
 SELECT * FROM schema_templates
 WHERE generation_type = $1
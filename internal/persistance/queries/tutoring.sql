@@ -0,0 +1,49 @@
+-- name: CreateTutoringThread :one
+INSERT INTO tutoring_threads (user_id, document_id, title, model_config_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetTutoringThread :one
+SELECT * FROM tutoring_threads
+WHERE id = $1;
+
+-- name: ListTutoringThreadsByUser :many
+SELECT * FROM tutoring_threads
+WHERE user_id = @user_id
+  AND (sqlc.narg('document_id')::uuid IS NULL OR document_id = sqlc.narg('document_id')::uuid)
+ORDER BY updated_at DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: UpdateTutoringThreadSummary :one
+UPDATE tutoring_threads
+SET summary = $2, summarized_through = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: TouchTutoringThread :exec
+UPDATE tutoring_threads SET updated_at = now()
+WHERE id = $1;
+
+-- name: LockTutoringThread :one
+SELECT id FROM tutoring_threads
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CreateTutoringMessage :one
+INSERT INTO tutoring_messages (thread_id, seq, role, content, artifact_id)
+VALUES (
+  @thread_id,
+  (SELECT COALESCE(MAX(seq), 0) + 1 FROM tutoring_messages WHERE thread_id = @thread_id),
+  @role, @content, @artifact_id
+)
+RETURNING *;
+
+-- name: ListTutoringMessages :many
+SELECT * FROM tutoring_messages
+WHERE thread_id = $1
+ORDER BY seq ASC;
+
+-- name: ListTutoringMessagesAfter :many
+SELECT * FROM tutoring_messages
+WHERE thread_id = @thread_id AND seq > @after_seq
+ORDER BY seq ASC;
@@ -28,6 +28,18 @@ const (
 	questionsSchemaSeed    = "questions_schema.json"
 	sectionTopicsPromptSeed = "section_topics_prompt.txt"
 	sectionTopicsSchemaSeed = "section_topics_schema.json"
+	flashcardsPromptSeed    = "flashcards_prompt.txt"
+	flashcardsSchemaSeed    = "flashcards_schema.json"
+	summaryPromptSeed       = "summary_prompt.txt"
+	summarySchemaSeed       = "summary_schema.json"
+	outlinePromptSeed       = "outline_prompt.txt"
+	outlineSchemaSeed       = "outline_schema.json"
+	hintPromptSeed          = "hint_prompt.txt"
+	hintSchemaSeed          = "hint_schema.json"
+	multipleChoicePromptSeed = "multiple_choice_prompt.txt"
+	multipleChoiceSchemaSeed = "multiple_choice_schema.json"
+	tutoringPromptSeed = "tutoring_prompt.txt"
+	groundingRulesPartialSeed = "grounding_rules_partial.txt"
 	chunkingConfigSeedFile = "chunking_config.json"
 
 	systemSeedEmail    = "admin@test.local"
@@ -87,6 +99,10 @@ func RunWithQueries(ctx context.Context, queries *store.Queries) error {
 		return fmt.Errorf("failed to seed chunking configs: %w", err)
 	}
 
+	if err := seedPromptPartials(ctx, queries); err != nil {
+		return fmt.Errorf("failed to seed prompt partials: %w", err)
+	}
+
 	if err := seedPromptTemplates(ctx, queries); err != nil {
 		return fmt.Errorf("failed to seed prompt templates: %w", err)
 	}
@@ -121,6 +137,7 @@ func seedModelConfig(ctx context.Context, queries *store.Queries, createdBy uuid
 		MimeType:    sql.NullString{String: "application/json", Valid: true},
 		IsActive:    true,
 		CreatedBy:   createdBy,
+		Provider:    "gemini",
 	})
 	if err != nil {
 		return err
@@ -250,12 +267,70 @@ func seedSystemInstructions(ctx context.Context, queries *store.Queries, created
 	return nil
 }
 
+func seedPromptPartials(ctx context.Context, queries *store.Queries) error {
+	seeds := []struct {
+		filename    string
+		name        string
+		description string
+	}{
+		{
+			filename:    groundingRulesPartialSeed,
+			name:        "grounding_rules",
+			description: "Seed partial restricting output to the source documents",
+		},
+	}
+
+	for _, def := range seeds {
+		path, err := seedPath(def.filename)
+		if err != nil {
+			return err
+		}
+		partialText, ok, err := readSeedText(path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			log.Printf("no prompt partial seed found in %s", path)
+			continue
+		}
+
+		partialText = strings.TrimSpace(partialText)
+		if partialText == "" {
+			return fmt.Errorf("prompt partial seed is empty: %s", path)
+		}
+
+		existing, err := queries.ListPromptPartialVersions(ctx, def.name)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			log.Printf("prompt partial already exists: name=%s", def.name)
+			continue
+		}
+
+		_, err = queries.CreatePromptPartialVersion(ctx, store.CreatePromptPartialVersionParams{
+			Name:        def.name,
+			IsActive:    true,
+			Description: sql.NullString{String: def.description, Valid: true},
+			Template:    partialText,
+			CreatedBy:   sql.NullString{String: systemSeedEmail, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("seeded prompt partial: name=%s", def.name)
+	}
+
+	return nil
+}
+
 func seedPromptTemplates(ctx context.Context, queries *store.Queries) error {
 	type promptSeedDefinition struct {
 		filename       string
 		generationType utils.GenerationType
 		title          string
 		description    string
+		metadata       json.RawMessage
 	}
 
 	seeds := []promptSeedDefinition{
@@ -270,6 +345,7 @@ func seedPromptTemplates(ctx context.Context, queries *store.Queries) error {
 			generationType: utils.GenerationTypeQuestions,
 			title:          "Question Generation Prompt",
 			description:    "Seed prompt template for question generation",
+			metadata:       json.RawMessage(`{"variables": [{"name": "question_count", "type": "integer", "required": false, "default": 5, "description": "Number of questions to generate"}]}`),
 		},
 		{
 			filename:       sectionTopicsPromptSeed,
@@ -277,6 +353,47 @@ func seedPromptTemplates(ctx context.Context, queries *store.Queries) error {
 			title:          "Section Topics Prompt",
 			description:    "Seed prompt template for section topic extraction",
 		},
+		{
+			filename:       flashcardsPromptSeed,
+			generationType: utils.GenerationTypeFlashcards,
+			title:          "Flashcards Prompt",
+			description:    "Seed prompt template for flashcard generation",
+			metadata:       json.RawMessage(`{"variables": [{"name": "card_count", "type": "integer", "required": false, "default": 10, "description": "Number of flashcards to generate"}]}`),
+		},
+		{
+			filename:       summaryPromptSeed,
+			generationType: utils.GenerationTypeSummary,
+			title:          "Summary Prompt",
+			description:    "Seed prompt template for document summaries",
+			metadata:       json.RawMessage(`{"variables": [{"name": "max_words", "type": "integer", "required": false, "default": 200, "description": "Upper bound on summary length in words"}]}`),
+		},
+		{
+			filename:       outlinePromptSeed,
+			generationType: utils.GenerationTypeOutline,
+			title:          "Outline Prompt",
+			description:    "Seed prompt template for study outlines",
+		},
+		{
+			filename:       hintPromptSeed,
+			generationType: utils.GenerationTypeHint,
+			title:          "Hint Prompt",
+			description:    "Seed prompt template for learner hints",
+			metadata:       json.RawMessage(`{"variables": [{"name": "question", "type": "string", "required": true, "description": "Question the learner is stuck on"}, {"name": "learner_answer", "type": "string", "required": false, "default": "", "description": "Learner's current answer, if any"}]}`),
+		},
+		{
+			filename:       multipleChoicePromptSeed,
+			generationType: utils.GenerationTypeMultipleChoice,
+			title:          "Multiple-Choice Question Prompt",
+			description:    "Seed prompt template for multiple-choice questions with distractors",
+			metadata:       json.RawMessage(`{"variables": [{"name": "question_count", "type": "integer", "required": false, "default": 5, "description": "Number of questions to generate"}, {"name": "option_count", "type": "integer", "required": false, "default": 4, "description": "Number of options per question"}]}`),
+		},
+		{
+			filename:       tutoringPromptSeed,
+			generationType: utils.GenerationTypeTutoring,
+			title:          "Tutoring Prompt",
+			description:    "Seed prompt template for tutoring conversation turns",
+			metadata:       json.RawMessage(`{"variables": [{"name": "question", "type": "string", "required": true, "description": "Learner's latest message"}, {"name": "conversation_summary", "type": "string", "required": false, "default": "", "description": "Summary of the turns older than the history window"}, {"name": "document_title", "type": "string", "required": false, "default": "", "description": "Title of the document the thread is about"}]}`),
+		},
 	}
 
 	for _, def := range seeds {
@@ -303,6 +420,7 @@ func seedPromptTemplates(ctx context.Context, queries *store.Queries) error {
 			Title:          def.title,
 			Description:    stringPtr(def.description),
 			Template:       promptText,
+			Metadata:       def.metadata,
 			CreatedBy:      stringPtr(systemSeedEmail),
 		}
 
@@ -384,6 +502,26 @@ func seedSchemaTemplates(ctx context.Context, queries *store.Queries, createdBy
 			filename:       sectionTopicsSchemaSeed,
 			generationType: utils.GenerationTypeSectionTopics,
 		},
+		{
+			filename:       flashcardsSchemaSeed,
+			generationType: utils.GenerationTypeFlashcards,
+		},
+		{
+			filename:       summarySchemaSeed,
+			generationType: utils.GenerationTypeSummary,
+		},
+		{
+			filename:       outlineSchemaSeed,
+			generationType: utils.GenerationTypeOutline,
+		},
+		{
+			filename:       hintSchemaSeed,
+			generationType: utils.GenerationTypeHint,
+		},
+		{
+			filename:       multipleChoiceSchemaSeed,
+			generationType: utils.GenerationTypeMultipleChoice,
+		},
 	}
 
 	for _, def := range seeds {
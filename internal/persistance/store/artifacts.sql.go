@@ -8,11 +8,118 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
+const assignArtifactReviewer = `-- name: AssignArtifactReviewer :one
+UPDATE artifacts SET
+  status = 'IN_REVIEW',
+  reviewer_id = $1,
+  review_requested_at = COALESCE(review_requested_at, now())
+WHERE id = $2 AND status = $3
+RETURNING id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at
+`
+
+type AssignArtifactReviewerParams struct {
+	ReviewerID uuid.NullUUID `json:"reviewer_id"`
+	ID         uuid.UUID     `json:"id"`
+	FromStatus string        `json:"from_status"`
+}
+
+func (q *Queries) AssignArtifactReviewer(ctx context.Context, arg AssignArtifactReviewerParams) (Artifact, error) {
+	row := q.db.QueryRowContext(ctx, assignArtifactReviewer, arg.ReviewerID, arg.ID, arg.FromStatus)
+	var i Artifact
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Status,
+		&i.EvalID,
+		&i.EvalItemID,
+		&i.AttemptID,
+		&i.ReviewerID,
+		&i.Text,
+		&i.OutputJson,
+		&i.Model,
+		&i.Prompt,
+		&i.InputHash,
+		&i.Meta,
+		&i.Error,
+		&i.CreatedAt,
+		&i.PromptTemplateID,
+		&i.SchemaTemplateID,
+		&i.ModelParams,
+		&i.PromptRender,
+		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const completeArtifactReview = `-- name: CompleteArtifactReview :one
+UPDATE artifacts SET
+  status = $1,
+  reviewed_at = now()
+WHERE id = $2 AND status = 'IN_REVIEW'
+RETURNING id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at
+`
+
+type CompleteArtifactReviewParams struct {
+	ToStatus string    `json:"to_status"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) CompleteArtifactReview(ctx context.Context, arg CompleteArtifactReviewParams) (Artifact, error) {
+	row := q.db.QueryRowContext(ctx, completeArtifactReview, arg.ToStatus, arg.ID)
+	var i Artifact
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Status,
+		&i.EvalID,
+		&i.EvalItemID,
+		&i.AttemptID,
+		&i.ReviewerID,
+		&i.Text,
+		&i.OutputJson,
+		&i.Model,
+		&i.Prompt,
+		&i.InputHash,
+		&i.Meta,
+		&i.Error,
+		&i.CreatedAt,
+		&i.PromptTemplateID,
+		&i.SchemaTemplateID,
+		&i.ModelParams,
+		&i.PromptRender,
+		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
 const countArtifacts = `-- name: CountArtifacts :one
 SELECT COUNT(*) FROM artifacts
 `
@@ -36,14 +143,41 @@ func (q *Queries) CountArtifactsByType(ctx context.Context, type_ string) (int64
 	return count, err
 }
 
+const countGenerationArtifacts = `-- name: CountGenerationArtifacts :one
+SELECT COUNT(*) FROM artifacts
+WHERE generation_type IS NOT NULL OR user_id IS NOT NULL
+`
+
+func (q *Queries) CountGenerationArtifacts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGenerationArtifacts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countGenerationArtifactsByUser = `-- name: CountGenerationArtifactsByUser :one
+SELECT COUNT(*) FROM artifacts
+WHERE user_id = $1
+`
+
+func (q *Queries) CountGenerationArtifactsByUser(ctx context.Context, userID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGenerationArtifactsByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createArtifact = `-- name: CreateArtifact :one
 INSERT INTO artifacts (
   type, generation_type, status, eval_id, eval_item_id, attempt_id, reviewer_id,
   text, output_json, model, prompt, prompt_template_id, schema_template_id,
-  model_params, prompt_render, input_hash, meta, error
+  model_params, prompt_render, input_hash, meta, error, user_id,
+  prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id,
+  parent_artifact_id, derivation
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
-) RETURNING id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+  $20, $21, $22, $23, $24, $25, $26, $27
+) RETURNING id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at
 `
 
 type CreateArtifactParams struct {
@@ -65,6 +199,15 @@ type CreateArtifactParams struct {
 	InputHash        sql.NullString        `json:"input_hash"`
 	Meta             pqtype.NullRawMessage `json:"meta"`
 	Error            sql.NullString        `json:"error"`
+	UserID           uuid.NullUUID         `json:"user_id"`
+	PromptTokens     sql.NullInt32         `json:"prompt_tokens"`
+	CandidateTokens  sql.NullInt32         `json:"candidate_tokens"`
+	CachedTokens     sql.NullInt32         `json:"cached_tokens"`
+	TotalTokens      sql.NullInt32         `json:"total_tokens"`
+	CostUsd          sql.NullFloat64       `json:"cost_usd"`
+	ThreadID         uuid.NullUUID         `json:"thread_id"`
+	ParentArtifactID uuid.NullUUID         `json:"parent_artifact_id"`
+	Derivation       sql.NullString        `json:"derivation"`
 }
 
 func (q *Queries) CreateArtifact(ctx context.Context, arg CreateArtifactParams) (Artifact, error) {
@@ -87,6 +230,15 @@ func (q *Queries) CreateArtifact(ctx context.Context, arg CreateArtifactParams)
 		arg.InputHash,
 		arg.Meta,
 		arg.Error,
+		arg.UserID,
+		arg.PromptTokens,
+		arg.CandidateTokens,
+		arg.CachedTokens,
+		arg.TotalTokens,
+		arg.CostUsd,
+		arg.ThreadID,
+		arg.ParentArtifactID,
+		arg.Derivation,
 	)
 	var i Artifact
 	err := row.Scan(
@@ -110,12 +262,66 @@ func (q *Queries) CreateArtifact(ctx context.Context, arg CreateArtifactParams)
 		&i.ModelParams,
 		&i.PromptRender,
 		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const createArtifactReviewEvent = `-- name: CreateArtifactReviewEvent :one
+INSERT INTO artifact_review_events (
+  artifact_id, actor_id, action, from_status, to_status, reviewer_id, comment
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, artifact_id, actor_id, action, from_status, to_status, reviewer_id, comment, created_at
+`
+
+type CreateArtifactReviewEventParams struct {
+	ArtifactID uuid.UUID      `json:"artifact_id"`
+	ActorID    uuid.NullUUID  `json:"actor_id"`
+	Action     string         `json:"action"`
+	FromStatus string         `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	ReviewerID uuid.NullUUID  `json:"reviewer_id"`
+	Comment    sql.NullString `json:"comment"`
+}
+
+func (q *Queries) CreateArtifactReviewEvent(ctx context.Context, arg CreateArtifactReviewEventParams) (ArtifactReviewEvent, error) {
+	row := q.db.QueryRowContext(ctx, createArtifactReviewEvent,
+		arg.ArtifactID,
+		arg.ActorID,
+		arg.Action,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ReviewerID,
+		arg.Comment,
+	)
+	var i ArtifactReviewEvent
+	err := row.Scan(
+		&i.ID,
+		&i.ArtifactID,
+		&i.ActorID,
+		&i.Action,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ReviewerID,
+		&i.Comment,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getArtifact = `-- name: GetArtifact :one
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE id = $1 LIMIT 1
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetArtifact(ctx context.Context, id uuid.UUID) (Artifact, error) {
@@ -142,26 +348,129 @@ func (q *Queries) GetArtifact(ctx context.Context, id uuid.UUID) (Artifact, erro
 		&i.ModelParams,
 		&i.PromptRender,
 		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
 
+const getArtifactLineage = `-- name: GetArtifactLineage :many
+WITH RECURSIVE ancestors AS (
+  SELECT a.id, a.parent_artifact_id, 0 AS depth
+  FROM artifacts a
+  WHERE a.id = $1::uuid
+  UNION ALL
+  SELECT p.id, p.parent_artifact_id, anc.depth + 1
+  FROM artifacts p
+  JOIN ancestors anc ON p.id = anc.parent_artifact_id
+  WHERE anc.depth < 100
+),
+root AS (
+  SELECT id FROM ancestors ORDER BY depth DESC LIMIT 1
+),
+tree AS (
+  SELECT a.id, 0 AS depth
+  FROM artifacts a
+  JOIN root ON root.id = a.id
+  UNION ALL
+  SELECT c.id, tree.depth + 1
+  FROM artifacts c
+  JOIN tree ON c.parent_artifact_id = tree.id
+  WHERE tree.depth < 100
+)
+SELECT a.id, a.parent_artifact_id, a.derivation, a.generation_type, a.status, a.model,
+  a.user_id, COALESCE(a.meta->'lineage'->>'feedback', '')::text AS feedback, a.created_at, tree.depth::int AS depth
+FROM tree
+JOIN artifacts a ON a.id = tree.id
+ORDER BY tree.depth, a.created_at
+`
+
+type GetArtifactLineageRow struct {
+	ID               uuid.UUID          `json:"id"`
+	ParentArtifactID uuid.NullUUID      `json:"parent_artifact_id"`
+	Derivation       sql.NullString     `json:"derivation"`
+	GenerationType   NullGenerationType `json:"generation_type"`
+	Status           string             `json:"status"`
+	Model            sql.NullString     `json:"model"`
+	UserID           uuid.NullUUID      `json:"user_id"`
+	Feedback         string             `json:"feedback"`
+	CreatedAt        time.Time          `json:"created_at"`
+	Depth            int32              `json:"depth"`
+}
+
+// Every artifact in the lineage tree containing the given artifact: its root
+// ancestor and all of the root's descendants, parents before children.
+func (q *Queries) GetArtifactLineage(ctx context.Context, artifactID uuid.UUID) ([]GetArtifactLineageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArtifactLineage, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtifactLineageRow
+	for rows.Next() {
+		var i GetArtifactLineageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.GenerationType,
+			&i.Status,
+			&i.Model,
+			&i.UserID,
+			&i.Feedback,
+			&i.CreatedAt,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getArtifactStats = `-- name: GetArtifactStats :one
 SELECT 
   COUNT(*) as total_artifacts,
   COUNT(CASE WHEN status = 'READY' THEN 1 END) as ready_count,
   COUNT(CASE WHEN status = 'PENDING' THEN 1 END) as pending_count,
   COUNT(CASE WHEN status = 'ERROR' THEN 1 END) as error_count,
-  COUNT(CASE WHEN error IS NOT NULL THEN 1 END) as with_errors
+  COUNT(CASE WHEN status = 'INVALID' THEN 1 END) as invalid_count,
+  COUNT(CASE WHEN error IS NOT NULL THEN 1 END) as with_errors,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
 FROM artifacts
 `
 
 type GetArtifactStatsRow struct {
-	TotalArtifacts int64 `json:"total_artifacts"`
-	ReadyCount     int64 `json:"ready_count"`
-	PendingCount   int64 `json:"pending_count"`
-	ErrorCount     int64 `json:"error_count"`
-	WithErrors     int64 `json:"with_errors"`
+	TotalArtifacts  int64   `json:"total_artifacts"`
+	ReadyCount      int64   `json:"ready_count"`
+	PendingCount    int64   `json:"pending_count"`
+	ErrorCount      int64   `json:"error_count"`
+	InvalidCount    int64   `json:"invalid_count"`
+	WithErrors      int64   `json:"with_errors"`
+	PromptTokens    int64   `json:"prompt_tokens"`
+	CandidateTokens int64   `json:"candidate_tokens"`
+	CachedTokens    int64   `json:"cached_tokens"`
+	TotalTokens     int64   `json:"total_tokens"`
+	CostUsd         float64 `json:"cost_usd"`
 }
 
 func (q *Queries) GetArtifactStats(ctx context.Context) (GetArtifactStatsRow, error) {
@@ -172,13 +481,264 @@ func (q *Queries) GetArtifactStats(ctx context.Context) (GetArtifactStatsRow, er
 		&i.ReadyCount,
 		&i.PendingCount,
 		&i.ErrorCount,
+		&i.InvalidCount,
 		&i.WithErrors,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
 	)
 	return i, err
 }
 
+const getArtifactUsageByDay = `-- name: GetArtifactUsageByDay :many
+SELECT
+  date_trunc('day', created_at AT TIME ZONE 'UTC')::date as day,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= $1 AND created_at < $2
+GROUP BY day
+ORDER BY day
+`
+
+type GetArtifactUsageByDayParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+type GetArtifactUsageByDayRow struct {
+	Day             time.Time `json:"day"`
+	ArtifactCount   int64     `json:"artifact_count"`
+	PromptTokens    int64     `json:"prompt_tokens"`
+	CandidateTokens int64     `json:"candidate_tokens"`
+	CachedTokens    int64     `json:"cached_tokens"`
+	TotalTokens     int64     `json:"total_tokens"`
+	CostUsd         float64   `json:"cost_usd"`
+}
+
+func (q *Queries) GetArtifactUsageByDay(ctx context.Context, arg GetArtifactUsageByDayParams) ([]GetArtifactUsageByDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArtifactUsageByDay, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtifactUsageByDayRow
+	for rows.Next() {
+		var i GetArtifactUsageByDayRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.ArtifactCount,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getArtifactUsageByDocument = `-- name: GetArtifactUsageByDocument :many
+SELECT
+  (meta->>'document_id')::text as document_id,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= $1 AND created_at < $2
+  AND meta->>'document_id' IS NOT NULL
+GROUP BY meta->>'document_id'
+ORDER BY cost_usd DESC, total_tokens DESC
+`
+
+type GetArtifactUsageByDocumentParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+type GetArtifactUsageByDocumentRow struct {
+	DocumentID      string  `json:"document_id"`
+	ArtifactCount   int64   `json:"artifact_count"`
+	PromptTokens    int64   `json:"prompt_tokens"`
+	CandidateTokens int64   `json:"candidate_tokens"`
+	CachedTokens    int64   `json:"cached_tokens"`
+	TotalTokens     int64   `json:"total_tokens"`
+	CostUsd         float64 `json:"cost_usd"`
+}
+
+func (q *Queries) GetArtifactUsageByDocument(ctx context.Context, arg GetArtifactUsageByDocumentParams) ([]GetArtifactUsageByDocumentRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArtifactUsageByDocument, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtifactUsageByDocumentRow
+	for rows.Next() {
+		var i GetArtifactUsageByDocumentRow
+		if err := rows.Scan(
+			&i.DocumentID,
+			&i.ArtifactCount,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getArtifactUsageByGenerationType = `-- name: GetArtifactUsageByGenerationType :many
+SELECT
+  generation_type,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= $1 AND created_at < $2
+GROUP BY generation_type
+ORDER BY cost_usd DESC, total_tokens DESC
+`
+
+type GetArtifactUsageByGenerationTypeParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+type GetArtifactUsageByGenerationTypeRow struct {
+	GenerationType  NullGenerationType `json:"generation_type"`
+	ArtifactCount   int64              `json:"artifact_count"`
+	PromptTokens    int64              `json:"prompt_tokens"`
+	CandidateTokens int64              `json:"candidate_tokens"`
+	CachedTokens    int64              `json:"cached_tokens"`
+	TotalTokens     int64              `json:"total_tokens"`
+	CostUsd         float64            `json:"cost_usd"`
+}
+
+func (q *Queries) GetArtifactUsageByGenerationType(ctx context.Context, arg GetArtifactUsageByGenerationTypeParams) ([]GetArtifactUsageByGenerationTypeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArtifactUsageByGenerationType, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtifactUsageByGenerationTypeRow
+	for rows.Next() {
+		var i GetArtifactUsageByGenerationTypeRow
+		if err := rows.Scan(
+			&i.GenerationType,
+			&i.ArtifactCount,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getArtifactUsageByUser = `-- name: GetArtifactUsageByUser :many
+SELECT
+  user_id,
+  COUNT(*) as artifact_count,
+  COALESCE(SUM(prompt_tokens), 0)::bigint as prompt_tokens,
+  COALESCE(SUM(candidate_tokens), 0)::bigint as candidate_tokens,
+  COALESCE(SUM(cached_tokens), 0)::bigint as cached_tokens,
+  COALESCE(SUM(total_tokens), 0)::bigint as total_tokens,
+  COALESCE(SUM(cost_usd), 0)::float8 as cost_usd
+FROM artifacts
+WHERE created_at >= $1 AND created_at < $2
+GROUP BY user_id
+ORDER BY cost_usd DESC, total_tokens DESC
+`
+
+type GetArtifactUsageByUserParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+type GetArtifactUsageByUserRow struct {
+	UserID          uuid.NullUUID `json:"user_id"`
+	ArtifactCount   int64         `json:"artifact_count"`
+	PromptTokens    int64         `json:"prompt_tokens"`
+	CandidateTokens int64         `json:"candidate_tokens"`
+	CachedTokens    int64         `json:"cached_tokens"`
+	TotalTokens     int64         `json:"total_tokens"`
+	CostUsd         float64       `json:"cost_usd"`
+}
+
+func (q *Queries) GetArtifactUsageByUser(ctx context.Context, arg GetArtifactUsageByUserParams) ([]GetArtifactUsageByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getArtifactUsageByUser, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetArtifactUsageByUserRow
+	for rows.Next() {
+		var i GetArtifactUsageByUserRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ArtifactCount,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getArtifactsByAttempt = `-- name: GetArtifactsByAttempt :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE attempt_id = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE attempt_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByAttempt(ctx context.Context, attemptID uuid.NullUUID) ([]Artifact, error) {
@@ -211,6 +771,17 @@ func (q *Queries) GetArtifactsByAttempt(ctx context.Context, attemptID uuid.Null
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -226,7 +797,7 @@ func (q *Queries) GetArtifactsByAttempt(ctx context.Context, attemptID uuid.Null
 }
 
 const getArtifactsByEval = `-- name: GetArtifactsByEval :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE eval_id = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE eval_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByEval(ctx context.Context, evalID uuid.NullUUID) ([]Artifact, error) {
@@ -259,6 +830,17 @@ func (q *Queries) GetArtifactsByEval(ctx context.Context, evalID uuid.NullUUID)
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -274,7 +856,7 @@ func (q *Queries) GetArtifactsByEval(ctx context.Context, evalID uuid.NullUUID)
 }
 
 const getArtifactsByEvalItem = `-- name: GetArtifactsByEvalItem :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE eval_item_id = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE eval_item_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByEvalItem(ctx context.Context, evalItemID uuid.NullUUID) ([]Artifact, error) {
@@ -307,6 +889,17 @@ func (q *Queries) GetArtifactsByEvalItem(ctx context.Context, evalItemID uuid.Nu
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -322,7 +915,7 @@ func (q *Queries) GetArtifactsByEvalItem(ctx context.Context, evalItemID uuid.Nu
 }
 
 const getArtifactsByInputHash = `-- name: GetArtifactsByInputHash :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts 
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts 
 WHERE input_hash = $1 
 ORDER BY created_at DESC
 `
@@ -357,6 +950,17 @@ func (q *Queries) GetArtifactsByInputHash(ctx context.Context, inputHash sql.Nul
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -372,7 +976,7 @@ func (q *Queries) GetArtifactsByInputHash(ctx context.Context, inputHash sql.Nul
 }
 
 const getArtifactsByReviewer = `-- name: GetArtifactsByReviewer :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE reviewer_id = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE reviewer_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByReviewer(ctx context.Context, reviewerID uuid.NullUUID) ([]Artifact, error) {
@@ -405,6 +1009,17 @@ func (q *Queries) GetArtifactsByReviewer(ctx context.Context, reviewerID uuid.Nu
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -420,7 +1035,7 @@ func (q *Queries) GetArtifactsByReviewer(ctx context.Context, reviewerID uuid.Nu
 }
 
 const getArtifactsByStatus = `-- name: GetArtifactsByStatus :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE status = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE status = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByStatus(ctx context.Context, status string) ([]Artifact, error) {
@@ -453,6 +1068,17 @@ func (q *Queries) GetArtifactsByStatus(ctx context.Context, status string) ([]Ar
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -468,7 +1094,7 @@ func (q *Queries) GetArtifactsByStatus(ctx context.Context, status string) ([]Ar
 }
 
 const getArtifactsByType = `-- name: GetArtifactsByType :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts WHERE type = $1 ORDER BY created_at DESC
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts WHERE type = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetArtifactsByType(ctx context.Context, type_ string) ([]Artifact, error) {
@@ -501,6 +1127,17 @@ func (q *Queries) GetArtifactsByType(ctx context.Context, type_ string) ([]Artif
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -516,7 +1153,7 @@ func (q *Queries) GetArtifactsByType(ctx context.Context, type_ string) ([]Artif
 }
 
 const getArtifactsByTypeAndEntity = `-- name: GetArtifactsByTypeAndEntity :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts 
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts 
 WHERE type = $1 
 AND (
   (eval_id = $2 AND $2 IS NOT NULL) OR
@@ -568,6 +1205,17 @@ func (q *Queries) GetArtifactsByTypeAndEntity(ctx context.Context, arg GetArtifa
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -583,7 +1231,7 @@ func (q *Queries) GetArtifactsByTypeAndEntity(ctx context.Context, arg GetArtifa
 }
 
 const getLatestArtifactByTypeAndEntity = `-- name: GetLatestArtifactByTypeAndEntity :one
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts 
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts 
 WHERE type = $1 
 AND (
   (eval_id = $2 AND $2 IS NOT NULL) OR
@@ -630,12 +1278,158 @@ func (q *Queries) GetLatestArtifactByTypeAndEntity(ctx context.Context, arg GetL
 		&i.ModelParams,
 		&i.PromptRender,
 		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
 	)
 	return i, err
 }
 
+const getLatestReusableArtifactByInputHash = `-- name: GetLatestReusableArtifactByInputHash :one
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts
+WHERE input_hash = $1 AND status IN ('READY', 'APPROVED')
+ORDER BY status = 'APPROVED' DESC, created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestReusableArtifactByInputHash(ctx context.Context, inputHash sql.NullString) (Artifact, error) {
+	row := q.db.QueryRowContext(ctx, getLatestReusableArtifactByInputHash, inputHash)
+	var i Artifact
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Status,
+		&i.EvalID,
+		&i.EvalItemID,
+		&i.AttemptID,
+		&i.ReviewerID,
+		&i.Text,
+		&i.OutputJson,
+		&i.Model,
+		&i.Prompt,
+		&i.InputHash,
+		&i.Meta,
+		&i.Error,
+		&i.CreatedAt,
+		&i.PromptTemplateID,
+		&i.SchemaTemplateID,
+		&i.ModelParams,
+		&i.PromptRender,
+		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const linkArtifactEval = `-- name: LinkArtifactEval :one
+UPDATE artifacts SET eval_id = $2
+WHERE id = $1 AND eval_id IS NULL
+RETURNING id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at
+`
+
+type LinkArtifactEvalParams struct {
+	ID     uuid.UUID     `json:"id"`
+	EvalID uuid.NullUUID `json:"eval_id"`
+}
+
+func (q *Queries) LinkArtifactEval(ctx context.Context, arg LinkArtifactEvalParams) (Artifact, error) {
+	row := q.db.QueryRowContext(ctx, linkArtifactEval, arg.ID, arg.EvalID)
+	var i Artifact
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Status,
+		&i.EvalID,
+		&i.EvalItemID,
+		&i.AttemptID,
+		&i.ReviewerID,
+		&i.Text,
+		&i.OutputJson,
+		&i.Model,
+		&i.Prompt,
+		&i.InputHash,
+		&i.Meta,
+		&i.Error,
+		&i.CreatedAt,
+		&i.PromptTemplateID,
+		&i.SchemaTemplateID,
+		&i.ModelParams,
+		&i.PromptRender,
+		&i.GenerationType,
+		&i.UserID,
+		&i.PromptTokens,
+		&i.CandidateTokens,
+		&i.CachedTokens,
+		&i.TotalTokens,
+		&i.CostUsd,
+		&i.ThreadID,
+		&i.ParentArtifactID,
+		&i.Derivation,
+		&i.ReviewRequestedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const listArtifactReviewEvents = `-- name: ListArtifactReviewEvents :many
+SELECT id, artifact_id, actor_id, action, from_status, to_status, reviewer_id, comment, created_at FROM artifact_review_events
+WHERE artifact_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListArtifactReviewEvents(ctx context.Context, artifactID uuid.UUID) ([]ArtifactReviewEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listArtifactReviewEvents, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArtifactReviewEvent
+	for rows.Next() {
+		var i ArtifactReviewEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ArtifactID,
+			&i.ActorID,
+			&i.Action,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ReviewerID,
+			&i.Comment,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listArtifacts = `-- name: ListArtifacts :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
 
 type ListArtifactsParams struct {
@@ -673,6 +1467,92 @@ func (q *Queries) ListArtifacts(ctx context.Context, arg ListArtifactsParams) ([
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArtifactsByReviewerAndStatus = `-- name: ListArtifactsByReviewerAndStatus :many
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts
+WHERE reviewer_id = $1
+  AND ($2::text IS NULL OR status = $2::text)
+ORDER BY review_requested_at DESC NULLS LAST, created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListArtifactsByReviewerAndStatusParams struct {
+	ReviewerID uuid.NullUUID  `json:"reviewer_id"`
+	Status     sql.NullString `json:"status"`
+	RowOffset  int32          `json:"row_offset"`
+	RowLimit   int32          `json:"row_limit"`
+}
+
+func (q *Queries) ListArtifactsByReviewerAndStatus(ctx context.Context, arg ListArtifactsByReviewerAndStatusParams) ([]Artifact, error) {
+	rows, err := q.db.QueryContext(ctx, listArtifactsByReviewerAndStatus,
+		arg.ReviewerID,
+		arg.Status,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Artifact
+	for rows.Next() {
+		var i Artifact
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Status,
+			&i.EvalID,
+			&i.EvalItemID,
+			&i.AttemptID,
+			&i.ReviewerID,
+			&i.Text,
+			&i.OutputJson,
+			&i.Model,
+			&i.Prompt,
+			&i.InputHash,
+			&i.Meta,
+			&i.Error,
+			&i.CreatedAt,
+			&i.PromptTemplateID,
+			&i.SchemaTemplateID,
+			&i.ModelParams,
+			&i.PromptRender,
+			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -688,7 +1568,7 @@ func (q *Queries) ListArtifacts(ctx context.Context, arg ListArtifactsParams) ([
 }
 
 const listArtifactsByType = `-- name: ListArtifactsByType :many
-SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type FROM artifacts 
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts 
 WHERE type = $1 
 ORDER BY created_at DESC 
 LIMIT $2 OFFSET $3
@@ -730,6 +1610,152 @@ func (q *Queries) ListArtifactsByType(ctx context.Context, arg ListArtifactsByTy
 			&i.ModelParams,
 			&i.PromptRender,
 			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenerationArtifacts = `-- name: ListGenerationArtifacts :many
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts
+WHERE generation_type IS NOT NULL OR user_id IS NOT NULL
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListGenerationArtifactsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListGenerationArtifacts(ctx context.Context, arg ListGenerationArtifactsParams) ([]Artifact, error) {
+	rows, err := q.db.QueryContext(ctx, listGenerationArtifacts, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Artifact
+	for rows.Next() {
+		var i Artifact
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Status,
+			&i.EvalID,
+			&i.EvalItemID,
+			&i.AttemptID,
+			&i.ReviewerID,
+			&i.Text,
+			&i.OutputJson,
+			&i.Model,
+			&i.Prompt,
+			&i.InputHash,
+			&i.Meta,
+			&i.Error,
+			&i.CreatedAt,
+			&i.PromptTemplateID,
+			&i.SchemaTemplateID,
+			&i.ModelParams,
+			&i.PromptRender,
+			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenerationArtifactsByUser = `-- name: ListGenerationArtifactsByUser :many
+SELECT id, type, status, eval_id, eval_item_id, attempt_id, reviewer_id, text, output_json, model, prompt, input_hash, meta, error, created_at, prompt_template_id, schema_template_id, model_params, prompt_render, generation_type, user_id, prompt_tokens, candidate_tokens, cached_tokens, total_tokens, cost_usd, thread_id, parent_artifact_id, derivation, review_requested_at, reviewed_at FROM artifacts
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListGenerationArtifactsByUserParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Limit  int32         `json:"limit"`
+	Offset int32         `json:"offset"`
+}
+
+func (q *Queries) ListGenerationArtifactsByUser(ctx context.Context, arg ListGenerationArtifactsByUserParams) ([]Artifact, error) {
+	rows, err := q.db.QueryContext(ctx, listGenerationArtifactsByUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Artifact
+	for rows.Next() {
+		var i Artifact
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Status,
+			&i.EvalID,
+			&i.EvalItemID,
+			&i.AttemptID,
+			&i.ReviewerID,
+			&i.Text,
+			&i.OutputJson,
+			&i.Model,
+			&i.Prompt,
+			&i.InputHash,
+			&i.Meta,
+			&i.Error,
+			&i.CreatedAt,
+			&i.PromptTemplateID,
+			&i.SchemaTemplateID,
+			&i.ModelParams,
+			&i.PromptRender,
+			&i.GenerationType,
+			&i.UserID,
+			&i.PromptTokens,
+			&i.CandidateTokens,
+			&i.CachedTokens,
+			&i.TotalTokens,
+			&i.CostUsd,
+			&i.ThreadID,
+			&i.ParentArtifactID,
+			&i.Derivation,
+			&i.ReviewRequestedAt,
+			&i.ReviewedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,277 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: generation_batches.sql
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const appendGenerationBatchResult = `-- name: AppendGenerationBatchResult :exec
+UPDATE generation_batches
+SET succeeded = $1, failed = $2, results = results || jsonb_build_array($3::jsonb)
+WHERE id = $4
+`
+
+type AppendGenerationBatchResultParams struct {
+	Succeeded int32           `json:"succeeded"`
+	Failed    int32           `json:"failed"`
+	Result    json.RawMessage `json:"result"`
+	ID        uuid.UUID       `json:"id"`
+}
+
+// Records one finished document while the batch runs. Results are in
+// completion order until CompleteGenerationBatch rewrites them in request order.
+func (q *Queries) AppendGenerationBatchResult(ctx context.Context, arg AppendGenerationBatchResultParams) error {
+	_, err := q.db.ExecContext(ctx, appendGenerationBatchResult,
+		arg.Succeeded,
+		arg.Failed,
+		arg.Result,
+		arg.ID,
+	)
+	return err
+}
+
+const completeGenerationBatch = `-- name: CompleteGenerationBatch :one
+UPDATE generation_batches
+SET status = $2,
+    succeeded = $3,
+    failed = $4,
+    results = $5,
+    error = $6,
+    completed_at = now()
+WHERE id = $1
+RETURNING id, user_id, status, request, total, succeeded, failed, results, error, created_at, updated_at, completed_at
+`
+
+type CompleteGenerationBatchParams struct {
+	ID        uuid.UUID       `json:"id"`
+	Status    string          `json:"status"`
+	Succeeded int32           `json:"succeeded"`
+	Failed    int32           `json:"failed"`
+	Results   json.RawMessage `json:"results"`
+	Error     sql.NullString  `json:"error"`
+}
+
+func (q *Queries) CompleteGenerationBatch(ctx context.Context, arg CompleteGenerationBatchParams) (GenerationBatch, error) {
+	row := q.db.QueryRowContext(ctx, completeGenerationBatch,
+		arg.ID,
+		arg.Status,
+		arg.Succeeded,
+		arg.Failed,
+		arg.Results,
+		arg.Error,
+	)
+	var i GenerationBatch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Request,
+		&i.Total,
+		&i.Succeeded,
+		&i.Failed,
+		&i.Results,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const countGenerationBatches = `-- name: CountGenerationBatches :one
+SELECT COUNT(*) FROM generation_batches
+`
+
+func (q *Queries) CountGenerationBatches(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGenerationBatches)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countGenerationBatchesByUser = `-- name: CountGenerationBatchesByUser :one
+SELECT COUNT(*) FROM generation_batches WHERE user_id = $1
+`
+
+func (q *Queries) CountGenerationBatchesByUser(ctx context.Context, userID uuid.NullUUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGenerationBatchesByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createGenerationBatch = `-- name: CreateGenerationBatch :one
+INSERT INTO generation_batches (user_id, request, total)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, status, request, total, succeeded, failed, results, error, created_at, updated_at, completed_at
+`
+
+type CreateGenerationBatchParams struct {
+	UserID  uuid.NullUUID   `json:"user_id"`
+	Request json.RawMessage `json:"request"`
+	Total   int32           `json:"total"`
+}
+
+func (q *Queries) CreateGenerationBatch(ctx context.Context, arg CreateGenerationBatchParams) (GenerationBatch, error) {
+	row := q.db.QueryRowContext(ctx, createGenerationBatch, arg.UserID, arg.Request, arg.Total)
+	var i GenerationBatch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Request,
+		&i.Total,
+		&i.Succeeded,
+		&i.Failed,
+		&i.Results,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const failRunningGenerationBatches = `-- name: FailRunningGenerationBatches :execrows
+UPDATE generation_batches
+SET status = 'FAILED',
+    error = $1::text,
+    completed_at = now()
+WHERE status = 'RUNNING'
+`
+
+func (q *Queries) FailRunningGenerationBatches(ctx context.Context, reason string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failRunningGenerationBatches, reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getGenerationBatch = `-- name: GetGenerationBatch :one
+SELECT id, user_id, status, request, total, succeeded, failed, results, error, created_at, updated_at, completed_at FROM generation_batches WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetGenerationBatch(ctx context.Context, id uuid.UUID) (GenerationBatch, error) {
+	row := q.db.QueryRowContext(ctx, getGenerationBatch, id)
+	var i GenerationBatch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Request,
+		&i.Total,
+		&i.Succeeded,
+		&i.Failed,
+		&i.Results,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const listGenerationBatches = `-- name: ListGenerationBatches :many
+SELECT id, user_id, status, request, total, succeeded, failed, results, error, created_at, updated_at, completed_at FROM generation_batches
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListGenerationBatchesParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListGenerationBatches(ctx context.Context, arg ListGenerationBatchesParams) ([]GenerationBatch, error) {
+	rows, err := q.db.QueryContext(ctx, listGenerationBatches, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GenerationBatch
+	for rows.Next() {
+		var i GenerationBatch
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.Request,
+			&i.Total,
+			&i.Succeeded,
+			&i.Failed,
+			&i.Results,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenerationBatchesByUser = `-- name: ListGenerationBatchesByUser :many
+SELECT id, user_id, status, request, total, succeeded, failed, results, error, created_at, updated_at, completed_at FROM generation_batches
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListGenerationBatchesByUserParams struct {
+	UserID uuid.NullUUID `json:"user_id"`
+	Limit  int32         `json:"limit"`
+	Offset int32         `json:"offset"`
+}
+
+func (q *Queries) ListGenerationBatchesByUser(ctx context.Context, arg ListGenerationBatchesByUserParams) ([]GenerationBatch, error) {
+	rows, err := q.db.QueryContext(ctx, listGenerationBatchesByUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GenerationBatch
+	for rows.Next() {
+		var i GenerationBatch
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.Request,
+			&i.Total,
+			&i.Succeeded,
+			&i.Failed,
+			&i.Results,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
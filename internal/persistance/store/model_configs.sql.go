@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sqlc-dev/pqtype"
 )
 
 const activateModelConfig = `-- name: ActivateModelConfig :exec
@@ -28,12 +30,16 @@ func (q *Queries) ActivateModelConfig(ctx context.Context, id uuid.UUID) error {
 const createModelConfig = `-- name: CreateModelConfig :one
 WITH inserted AS (
   INSERT INTO model_configs (
-    version, model_name, display_name, temperature, max_tokens, top_p, top_k, mime_type, is_active, created_by
+    version, model_name, display_name, temperature, max_tokens, top_p, top_k, mime_type, is_active, created_by,
+    provider, base_url, requests_per_minute,
+    input_price_per_million, output_price_per_million, cached_input_price_per_million,
+    seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings
   ) VALUES (
     (SELECT COALESCE(MAX(version), 0) + 1 FROM model_configs),
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+    $16, $17, $18, $19, $20, $21, $22
   )
-  RETURNING id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name
+  RETURNING id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name, provider, base_url, requests_per_minute, input_price_per_million, output_price_per_million, cached_input_price_per_million, seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings
 ),
 deactivated AS (
   UPDATE model_configs SET
@@ -41,34 +47,60 @@ deactivated AS (
   WHERE model_configs.id != (SELECT id FROM inserted)
     AND (SELECT is_active FROM inserted) = true
 )
-SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name FROM inserted
+SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name, provider, base_url, requests_per_minute, input_price_per_million, output_price_per_million, cached_input_price_per_million, seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings FROM inserted
 `
 
 type CreateModelConfigParams struct {
-	ModelName   string          `json:"model_name"`
-	DisplayName string          `json:"display_name"`
-	Temperature sql.NullFloat64 `json:"temperature"`
-	MaxTokens   sql.NullInt32   `json:"max_tokens"`
-	TopP        sql.NullFloat64 `json:"top_p"`
-	TopK        sql.NullFloat64 `json:"top_k"`
-	MimeType    sql.NullString  `json:"mime_type"`
-	IsActive    bool            `json:"is_active"`
-	CreatedBy   uuid.UUID       `json:"created_by"`
+	ModelName                  string                `json:"model_name"`
+	DisplayName                string                `json:"display_name"`
+	Temperature                sql.NullFloat64       `json:"temperature"`
+	MaxTokens                  sql.NullInt32         `json:"max_tokens"`
+	TopP                       sql.NullFloat64       `json:"top_p"`
+	TopK                       sql.NullFloat64       `json:"top_k"`
+	MimeType                   sql.NullString        `json:"mime_type"`
+	IsActive                   bool                  `json:"is_active"`
+	CreatedBy                  uuid.UUID             `json:"created_by"`
+	Provider                   string                `json:"provider"`
+	BaseUrl                    sql.NullString        `json:"base_url"`
+	RequestsPerMinute          sql.NullInt32         `json:"requests_per_minute"`
+	InputPricePerMillion       sql.NullFloat64       `json:"input_price_per_million"`
+	OutputPricePerMillion      sql.NullFloat64       `json:"output_price_per_million"`
+	CachedInputPricePerMillion sql.NullFloat64       `json:"cached_input_price_per_million"`
+	Seed                       sql.NullInt32         `json:"seed"`
+	StopSequences              []string              `json:"stop_sequences"`
+	CandidateCount             sql.NullInt32         `json:"candidate_count"`
+	PresencePenalty            sql.NullFloat64       `json:"presence_penalty"`
+	FrequencyPenalty           sql.NullFloat64       `json:"frequency_penalty"`
+	ThinkingBudget             sql.NullInt32         `json:"thinking_budget"`
+	SafetySettings             pqtype.NullRawMessage `json:"safety_settings"`
 }
 
 type CreateModelConfigRow struct {
-	ID          uuid.UUID       `json:"id"`
-	Version     int32           `json:"version"`
-	ModelName   string          `json:"model_name"`
-	Temperature sql.NullFloat64 `json:"temperature"`
-	MaxTokens   sql.NullInt32   `json:"max_tokens"`
-	TopP        sql.NullFloat64 `json:"top_p"`
-	TopK        sql.NullFloat64 `json:"top_k"`
-	IsActive    bool            `json:"is_active"`
-	CreatedBy   uuid.UUID       `json:"created_by"`
-	CreatedAt   time.Time       `json:"created_at"`
-	MimeType    sql.NullString  `json:"mime_type"`
-	DisplayName string          `json:"display_name"`
+	ID                         uuid.UUID             `json:"id"`
+	Version                    int32                 `json:"version"`
+	ModelName                  string                `json:"model_name"`
+	Temperature                sql.NullFloat64       `json:"temperature"`
+	MaxTokens                  sql.NullInt32         `json:"max_tokens"`
+	TopP                       sql.NullFloat64       `json:"top_p"`
+	TopK                       sql.NullFloat64       `json:"top_k"`
+	IsActive                   bool                  `json:"is_active"`
+	CreatedBy                  uuid.UUID             `json:"created_by"`
+	CreatedAt                  time.Time             `json:"created_at"`
+	MimeType                   sql.NullString        `json:"mime_type"`
+	DisplayName                string                `json:"display_name"`
+	Provider                   string                `json:"provider"`
+	BaseUrl                    sql.NullString        `json:"base_url"`
+	RequestsPerMinute          sql.NullInt32         `json:"requests_per_minute"`
+	InputPricePerMillion       sql.NullFloat64       `json:"input_price_per_million"`
+	OutputPricePerMillion      sql.NullFloat64       `json:"output_price_per_million"`
+	CachedInputPricePerMillion sql.NullFloat64       `json:"cached_input_price_per_million"`
+	Seed                       sql.NullInt32         `json:"seed"`
+	StopSequences              []string              `json:"stop_sequences"`
+	CandidateCount             sql.NullInt32         `json:"candidate_count"`
+	PresencePenalty            sql.NullFloat64       `json:"presence_penalty"`
+	FrequencyPenalty           sql.NullFloat64       `json:"frequency_penalty"`
+	ThinkingBudget             sql.NullInt32         `json:"thinking_budget"`
+	SafetySettings             pqtype.NullRawMessage `json:"safety_settings"`
 }
 
 func (q *Queries) CreateModelConfig(ctx context.Context, arg CreateModelConfigParams) (CreateModelConfigRow, error) {
@@ -82,6 +114,19 @@ func (q *Queries) CreateModelConfig(ctx context.Context, arg CreateModelConfigPa
 		arg.MimeType,
 		arg.IsActive,
 		arg.CreatedBy,
+		arg.Provider,
+		arg.BaseUrl,
+		arg.RequestsPerMinute,
+		arg.InputPricePerMillion,
+		arg.OutputPricePerMillion,
+		arg.CachedInputPricePerMillion,
+		arg.Seed,
+		pq.Array(arg.StopSequences),
+		arg.CandidateCount,
+		arg.PresencePenalty,
+		arg.FrequencyPenalty,
+		arg.ThinkingBudget,
+		arg.SafetySettings,
 	)
 	var i CreateModelConfigRow
 	err := row.Scan(
@@ -97,6 +142,19 @@ func (q *Queries) CreateModelConfig(ctx context.Context, arg CreateModelConfigPa
 		&i.CreatedAt,
 		&i.MimeType,
 		&i.DisplayName,
+		&i.Provider,
+		&i.BaseUrl,
+		&i.RequestsPerMinute,
+		&i.InputPricePerMillion,
+		&i.OutputPricePerMillion,
+		&i.CachedInputPricePerMillion,
+		&i.Seed,
+		pq.Array(&i.StopSequences),
+		&i.CandidateCount,
+		&i.PresencePenalty,
+		&i.FrequencyPenalty,
+		&i.ThinkingBudget,
+		&i.SafetySettings,
 	)
 	return i, err
 }
@@ -111,7 +169,7 @@ func (q *Queries) DeactivateOtherModelConfigs(ctx context.Context, id uuid.UUID)
 }
 
 const getActiveModelConfig = `-- name: GetActiveModelConfig :one
-SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name FROM model_configs WHERE is_active = true LIMIT 1
+SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name, provider, base_url, requests_per_minute, input_price_per_million, output_price_per_million, cached_input_price_per_million, seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings FROM model_configs WHERE is_active = true LIMIT 1
 `
 
 func (q *Queries) GetActiveModelConfig(ctx context.Context) (ModelConfig, error) {
@@ -130,12 +188,25 @@ func (q *Queries) GetActiveModelConfig(ctx context.Context) (ModelConfig, error)
 		&i.CreatedAt,
 		&i.MimeType,
 		&i.DisplayName,
+		&i.Provider,
+		&i.BaseUrl,
+		&i.RequestsPerMinute,
+		&i.InputPricePerMillion,
+		&i.OutputPricePerMillion,
+		&i.CachedInputPricePerMillion,
+		&i.Seed,
+		pq.Array(&i.StopSequences),
+		&i.CandidateCount,
+		&i.PresencePenalty,
+		&i.FrequencyPenalty,
+		&i.ThinkingBudget,
+		&i.SafetySettings,
 	)
 	return i, err
 }
 
 const getModelConfig = `-- name: GetModelConfig :one
-SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name FROM model_configs WHERE id = $1 LIMIT 1
+SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name, provider, base_url, requests_per_minute, input_price_per_million, output_price_per_million, cached_input_price_per_million, seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings FROM model_configs WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetModelConfig(ctx context.Context, id uuid.UUID) (ModelConfig, error) {
@@ -154,12 +225,25 @@ func (q *Queries) GetModelConfig(ctx context.Context, id uuid.UUID) (ModelConfig
 		&i.CreatedAt,
 		&i.MimeType,
 		&i.DisplayName,
+		&i.Provider,
+		&i.BaseUrl,
+		&i.RequestsPerMinute,
+		&i.InputPricePerMillion,
+		&i.OutputPricePerMillion,
+		&i.CachedInputPricePerMillion,
+		&i.Seed,
+		pq.Array(&i.StopSequences),
+		&i.CandidateCount,
+		&i.PresencePenalty,
+		&i.FrequencyPenalty,
+		&i.ThinkingBudget,
+		&i.SafetySettings,
 	)
 	return i, err
 }
 
 const listModelConfigs = `-- name: ListModelConfigs :many
-SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name FROM model_configs ORDER BY created_at DESC
+SELECT id, version, model_name, temperature, max_tokens, top_p, top_k, is_active, created_by, created_at, mime_type, display_name, provider, base_url, requests_per_minute, input_price_per_million, output_price_per_million, cached_input_price_per_million, seed, stop_sequences, candidate_count, presence_penalty, frequency_penalty, thinking_budget, safety_settings FROM model_configs ORDER BY created_at DESC
 `
 
 func (q *Queries) ListModelConfigs(ctx context.Context) ([]ModelConfig, error) {
@@ -184,6 +268,19 @@ func (q *Queries) ListModelConfigs(ctx context.Context) ([]ModelConfig, error) {
 			&i.CreatedAt,
 			&i.MimeType,
 			&i.DisplayName,
+			&i.Provider,
+			&i.BaseUrl,
+			&i.RequestsPerMinute,
+			&i.InputPricePerMillion,
+			&i.OutputPricePerMillion,
+			&i.CachedInputPricePerMillion,
+			&i.Seed,
+			pq.Array(&i.StopSequences),
+			&i.CandidateCount,
+			&i.PresencePenalty,
+			&i.FrequencyPenalty,
+			&i.ThinkingBudget,
+			&i.SafetySettings,
 		); err != nil {
 			return nil, err
 		}
@@ -30,6 +30,7 @@ const (
 	ArtifactTypeSUMMARY        ArtifactType = "SUMMARY"
 	ArtifactTypeOUTLINE        ArtifactType = "OUTLINE"
 	ArtifactTypeOTHER          ArtifactType = "OTHER"
+	ArtifactTypeFLASHCARDS     ArtifactType = "FLASHCARDS"
 )
 
 func (e *ArtifactType) Scan(src interface{}) error {
@@ -73,6 +74,12 @@ const (
 	GenerationTypeCLASSIFICATION GenerationType = "CLASSIFICATION"
 	GenerationTypeQUESTIONS      GenerationType = "QUESTIONS"
 	GenerationTypeSECTIONTOPICS  GenerationType = "SECTION_TOPICS"
+	GenerationTypeFLASHCARDS     GenerationType = "FLASHCARDS"
+	GenerationTypeSUMMARY        GenerationType = "SUMMARY"
+	GenerationTypeOUTLINE        GenerationType = "OUTLINE"
+	GenerationTypeHINT           GenerationType = "HINT"
+	GenerationTypeMULTIPLECHOICE GenerationType = "MULTIPLE_CHOICE"
+	GenerationTypeTUTORING       GenerationType = "TUTORING"
 )
 
 func (e *GenerationType) Scan(src interface{}) error {
@@ -182,6 +189,42 @@ type Artifact struct {
 	PromptRender sql.NullString `json:"prompt_render"`
 	// Generation type associated with the prompt/schema output
 	GenerationType NullGenerationType `json:"generation_type"`
+	// User who requested the generation
+	UserID uuid.NullUUID `json:"user_id"`
+	// Prompt tokens reported by the provider, summed over repair calls
+	PromptTokens sql.NullInt32 `json:"prompt_tokens"`
+	// Output tokens reported by the provider, summed over repair calls
+	CandidateTokens sql.NullInt32 `json:"candidate_tokens"`
+	// Portion of prompt_tokens served from the provider cache
+	CachedTokens sql.NullInt32 `json:"cached_tokens"`
+	// Total tokens billed for the generation
+	TotalTokens sql.NullInt32 `json:"total_tokens"`
+	// Cost computed from the model config price table when the artifact was saved
+	CostUsd sql.NullFloat64 `json:"cost_usd"`
+	// Tutoring thread the artifact was generated for
+	ThreadID uuid.NullUUID `json:"thread_id"`
+	// Artifact this one was regenerated or refined from
+	ParentArtifactID uuid.NullUUID `json:"parent_artifact_id"`
+	// REGENERATE (same inputs, optional overrides) or REFINE (previous output plus feedback); NULL for fresh generations
+	Derivation sql.NullString `json:"derivation"`
+	// When a reviewer was first assigned (status moved to IN_REVIEW)
+	ReviewRequestedAt sql.NullTime `json:"review_requested_at"`
+	// When the reviewer approved or rejected the artifact
+	ReviewedAt sql.NullTime `json:"reviewed_at"`
+}
+
+// Review history of an artifact: reviewer assignments, comments and decisions
+type ArtifactReviewEvent struct {
+	ID         uuid.UUID     `json:"id"`
+	ArtifactID uuid.UUID     `json:"artifact_id"`
+	ActorID    uuid.NullUUID `json:"actor_id"`
+	Action     string        `json:"action"`
+	FromStatus string        `json:"from_status"`
+	ToStatus   string        `json:"to_status"`
+	// Reviewer assigned by an ASSIGN event
+	ReviewerID uuid.NullUUID  `json:"reviewer_id"`
+	Comment    sql.NullString `json:"comment"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 type ChunkingConfig struct {
@@ -215,6 +258,26 @@ type Document struct {
 	TextbookID uuid.NullUUID `json:"textbook_id"`
 }
 
+type DocumentGraphEdge struct {
+	ID         uuid.UUID             `json:"id"`
+	DocumentID uuid.UUID             `json:"document_id"`
+	FromNodeID uuid.UUID             `json:"from_node_id"`
+	ToNodeID   uuid.UUID             `json:"to_node_id"`
+	Relation   string                `json:"relation"`
+	Metadata   pqtype.NullRawMessage `json:"metadata"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+type DocumentGraphNode struct {
+	ID          uuid.UUID             `json:"id"`
+	DocumentID  uuid.UUID             `json:"document_id"`
+	NodeType    string                `json:"node_type"`
+	TextContent sql.NullString        `json:"text_content"`
+	PageNumber  sql.NullInt32         `json:"page_number"`
+	Metadata    pqtype.NullRawMessage `json:"metadata"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
 type DocumentTaxonomyLink struct {
 	DocumentID     uuid.UUID       `json:"document_id"`
 	TaxonomyNodeID uuid.UUID       `json:"taxonomy_node_id"`
@@ -302,6 +365,24 @@ type EvalResult struct {
 	CreatedAt         sql.NullTime          `json:"created_at"`
 }
 
+// Batch generation jobs run over many documents
+type GenerationBatch struct {
+	ID     uuid.UUID     `json:"id"`
+	UserID uuid.NullUUID `json:"user_id"`
+	Status string        `json:"status"`
+	// Shared instructions, output, tools and document IDs for the batch
+	Request   json.RawMessage `json:"request"`
+	Total     int32           `json:"total"`
+	Succeeded int32           `json:"succeeded"`
+	Failed    int32           `json:"failed"`
+	// Per-document outcome with artifact IDs, appended as each document finishes and rewritten in request order when the batch finishes
+	Results     json.RawMessage `json:"results"`
+	Error       sql.NullString  `json:"error"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt sql.NullTime    `json:"completed_at"`
+}
+
 type ModelConfig struct {
 	ID          uuid.UUID       `json:"id"`
 	Version     int32           `json:"version"`
@@ -315,6 +396,66 @@ type ModelConfig struct {
 	CreatedAt   time.Time       `json:"created_at"`
 	MimeType    sql.NullString  `json:"mime_type"`
 	DisplayName string          `json:"display_name"`
+	// Generator implementation used for this model (gemini, openai, synthetic)
+	Provider string `json:"provider"`
+	// Endpoint override for OpenAI-compatible providers
+	BaseUrl sql.NullString `json:"base_url"`
+	// Process-wide request rate limit for this model; NULL means unlimited
+	RequestsPerMinute sql.NullInt32 `json:"requests_per_minute"`
+	// USD per million prompt tokens; NULL means cost is not tracked
+	InputPricePerMillion sql.NullFloat64 `json:"input_price_per_million"`
+	// USD per million candidate (output) tokens
+	OutputPricePerMillion sql.NullFloat64 `json:"output_price_per_million"`
+	// USD per million cached prompt tokens; NULL falls back to the input price
+	CachedInputPricePerMillion sql.NullFloat64 `json:"cached_input_price_per_million"`
+	// Sampling seed for reproducible output; NULL lets the provider choose
+	Seed sql.NullInt32 `json:"seed"`
+	// Sequences that end generation when produced
+	StopSequences []string `json:"stop_sequences"`
+	// Number of candidates to request; the first schema-valid one is kept
+	CandidateCount   sql.NullInt32   `json:"candidate_count"`
+	PresencePenalty  sql.NullFloat64 `json:"presence_penalty"`
+	FrequencyPenalty sql.NullFloat64 `json:"frequency_penalty"`
+	// Thinking token budget; 0 disables thinking and -1 lets the model decide
+	ThinkingBudget sql.NullInt32 `json:"thinking_budget"`
+	// Per-category block thresholds as [{"category", "threshold"}]
+	SafetySettings pqtype.NullRawMessage `json:"safety_settings"`
+}
+
+// A/B experiments splitting generation traffic across prompt template versions
+type PromptExperiment struct {
+	ID             uuid.UUID      `json:"id"`
+	Name           string         `json:"name"`
+	Description    sql.NullString `json:"description"`
+	GenerationType GenerationType `json:"generation_type"`
+	// DRAFT, RUNNING or STOPPED; at most one experiment per generation type runs at a time
+	Status    string         `json:"status"`
+	CreatedBy sql.NullString `json:"created_by"`
+	StartedAt sql.NullTime   `json:"started_at"`
+	StoppedAt sql.NullTime   `json:"stopped_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type PromptExperimentVariant struct {
+	ExperimentID     uuid.UUID `json:"experiment_id"`
+	PromptTemplateID uuid.UUID `json:"prompt_template_id"`
+	// Relative share of generations rendered with this template
+	Weight int32 `json:"weight"`
+}
+
+// Versioned prompt fragments included by prompt templates with {{template "name" .}}
+type PromptPartial struct {
+	ID uuid.UUID `json:"id"`
+	// Template name used to include the partial
+	Name        string         `json:"name"`
+	Version     int32          `json:"version"`
+	IsActive    bool           `json:"is_active"`
+	Description sql.NullString `json:"description"`
+	Template    string         `json:"template"`
+	CreatedBy   sql.NullString `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
 type PromptTemplate struct {
@@ -405,6 +546,34 @@ type TestAttempt struct {
 	CompletedAt sql.NullTime          `json:"completed_at"`
 }
 
+// Messages of a tutoring thread in conversation order
+type TutoringMessage struct {
+	ID       uuid.UUID `json:"id"`
+	ThreadID uuid.UUID `json:"thread_id"`
+	Seq      int32     `json:"seq"`
+	Role     string    `json:"role"`
+	Content  string    `json:"content"`
+	// Artifact that produced an assistant message
+	ArtifactID uuid.NullUUID `json:"artifact_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// Tutoring conversations between a learner and the model about one document
+type TutoringThread struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	DocumentID uuid.UUID `json:"document_id"`
+	Title      string    `json:"title"`
+	// Model used for the thread; NULL uses the active model config
+	ModelConfigID uuid.NullUUID `json:"model_config_id"`
+	// Summary of the messages older than the history window
+	Summary sql.NullString `json:"summary"`
+	// Sequence number of the last message folded into summary
+	SummarizedThrough int32     `json:"summarized_through"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
 type User struct {
 	ID       uuid.UUID `json:"id"`
 	Email    string    `json:"email"`
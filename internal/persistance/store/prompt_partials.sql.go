@@ -0,0 +1,270 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: prompt_partials.sql
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const activatePromptPartial = `-- name: ActivatePromptPartial :one
+WITH target AS (
+  SELECT name FROM prompt_partials WHERE prompt_partials.id = $1
+),
+deactivated AS (
+  UPDATE prompt_partials SET
+    is_active = false,
+    updated_at = now()
+  WHERE name = (SELECT name FROM target) AND id != $1
+),
+activated AS (
+  UPDATE prompt_partials SET
+    is_active = true,
+    updated_at = now()
+  WHERE id = $1
+  RETURNING id, name, version, is_active, description, template, created_by, created_at, updated_at
+)
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM activated
+`
+
+type ActivatePromptPartialRow struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Version     int32          `json:"version"`
+	IsActive    bool           `json:"is_active"`
+	Description sql.NullString `json:"description"`
+	Template    string         `json:"template"`
+	CreatedBy   sql.NullString `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (q *Queries) ActivatePromptPartial(ctx context.Context, id uuid.UUID) (ActivatePromptPartialRow, error) {
+	row := q.db.QueryRowContext(ctx, activatePromptPartial, id)
+	var i ActivatePromptPartialRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.IsActive,
+		&i.Description,
+		&i.Template,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPromptPartialVersion = `-- name: CreatePromptPartialVersion :one
+WITH inserted AS (
+  INSERT INTO prompt_partials (
+    name, version, is_active, description, template, created_by
+  ) VALUES (
+    $1,
+    (SELECT COALESCE(MAX(version), 0) + 1 FROM prompt_partials WHERE name = $1),
+    $2, $3, $4, $5
+  )
+  RETURNING id, name, version, is_active, description, template, created_by, created_at, updated_at
+),
+deactivated AS (
+  UPDATE prompt_partials SET
+    is_active = false,
+    updated_at = now()
+  WHERE name = (SELECT name FROM inserted)
+    AND id != (SELECT id FROM inserted)
+    AND (SELECT is_active FROM inserted) = true
+)
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM inserted
+`
+
+type CreatePromptPartialVersionParams struct {
+	Name        string         `json:"name"`
+	IsActive    bool           `json:"is_active"`
+	Description sql.NullString `json:"description"`
+	Template    string         `json:"template"`
+	CreatedBy   sql.NullString `json:"created_by"`
+}
+
+type CreatePromptPartialVersionRow struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Version     int32          `json:"version"`
+	IsActive    bool           `json:"is_active"`
+	Description sql.NullString `json:"description"`
+	Template    string         `json:"template"`
+	CreatedBy   sql.NullString `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (q *Queries) CreatePromptPartialVersion(ctx context.Context, arg CreatePromptPartialVersionParams) (CreatePromptPartialVersionRow, error) {
+	row := q.db.QueryRowContext(ctx, createPromptPartialVersion,
+		arg.Name,
+		arg.IsActive,
+		arg.Description,
+		arg.Template,
+		arg.CreatedBy,
+	)
+	var i CreatePromptPartialVersionRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.IsActive,
+		&i.Description,
+		&i.Template,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getActivePromptPartialByName = `-- name: GetActivePromptPartialByName :one
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM prompt_partials WHERE name = $1 AND is_active = true LIMIT 1
+`
+
+func (q *Queries) GetActivePromptPartialByName(ctx context.Context, name string) (PromptPartial, error) {
+	row := q.db.QueryRowContext(ctx, getActivePromptPartialByName, name)
+	var i PromptPartial
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.IsActive,
+		&i.Description,
+		&i.Template,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPromptPartial = `-- name: GetPromptPartial :one
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM prompt_partials WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPromptPartial(ctx context.Context, id uuid.UUID) (PromptPartial, error) {
+	row := q.db.QueryRowContext(ctx, getPromptPartial, id)
+	var i PromptPartial
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.IsActive,
+		&i.Description,
+		&i.Template,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPromptPartialByNameAndVersion = `-- name: GetPromptPartialByNameAndVersion :one
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM prompt_partials WHERE name = $1 AND version = $2 LIMIT 1
+`
+
+type GetPromptPartialByNameAndVersionParams struct {
+	Name    string `json:"name"`
+	Version int32  `json:"version"`
+}
+
+func (q *Queries) GetPromptPartialByNameAndVersion(ctx context.Context, arg GetPromptPartialByNameAndVersionParams) (PromptPartial, error) {
+	row := q.db.QueryRowContext(ctx, getPromptPartialByNameAndVersion, arg.Name, arg.Version)
+	var i PromptPartial
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.IsActive,
+		&i.Description,
+		&i.Template,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listActivePromptPartials = `-- name: ListActivePromptPartials :many
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM prompt_partials WHERE is_active = true ORDER BY name ASC
+`
+
+func (q *Queries) ListActivePromptPartials(ctx context.Context) ([]PromptPartial, error) {
+	rows, err := q.db.QueryContext(ctx, listActivePromptPartials)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PromptPartial
+	for rows.Next() {
+		var i PromptPartial
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.IsActive,
+			&i.Description,
+			&i.Template,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromptPartialVersions = `-- name: ListPromptPartialVersions :many
+SELECT id, name, version, is_active, description, template, created_by, created_at, updated_at FROM prompt_partials WHERE name = $1 ORDER BY version DESC
+`
+
+func (q *Queries) ListPromptPartialVersions(ctx context.Context, name string) ([]PromptPartial, error) {
+	rows, err := q.db.QueryContext(ctx, listPromptPartialVersions, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PromptPartial
+	for rows.Next() {
+		var i PromptPartial
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Version,
+			&i.IsActive,
+			&i.Description,
+			&i.Template,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
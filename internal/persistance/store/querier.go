@@ -15,17 +15,29 @@ type Querier interface {
 	ActivateChunkingConfig(ctx context.Context, id uuid.UUID) error
 	ActivateEvalPrompt(ctx context.Context, id uuid.UUID) error
 	ActivateModelConfig(ctx context.Context, id uuid.UUID) error
+	ActivatePromptPartial(ctx context.Context, id uuid.UUID) (ActivatePromptPartialRow, error)
 	ActivatePromptTemplate(ctx context.Context, id uuid.UUID) (ActivatePromptTemplateRow, error)
 	ActivateSchemaTemplate(ctx context.Context, id uuid.UUID) (ActivateSchemaTemplateRow, error)
 	ActivateSystemInstruction(ctx context.Context, id uuid.UUID) error
 	ActivateTaxonomyNode(ctx context.Context, id uuid.UUID) (ActivateTaxonomyNodeRow, error)
+	// Records one finished document while the batch runs. Results are in
+	// completion order until CompleteGenerationBatch rewrites them in request order.
+	AppendGenerationBatchResult(ctx context.Context, arg AppendGenerationBatchResultParams) error
 	ArchiveEval(ctx context.Context, id uuid.UUID) (Eval, error)
+	AssignArtifactReviewer(ctx context.Context, arg AssignArtifactReviewerParams) (Artifact, error)
+	CompleteArtifactReview(ctx context.Context, arg CompleteArtifactReviewParams) (Artifact, error)
+	CompleteGenerationBatch(ctx context.Context, arg CompleteGenerationBatchParams) (GenerationBatch, error)
 	CompleteTestAttempt(ctx context.Context, arg CompleteTestAttemptParams) (TestAttempt, error)
 	CountArtifacts(ctx context.Context) (int64, error)
 	CountArtifactsByType(ctx context.Context, type_ string) (int64, error)
+	CountGenerationArtifacts(ctx context.Context) (int64, error)
+	CountGenerationArtifactsByUser(ctx context.Context, userID uuid.NullUUID) (int64, error)
+	CountGenerationBatches(ctx context.Context) (int64, error)
+	CountGenerationBatchesByUser(ctx context.Context, userID uuid.NullUUID) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
 	CountUsersByRole(ctx context.Context, dollar_1 string) (int64, error)
 	CreateArtifact(ctx context.Context, arg CreateArtifactParams) (Artifact, error)
+	CreateArtifactReviewEvent(ctx context.Context, arg CreateArtifactReviewEventParams) (ArtifactReviewEvent, error)
 	CreateChunkingConfig(ctx context.Context, arg CreateChunkingConfigParams) (CreateChunkingConfigRow, error)
 	CreateDocument(ctx context.Context, arg CreateDocumentParams) (Document, error)
 	CreateDocumentTaxonomyLink(ctx context.Context, arg CreateDocumentTaxonomyLinkParams) (DocumentTaxonomyLink, error)
@@ -35,8 +47,11 @@ type Querier interface {
 	CreateEvalItemReview(ctx context.Context, arg CreateEvalItemReviewParams) (EvalItemReview, error)
 	CreateEvalPrompt(ctx context.Context, arg CreateEvalPromptParams) (EvalPrompt, error)
 	CreateEvalResult(ctx context.Context, arg CreateEvalResultParams) (EvalResult, error)
+	CreateGenerationBatch(ctx context.Context, arg CreateGenerationBatchParams) (GenerationBatch, error)
 	CreateModelConfig(ctx context.Context, arg CreateModelConfigParams) (CreateModelConfigRow, error)
 	CreateNewVersion(ctx context.Context, arg CreateNewVersionParams) (CreateNewVersionRow, error)
+	CreatePromptExperiment(ctx context.Context, arg CreatePromptExperimentParams) (CreatePromptExperimentRow, error)
+	CreatePromptPartialVersion(ctx context.Context, arg CreatePromptPartialVersionParams) (CreatePromptPartialVersionRow, error)
 	CreatePromptTemplate(ctx context.Context, arg CreatePromptTemplateParams) (CreatePromptTemplateRow, error)
 	CreateSchemaTemplate(ctx context.Context, arg CreateSchemaTemplateParams) (CreateSchemaTemplateRow, error)
 	CreateSubSubject(ctx context.Context, arg CreateSubSubjectParams) (SubSubject, error)
@@ -44,6 +59,8 @@ type Querier interface {
 	CreateSystemInstruction(ctx context.Context, arg CreateSystemInstructionParams) (CreateSystemInstructionRow, error)
 	CreateTaxonomyNode(ctx context.Context, arg CreateTaxonomyNodeParams) (CreateTaxonomyNodeRow, error)
 	CreateTestAttempt(ctx context.Context, arg CreateTestAttemptParams) (TestAttempt, error)
+	CreateTutoringMessage(ctx context.Context, arg CreateTutoringMessageParams) (TutoringMessage, error)
+	CreateTutoringThread(ctx context.Context, arg CreateTutoringThreadParams) (TutoringThread, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserAnswer(ctx context.Context, arg CreateUserAnswerParams) (UserAnswer, error)
 	DeactivateEvalPrompt(ctx context.Context, id uuid.UUID) error
@@ -56,10 +73,12 @@ type Querier interface {
 	DeleteDocument(ctx context.Context, id uuid.UUID) error
 	DeleteSubject(ctx context.Context, id uuid.UUID) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	FailRunningGenerationBatches(ctx context.Context, reason string) (int64, error)
 	GetActiveAttempts(ctx context.Context) ([]TestAttempt, error)
 	GetActiveChunkingConfig(ctx context.Context) (ChunkingConfig, error)
 	GetActiveEvalPrompt(ctx context.Context, evalType string) (EvalPrompt, error)
 	GetActiveModelConfig(ctx context.Context) (ModelConfig, error)
+	GetActivePromptPartialByName(ctx context.Context, name string) (PromptPartial, error)
 	GetActivePromptTemplates(ctx context.Context) ([]PromptTemplate, error)
 	GetActiveSchemaTemplateByGenerationType(ctx context.Context, generationType GenerationType) (SchemaTemplate, error)
 	GetActiveSystemInstruction(ctx context.Context) (SystemInstruction, error)
@@ -68,7 +87,14 @@ type Querier interface {
 	GetAnswerStatsForEvalItem(ctx context.Context, evalItemID uuid.UUID) (GetAnswerStatsForEvalItemRow, error)
 	GetAnswersByUserAndEval(ctx context.Context, arg GetAnswersByUserAndEvalParams) ([]GetAnswersByUserAndEvalRow, error)
 	GetArtifact(ctx context.Context, id uuid.UUID) (Artifact, error)
+	// Every artifact in the lineage tree containing the given artifact: its root
+	// ancestor and all of the root's descendants, parents before children.
+	GetArtifactLineage(ctx context.Context, artifactID uuid.UUID) ([]GetArtifactLineageRow, error)
 	GetArtifactStats(ctx context.Context) (GetArtifactStatsRow, error)
+	GetArtifactUsageByDay(ctx context.Context, arg GetArtifactUsageByDayParams) ([]GetArtifactUsageByDayRow, error)
+	GetArtifactUsageByDocument(ctx context.Context, arg GetArtifactUsageByDocumentParams) ([]GetArtifactUsageByDocumentRow, error)
+	GetArtifactUsageByGenerationType(ctx context.Context, arg GetArtifactUsageByGenerationTypeParams) ([]GetArtifactUsageByGenerationTypeRow, error)
+	GetArtifactUsageByUser(ctx context.Context, arg GetArtifactUsageByUserParams) ([]GetArtifactUsageByUserRow, error)
 	GetArtifactsByAttempt(ctx context.Context, attemptID uuid.NullUUID) ([]Artifact, error)
 	GetArtifactsByEval(ctx context.Context, evalID uuid.NullUUID) ([]Artifact, error)
 	GetArtifactsByEvalItem(ctx context.Context, evalItemID uuid.NullUUID) ([]Artifact, error)
@@ -102,13 +128,21 @@ type Querier interface {
 	GetEvalsByStatus(ctx context.Context, status string) ([]Eval, error)
 	GetEvalsByUser(ctx context.Context, userID uuid.UUID) ([]Eval, error)
 	GetEvalsWithItemCounts(ctx context.Context, userID uuid.UUID) ([]GetEvalsWithItemCountsRow, error)
+	GetGenerationBatch(ctx context.Context, id uuid.UUID) (GenerationBatch, error)
 	GetIncorrectAnswersByAttempt(ctx context.Context, attemptID uuid.UUID) ([]UserAnswer, error)
 	GetLatestArtifactByTypeAndEntity(ctx context.Context, arg GetLatestArtifactByTypeAndEntityParams) (Artifact, error)
 	GetLatestEvalPromptVersion(ctx context.Context, evalType string) (interface{}, error)
 	GetLatestEvalResultForItem(ctx context.Context, arg GetLatestEvalResultForItemParams) (EvalResult, error)
+	GetLatestReusableArtifactByInputHash(ctx context.Context, inputHash sql.NullString) (Artifact, error)
 	GetLatestVersionByGenerationType(ctx context.Context, generationType GenerationType) (interface{}, error)
 	GetModelConfig(ctx context.Context, id uuid.UUID) (ModelConfig, error)
 	GetPendingReviewsForEval(ctx context.Context, evalID uuid.UUID) ([]EvalItem, error)
+	GetPromptExperiment(ctx context.Context, id uuid.UUID) (PromptExperiment, error)
+	// Per-variant counts of the experiment's artifacts and the latest verdict of
+	// the given eval type for the eval items generated from them.
+	GetPromptExperimentReport(ctx context.Context, arg GetPromptExperimentReportParams) ([]GetPromptExperimentReportRow, error)
+	GetPromptPartial(ctx context.Context, id uuid.UUID) (PromptPartial, error)
+	GetPromptPartialByNameAndVersion(ctx context.Context, arg GetPromptPartialByNameAndVersionParams) (PromptPartial, error)
 	GetPromptTemplate(ctx context.Context, id uuid.UUID) (PromptTemplate, error)
 	GetPromptTemplateByGenerationType(ctx context.Context, generationType GenerationType) (PromptTemplate, error)
 	GetPromptTemplateByGenerationTypeAndVersion(ctx context.Context, arg GetPromptTemplateByGenerationTypeAndVersionParams) (PromptTemplate, error)
@@ -124,7 +158,9 @@ type Querier interface {
 	GetReviewsByReviewer(ctx context.Context, reviewerID uuid.UUID) ([]EvalItemReview, error)
 	GetReviewsByVerdict(ctx context.Context, verdict ReviewVerdict) ([]EvalItemReview, error)
 	GetReviewsWithEvalItemDetails(ctx context.Context, arg GetReviewsWithEvalItemDetailsParams) ([]GetReviewsWithEvalItemDetailsRow, error)
+	GetRunningPromptExperiment(ctx context.Context, generationType GenerationType) (PromptExperiment, error)
 	GetSchemaTemplate(ctx context.Context, id uuid.UUID) (SchemaTemplate, error)
+	GetSchemaTemplateByGenerationTypeAndVersion(ctx context.Context, arg GetSchemaTemplateByGenerationTypeAndVersionParams) (SchemaTemplate, error)
 	GetSubSubjectsBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]SubSubject, error)
 	GetSubjectByID(ctx context.Context, id uuid.UUID) (Subject, error)
 	GetSubjectByName(ctx context.Context, name string) (Subject, error)
@@ -134,6 +170,7 @@ type Querier interface {
 	GetTestAttemptWithAnswers(ctx context.Context, id uuid.UUID) (GetTestAttemptWithAnswersRow, error)
 	GetTestAttemptsByEval(ctx context.Context, evalID uuid.UUID) ([]TestAttempt, error)
 	GetTestAttemptsByUser(ctx context.Context, userID uuid.UUID) ([]TestAttempt, error)
+	GetTutoringThread(ctx context.Context, id uuid.UUID) (TutoringThread, error)
 	GetUser(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserAnswer(ctx context.Context, id uuid.UUID) (UserAnswer, error)
 	GetUserAnswerByAttemptAndItem(ctx context.Context, arg GetUserAnswerByAttemptAndItemParams) (UserAnswer, error)
@@ -143,8 +180,12 @@ type Querier interface {
 	GetUserAttemptsByEval(ctx context.Context, arg GetUserAttemptsByEvalParams) ([]TestAttempt, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserTestStats(ctx context.Context, userID uuid.UUID) (GetUserTestStatsRow, error)
+	LinkArtifactEval(ctx context.Context, arg LinkArtifactEvalParams) (Artifact, error)
+	ListActivePromptPartials(ctx context.Context) ([]PromptPartial, error)
 	ListActiveSchemaTemplates(ctx context.Context) ([]SchemaTemplate, error)
+	ListArtifactReviewEvents(ctx context.Context, artifactID uuid.UUID) ([]ArtifactReviewEvent, error)
 	ListArtifacts(ctx context.Context, arg ListArtifactsParams) ([]Artifact, error)
+	ListArtifactsByReviewerAndStatus(ctx context.Context, arg ListArtifactsByReviewerAndStatusParams) ([]Artifact, error)
 	ListArtifactsByType(ctx context.Context, arg ListArtifactsByTypeParams) ([]Artifact, error)
 	ListChunkingConfigs(ctx context.Context) ([]ChunkingConfig, error)
 	ListDocuments(ctx context.Context, arg ListDocumentsParams) ([]Document, error)
@@ -154,7 +195,14 @@ type Querier interface {
 	ListEvalPrompts(ctx context.Context, arg ListEvalPromptsParams) ([]EvalPrompt, error)
 	ListEvalResults(ctx context.Context, arg ListEvalResultsParams) ([]EvalResult, error)
 	ListEvals(ctx context.Context, arg ListEvalsParams) ([]Eval, error)
+	ListGenerationArtifacts(ctx context.Context, arg ListGenerationArtifactsParams) ([]Artifact, error)
+	ListGenerationArtifactsByUser(ctx context.Context, arg ListGenerationArtifactsByUserParams) ([]Artifact, error)
+	ListGenerationBatches(ctx context.Context, arg ListGenerationBatchesParams) ([]GenerationBatch, error)
+	ListGenerationBatchesByUser(ctx context.Context, arg ListGenerationBatchesByUserParams) ([]GenerationBatch, error)
 	ListModelConfigs(ctx context.Context) ([]ModelConfig, error)
+	ListPromptExperimentVariants(ctx context.Context, experimentID uuid.UUID) ([]ListPromptExperimentVariantsRow, error)
+	ListPromptExperiments(ctx context.Context, generationType NullGenerationType) ([]PromptExperiment, error)
+	ListPromptPartialVersions(ctx context.Context, name string) ([]PromptPartial, error)
 	ListPromptTemplates(ctx context.Context, arg ListPromptTemplatesParams) ([]PromptTemplate, error)
 	ListSchemaTemplatesByGenerationType(ctx context.Context, generationType GenerationType) ([]SchemaTemplate, error)
 	ListSubSubjectsBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]SubSubject, error)
@@ -162,21 +210,29 @@ type Querier interface {
 	ListSystemInstructions(ctx context.Context) ([]SystemInstruction, error)
 	ListTaxonomyNodesByPrefix(ctx context.Context, dollar_1 sql.NullString) ([]TaxonomyNode, error)
 	ListTestAttempts(ctx context.Context, arg ListTestAttemptsParams) ([]TestAttempt, error)
+	ListTutoringMessages(ctx context.Context, threadID uuid.UUID) ([]TutoringMessage, error)
+	ListTutoringMessagesAfter(ctx context.Context, arg ListTutoringMessagesAfterParams) ([]TutoringMessage, error)
+	ListTutoringThreadsByUser(ctx context.Context, arg ListTutoringThreadsByUserParams) ([]TutoringThread, error)
 	ListUserAnswers(ctx context.Context, arg ListUserAnswersParams) ([]UserAnswer, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	ListUsersByRole(ctx context.Context, dollar_1 string) ([]User, error)
+	LockTutoringThread(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
 	PublishEval(ctx context.Context, id uuid.UUID) (Eval, error)
 	SearchDocumentsByTitle(ctx context.Context, arg SearchDocumentsByTitleParams) ([]Document, error)
 	SearchEvalItemsByPrompt(ctx context.Context, arg SearchEvalItemsByPromptParams) ([]EvalItem, error)
 	SearchEvalsByTitle(ctx context.Context, arg SearchEvalsByTitleParams) ([]Eval, error)
 	SearchPromptTemplatesByTitle(ctx context.Context, arg SearchPromptTemplatesByTitleParams) ([]PromptTemplate, error)
 	SearchUsersByEmail(ctx context.Context, arg SearchUsersByEmailParams) ([]User, error)
+	StartPromptExperiment(ctx context.Context, id uuid.UUID) (PromptExperiment, error)
+	StopPromptExperiment(ctx context.Context, id uuid.UUID) (PromptExperiment, error)
+	TouchTutoringThread(ctx context.Context, id uuid.UUID) error
 	UpdateDocument(ctx context.Context, arg UpdateDocumentParams) (Document, error)
 	UpdateDocumentRagStatus(ctx context.Context, arg UpdateDocumentRagStatusParams) (Document, error)
 	UpdateDocumentTaxonomyLinkState(ctx context.Context, arg UpdateDocumentTaxonomyLinkStateParams) (DocumentTaxonomyLink, error)
 	UpdateDocumentTextbook(ctx context.Context, arg UpdateDocumentTextbookParams) (Document, error)
 	UpdateTestAttemptScore(ctx context.Context, arg UpdateTestAttemptScoreParams) (TestAttempt, error)
 	UpdateTestAttemptTime(ctx context.Context, arg UpdateTestAttemptTimeParams) (TestAttempt, error)
+	UpdateTutoringThreadSummary(ctx context.Context, arg UpdateTutoringThreadSummaryParams) (TutoringThread, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
@@ -164,6 +164,31 @@ func (q *Queries) GetSchemaTemplate(ctx context.Context, id uuid.UUID) (SchemaTe
 	return i, err
 }
 
+const getSchemaTemplateByGenerationTypeAndVersion = `-- name: GetSchemaTemplateByGenerationTypeAndVersion :one
+SELECT id, generation_type, version, schema_json, is_active, created_by, created_at, locked_at FROM schema_templates WHERE generation_type = $1 AND version = $2 LIMIT 1
+`
+
+type GetSchemaTemplateByGenerationTypeAndVersionParams struct {
+	GenerationType GenerationType `json:"generation_type"`
+	Version        int32          `json:"version"`
+}
+
+func (q *Queries) GetSchemaTemplateByGenerationTypeAndVersion(ctx context.Context, arg GetSchemaTemplateByGenerationTypeAndVersionParams) (SchemaTemplate, error) {
+	row := q.db.QueryRowContext(ctx, getSchemaTemplateByGenerationTypeAndVersion, arg.GenerationType, arg.Version)
+	var i SchemaTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.GenerationType,
+		&i.Version,
+		&i.SchemaJson,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.LockedAt,
+	)
+	return i, err
+}
+
 const listActiveSchemaTemplates = `-- name: ListActiveSchemaTemplates :many
 SELECT id, generation_type, version, schema_json, is_active, created_by, created_at, locked_at FROM schema_templates
 WHERE is_active = true
@@ -0,0 +1,291 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tutoring.sql
+
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createTutoringMessage = `-- name: CreateTutoringMessage :one
+INSERT INTO tutoring_messages (thread_id, seq, role, content, artifact_id)
+VALUES (
+  $1,
+  (SELECT COALESCE(MAX(seq), 0) + 1 FROM tutoring_messages WHERE thread_id = $1),
+  $2, $3, $4
+)
+RETURNING id, thread_id, seq, role, content, artifact_id, created_at
+`
+
+type CreateTutoringMessageParams struct {
+	ThreadID   uuid.UUID     `json:"thread_id"`
+	Role       string        `json:"role"`
+	Content    string        `json:"content"`
+	ArtifactID uuid.NullUUID `json:"artifact_id"`
+}
+
+func (q *Queries) CreateTutoringMessage(ctx context.Context, arg CreateTutoringMessageParams) (TutoringMessage, error) {
+	row := q.db.QueryRowContext(ctx, createTutoringMessage,
+		arg.ThreadID,
+		arg.Role,
+		arg.Content,
+		arg.ArtifactID,
+	)
+	var i TutoringMessage
+	err := row.Scan(
+		&i.ID,
+		&i.ThreadID,
+		&i.Seq,
+		&i.Role,
+		&i.Content,
+		&i.ArtifactID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTutoringThread = `-- name: CreateTutoringThread :one
+INSERT INTO tutoring_threads (user_id, document_id, title, model_config_id)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, document_id, title, model_config_id, summary, summarized_through, created_at, updated_at
+`
+
+type CreateTutoringThreadParams struct {
+	UserID        uuid.UUID     `json:"user_id"`
+	DocumentID    uuid.UUID     `json:"document_id"`
+	Title         string        `json:"title"`
+	ModelConfigID uuid.NullUUID `json:"model_config_id"`
+}
+
+func (q *Queries) CreateTutoringThread(ctx context.Context, arg CreateTutoringThreadParams) (TutoringThread, error) {
+	row := q.db.QueryRowContext(ctx, createTutoringThread,
+		arg.UserID,
+		arg.DocumentID,
+		arg.Title,
+		arg.ModelConfigID,
+	)
+	var i TutoringThread
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DocumentID,
+		&i.Title,
+		&i.ModelConfigID,
+		&i.Summary,
+		&i.SummarizedThrough,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTutoringThread = `-- name: GetTutoringThread :one
+SELECT id, user_id, document_id, title, model_config_id, summary, summarized_through, created_at, updated_at FROM tutoring_threads
+WHERE id = $1
+`
+
+func (q *Queries) GetTutoringThread(ctx context.Context, id uuid.UUID) (TutoringThread, error) {
+	row := q.db.QueryRowContext(ctx, getTutoringThread, id)
+	var i TutoringThread
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DocumentID,
+		&i.Title,
+		&i.ModelConfigID,
+		&i.Summary,
+		&i.SummarizedThrough,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listTutoringMessages = `-- name: ListTutoringMessages :many
+SELECT id, thread_id, seq, role, content, artifact_id, created_at FROM tutoring_messages
+WHERE thread_id = $1
+ORDER BY seq ASC
+`
+
+func (q *Queries) ListTutoringMessages(ctx context.Context, threadID uuid.UUID) ([]TutoringMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listTutoringMessages, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TutoringMessage
+	for rows.Next() {
+		var i TutoringMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ThreadID,
+			&i.Seq,
+			&i.Role,
+			&i.Content,
+			&i.ArtifactID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTutoringMessagesAfter = `-- name: ListTutoringMessagesAfter :many
+SELECT id, thread_id, seq, role, content, artifact_id, created_at FROM tutoring_messages
+WHERE thread_id = $1 AND seq > $2
+ORDER BY seq ASC
+`
+
+type ListTutoringMessagesAfterParams struct {
+	ThreadID uuid.UUID `json:"thread_id"`
+	AfterSeq int32     `json:"after_seq"`
+}
+
+func (q *Queries) ListTutoringMessagesAfter(ctx context.Context, arg ListTutoringMessagesAfterParams) ([]TutoringMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listTutoringMessagesAfter, arg.ThreadID, arg.AfterSeq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TutoringMessage
+	for rows.Next() {
+		var i TutoringMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ThreadID,
+			&i.Seq,
+			&i.Role,
+			&i.Content,
+			&i.ArtifactID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTutoringThreadsByUser = `-- name: ListTutoringThreadsByUser :many
+SELECT id, user_id, document_id, title, model_config_id, summary, summarized_through, created_at, updated_at FROM tutoring_threads
+WHERE user_id = $1
+  AND ($2::uuid IS NULL OR document_id = $2::uuid)
+ORDER BY updated_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type ListTutoringThreadsByUserParams struct {
+	UserID     uuid.UUID     `json:"user_id"`
+	DocumentID uuid.NullUUID `json:"document_id"`
+	PageOffset int32         `json:"page_offset"`
+	PageLimit  int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListTutoringThreadsByUser(ctx context.Context, arg ListTutoringThreadsByUserParams) ([]TutoringThread, error) {
+	rows, err := q.db.QueryContext(ctx, listTutoringThreadsByUser,
+		arg.UserID,
+		arg.DocumentID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TutoringThread
+	for rows.Next() {
+		var i TutoringThread
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DocumentID,
+			&i.Title,
+			&i.ModelConfigID,
+			&i.Summary,
+			&i.SummarizedThrough,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockTutoringThread = `-- name: LockTutoringThread :one
+SELECT id FROM tutoring_threads
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockTutoringThread(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, lockTutoringThread, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const touchTutoringThread = `-- name: TouchTutoringThread :exec
+UPDATE tutoring_threads SET updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchTutoringThread(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, touchTutoringThread, id)
+	return err
+}
+
+const updateTutoringThreadSummary = `-- name: UpdateTutoringThreadSummary :one
+UPDATE tutoring_threads
+SET summary = $2, summarized_through = $3
+WHERE id = $1
+RETURNING id, user_id, document_id, title, model_config_id, summary, summarized_through, created_at, updated_at
+`
+
+type UpdateTutoringThreadSummaryParams struct {
+	ID                uuid.UUID      `json:"id"`
+	Summary           sql.NullString `json:"summary"`
+	SummarizedThrough int32          `json:"summarized_through"`
+}
+
+func (q *Queries) UpdateTutoringThreadSummary(ctx context.Context, arg UpdateTutoringThreadSummaryParams) (TutoringThread, error) {
+	row := q.db.QueryRowContext(ctx, updateTutoringThreadSummary, arg.ID, arg.Summary, arg.SummarizedThrough)
+	var i TutoringThread
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DocumentID,
+		&i.Title,
+		&i.ModelConfigID,
+		&i.Summary,
+		&i.SummarizedThrough,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
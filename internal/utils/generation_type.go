@@ -7,6 +7,27 @@ type GenerationType string
 const GenerationTypeClassification GenerationType = "CLASSIFICATION"
 const GenerationTypeQuestions GenerationType = "QUESTIONS"
 const GenerationTypeSectionTopics GenerationType = "SECTION_TOPICS"
+const GenerationTypeFlashcards GenerationType = "FLASHCARDS"
+const GenerationTypeSummary GenerationType = "SUMMARY"
+const GenerationTypeOutline GenerationType = "OUTLINE"
+const GenerationTypeHint GenerationType = "HINT"
+const GenerationTypeMultipleChoice GenerationType = "MULTIPLE_CHOICE"
+const GenerationTypeTutoring GenerationType = "TUTORING"
+
+// GenerationTypes lists every generation type accepted by the generation_type enum.
+func GenerationTypes() []GenerationType {
+	return []GenerationType{
+		GenerationTypeClassification,
+		GenerationTypeQuestions,
+		GenerationTypeSectionTopics,
+		GenerationTypeFlashcards,
+		GenerationTypeSummary,
+		GenerationTypeOutline,
+		GenerationTypeHint,
+		GenerationTypeMultipleChoice,
+		GenerationTypeTutoring,
+	}
+}
 
 func (g GenerationType) String() string {
 	return string(g)
@@ -15,3 +36,30 @@ func (g GenerationType) String() string {
 func (g GenerationType) DB() store.GenerationType {
 	return store.GenerationType(g)
 }
+
+// IsValid reports whether g is a known generation type.
+func (g GenerationType) IsValid() bool {
+	for _, known := range GenerationTypes() {
+		if g == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ArtifactType returns the artifact type used for artifacts produced by g.
+// Types without a dedicated artifact type are stored as OTHER.
+func (g GenerationType) ArtifactType() store.ArtifactType {
+	switch g {
+	case GenerationTypeFlashcards:
+		return store.ArtifactTypeFLASHCARDS
+	case GenerationTypeSummary:
+		return store.ArtifactTypeSUMMARY
+	case GenerationTypeOutline:
+		return store.ArtifactTypeOUTLINE
+	case GenerationTypeHint:
+		return store.ArtifactTypeHINT
+	default:
+		return store.ArtifactTypeOTHER
+	}
+}